@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/cadyyan/void-tool/internal/backfill"
+	"github.com/cadyyan/void-tool/internal/bgtasks"
+	"github.com/cadyyan/void-tool/internal/configuration"
+	"github.com/cadyyan/void-tool/internal/services"
+	"github.com/spf13/cobra"
+)
+
+func newBackfillCommand() *cobra.Command {
+	var (
+		dryRun bool
+		world  string
+	)
+
+	command := cobra.Command{
+		Use:   "backfill <backups directory or git repository>",
+		Short: "Record player history from old backups of the data directory",
+		Long: `Record player history from old backups of the data directory.
+
+Backups are the directories, tar, tar.gz and zip archives in the given directory that have a
+date in their name (e.g. players-2024-03-01.tar.gz). If the directory is a git repository of
+saves then the last commit of each day is used instead.
+
+Each backup is recorded under its date. Players that already have skills recorded for that day
+are left alone so newer data is never replaced.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			config, err := configuration.NewConfigurationFromEnv()
+			if err != nil {
+				return fmt.Errorf("unable to prepare backfill: %w", err)
+			}
+
+			if world == "" {
+				world = config.RS.World
+			}
+
+			if _, err := worldDataDir(config, world); err != nil {
+				return err
+			}
+
+			snapshots, err := backfill.FindSnapshots(args[0])
+			if err != nil {
+				return fmt.Errorf("unable to find backups: %w", err)
+			}
+
+			logger := config.Logging.BuildLogger()
+
+			shutdownTracing, err := setupTracing(ctx, logger, config)
+			if err != nil {
+				return err
+			}
+			defer shutdownTracing()
+
+			storageService, closeStorage, err := openStorage(ctx, logger, config)
+			if err != nil {
+				return err
+			}
+			defer closeStorage()
+
+			out := cmd.OutOrStdout()
+
+			for _, snapshot := range snapshots {
+				summary, err := backfillSnapshot(ctx, logger, storageService, world, snapshot, dryRun)
+				if err != nil {
+					// One bad backup shouldn't stop the rest from being recorded
+					fmt.Fprintf(out, "%s %s: %s\n", snapshot.Date.Format(time.DateOnly), snapshot.Name, err)
+
+					continue
+				}
+
+				printBackfillSummary(out, snapshot, summary, dryRun)
+			}
+
+			return nil
+		},
+	}
+
+	command.Flags().BoolVar(
+		&dryRun,
+		"dry-run",
+		false,
+		"print what would be recorded without recording anything",
+	)
+	command.Flags().StringVar(
+		&world,
+		"world",
+		"",
+		"record the backups in this world (default VOID_RS_WORLD)",
+	)
+
+	return &command
+}
+
+func backfillSnapshot(
+	ctx context.Context,
+	logger *slog.Logger,
+	storageService services.StorageService,
+	world string,
+	snapshot backfill.Snapshot,
+	dryRun bool,
+) (bgtasks.IngestSummary, error) {
+	saves, closeSnapshot, err := snapshot.Open()
+	if err != nil {
+		return bgtasks.IngestSummary{}, fmt.Errorf("unable to open backup: %w", err)
+	}
+	defer closeSnapshot()
+
+	summary, err := bgtasks.IngestPlayers(
+		ctx,
+		logger.With(slog.String("backup", snapshot.Name)),
+		storageService,
+		services.NewVoidPlayerFileService(saves),
+		bgtasks.IngestOptions{
+			World:            world,
+			Date:             snapshot.Date,
+			DryRun:           dryRun,
+			TrackSaveFiles:   false,
+			RecordRun:        false,
+			PreserveExisting: true,
+		},
+	)
+	if err != nil {
+		return bgtasks.IngestSummary{}, fmt.Errorf("unable to ingest backup: %w", err)
+	}
+
+	return summary, nil
+}
+
+func printBackfillSummary(
+	out io.Writer,
+	snapshot backfill.Snapshot,
+	summary bgtasks.IngestSummary,
+	dryRun bool,
+) {
+	verb := "recorded"
+	if dryRun {
+		verb = "would record"
+	}
+
+	fmt.Fprintf(
+		out,
+		"%s %s: %s %d of %d players with %d failures\n",
+		snapshot.Date.Format(time.DateOnly),
+		snapshot.Name,
+		verb,
+		len(summary.Changes),
+		summary.FilesScanned,
+		len(summary.Failures),
+	)
+
+	for _, failure := range summary.Failures {
+		fmt.Fprintf(out, "  %s: %s\n", failure.File, failure.Error)
+	}
+}
@@ -10,6 +10,9 @@ func NewRootCommand() *cobra.Command {
 
 	command.AddCommand(
 		newServeCommand(),
+		newIngestCommand(),
+		newBackfillCommand(),
+		newRetentionCommand(),
 	)
 
 	return &command
@@ -0,0 +1,262 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cadyyan/void-tool/internal/bgtasks"
+	"github.com/cadyyan/void-tool/internal/configuration"
+	"github.com/cadyyan/void-tool/internal/services"
+	"github.com/spf13/cobra"
+)
+
+var errInvalidIngestDate = errors.New("invalid date")
+
+func newIngestCommand() *cobra.Command {
+	var (
+		date   string
+		dryRun bool
+		world  string
+	)
+
+	command := cobra.Command{
+		Use:   "ingest [data directory, save file or glob]",
+		Short: "Ingest player saves once",
+		Long: `Ingest player saves once without starting the HTTP server.
+
+By default every player is ingested from the configured source, either the data directory or
+Void's database. A directory, a single save file or a glob of save files
+(e.g. "backups/players/z*.toml") can be given instead.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			config, err := configuration.NewConfigurationFromEnv()
+			if err != nil {
+				return fmt.Errorf("unable to prepare ingestion: %w", err)
+			}
+
+			var snapshotDate time.Time
+			if date != "" {
+				snapshotDate, err = time.Parse(time.DateOnly, date)
+				if err != nil {
+					return fmt.Errorf("%w %q, expected YYYY-MM-DD: %w", errInvalidIngestDate, date, err)
+				}
+			}
+
+			logger := config.Logging.BuildLogger()
+
+			shutdownTracing, err := setupTracing(ctx, logger, config)
+			if err != nil {
+				return err
+			}
+			defer shutdownTracing()
+
+			storageService, closeStorage, err := openStorage(ctx, logger, config)
+			if err != nil {
+				return err
+			}
+			defer closeStorage()
+
+			if world == "" {
+				world = config.RS.World
+			}
+
+			dataDir, err := worldDataDir(config, world)
+			if err != nil {
+				return err
+			}
+
+			options := bgtasks.IngestOptions{
+				World:  world,
+				Date:   snapshotDate,
+				DryRun: dryRun,
+				// Save file states only describe the configured source as it is now so they
+				// aren't touched when ingesting anything else
+				TrackSaveFiles:   snapshotDate.IsZero(),
+				RecordRun:        true,
+				PreserveExisting: false,
+			}
+
+			if len(args) > 0 {
+				targetDir, pattern, err := resolveIngestTarget(args[0])
+				if err != nil {
+					return err
+				}
+
+				options.TrackSaveFiles = options.TrackSaveFiles &&
+					dataDir != "" &&
+					pattern == services.DefaultSaveFilePattern &&
+					sameDirectory(targetDir, dataDir)
+
+				return ingestWorld(
+					cmd,
+					logger,
+					storageService,
+					services.NewVoidPlayerFileServiceWithPattern(os.DirFS(targetDir), pattern),
+					options,
+				)
+			}
+
+			worlds, closeWorlds, err := openWorlds(ctx, logger, config)
+			if err != nil {
+				return err
+			}
+			defer closeWorlds()
+
+			// Every world is ingested unless one was asked for
+			for _, voidWorld := range worlds {
+				if cmd.Flags().Changed("world") && voidWorld.Name != world {
+					continue
+				}
+
+				if len(worlds) > 1 {
+					fmt.Fprintf(cmd.OutOrStdout(), "World %s:\n", voidWorld.Name)
+				}
+
+				options.World = voidWorld.Name
+
+				err := ingestWorld(cmd, logger, storageService, voidWorld.Players, options)
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	}
+
+	command.Flags().StringVar(
+		&date,
+		"date",
+		"",
+		"record skills under this day (YYYY-MM-DD) instead of today",
+	)
+	command.Flags().BoolVar(
+		&dryRun,
+		"dry-run",
+		false,
+		"print what would change without recording anything",
+	)
+	command.Flags().StringVar(
+		&world,
+		"world",
+		"",
+		"only ingest this world, and record saves given as an argument in it (default VOID_RS_WORLD)",
+	)
+
+	return &command
+}
+
+func ingestWorld(
+	cmd *cobra.Command,
+	logger *slog.Logger,
+	storageService services.StorageService,
+	voidPlayerService services.VoidPlayerService,
+	options bgtasks.IngestOptions,
+) error {
+	summary, err := bgtasks.IngestPlayers(
+		cmd.Context(),
+		logger,
+		storageService,
+		voidPlayerService,
+		options,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to ingest players: %w", err)
+	}
+
+	printIngestSummary(cmd.OutOrStdout(), summary, options.DryRun)
+
+	return nil
+}
+
+// resolveIngestTarget splits the target into the directory to read saves from and the pattern
+// of saves to read within it.
+func resolveIngestTarget(target string) (string, string, error) {
+	info, err := os.Stat(target)
+	if err == nil && info.IsDir() {
+		return target, services.DefaultSaveFilePattern, nil
+	} else if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", "", fmt.Errorf("unable to read %s: %w", target, err)
+	}
+
+	// Anything else is treated as a save file or a glob of them
+	pattern := filepath.Base(target)
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return "", "", fmt.Errorf("invalid save file pattern %q: %w", pattern, err)
+	}
+
+	return filepath.Dir(target), pattern, nil
+}
+
+func sameDirectory(first string, second string) bool {
+	firstPath, err := filepath.Abs(first)
+	if err != nil {
+		return false
+	}
+
+	secondPath, err := filepath.Abs(second)
+	if err != nil {
+		return false
+	}
+
+	return firstPath == secondPath
+}
+
+func printIngestSummary(out io.Writer, summary bgtasks.IngestSummary, dryRun bool) {
+	verb := "Updated"
+	if dryRun {
+		verb = "Would update"
+	}
+
+	for _, change := range summary.Changes {
+		switch {
+		case change.RenamedFrom != "":
+			fmt.Fprintf(out, "%s (%s): renamed from %s\n", change.Username, change.File, change.RenamedFrom)
+		case change.NewPlayer:
+			fmt.Fprintf(out, "%s (%s): new player\n", change.Username, change.File)
+		default:
+			fmt.Fprintf(out, "%s (%s):\n", change.Username, change.File)
+		}
+
+		if len(change.Skills) == 0 {
+			fmt.Fprintln(out, "  no skills changed")
+		}
+
+		for _, skill := range services.SkillOrder {
+			skillChange, ok := change.Skills[skill]
+			if !ok {
+				continue
+			}
+
+			fmt.Fprintf(
+				out,
+				"  %-14s level %d -> %d, experience %.1f -> %.1f\n",
+				skill,
+				skillChange.Before.Level,
+				skillChange.After.Level,
+				skillChange.Before.Experience,
+				skillChange.After.Experience,
+			)
+		}
+	}
+
+	for _, failure := range summary.Failures {
+		fmt.Fprintf(out, "%s: %s\n", failure.File, failure.Error)
+	}
+
+	fmt.Fprintf(
+		out,
+		"%s %d players from %d files with %d failures\n",
+		verb,
+		len(summary.Changes),
+		summary.FilesScanned,
+		len(summary.Failures),
+	)
+}
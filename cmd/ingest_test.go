@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cadyyan/void-tool/internal/bgtasks"
+	"github.com/cadyyan/void-tool/internal/services"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintIngestSummaryListsNonCombatSkills(t *testing.T) {
+	t.Parallel()
+
+	summary := bgtasks.IngestSummary{
+		FilesScanned: 1,
+		Changes: []bgtasks.PlayerChange{
+			{
+				Username:    "zezima",
+				File:        "zezima.toml",
+				NewPlayer:   false,
+				RenamedFrom: "",
+				Skills: map[string]bgtasks.SkillChange{
+					"Woodcutting": {
+						Before: services.PlayerSkillRecord{Level: 10, Experience: 1154},
+						After:  services.PlayerSkillRecord{Level: 11, Experience: 1358},
+					},
+				},
+			},
+		},
+		Failures: nil,
+	}
+
+	var out strings.Builder
+
+	printIngestSummary(&out, summary, true)
+
+	require.Equal(
+		t,
+		"zezima (zezima.toml):\n"+
+			"  Woodcutting    level 10 -> 11, experience 1154.0 -> 1358.0\n"+
+			"Would update 1 players from 1 files with 0 failures\n",
+		out.String(),
+	)
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+
+	"github.com/cadyyan/void-tool/internal/configuration"
+	"github.com/cadyyan/void-tool/internal/database/voiddb"
+	"github.com/cadyyan/void-tool/internal/logging"
+	"github.com/cadyyan/void-tool/internal/services"
+)
+
+var (
+	errDataDirRequired     = errors.New("VOID_RS_DATADIR is required when reading players from save files")
+	errDatabaseURLRequired = errors.New("VOID_RS_DATABASEURL is required when reading players from Void's database")
+	errInvalidWorldName    = errors.New("world names may only contain lowercase letters, numbers and dashes")
+	errDuplicateWorld      = errors.New("world is configured more than once")
+	errWorldDataDirMissing = errors.New("world needs a data directory")
+	errUnknownWorld        = errors.New("unknown world")
+)
+
+// World names are used in URLs so they're kept simple
+var worldNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// openWorlds returns every configured world, starting with the one read from the configured
+// player source, along with a function to close any connections they needed.
+func openWorlds(
+	ctx context.Context,
+	logger *slog.Logger,
+	config configuration.Configuration,
+) ([]services.VoidWorld, func(), error) {
+	if !worldNamePattern.MatchString(config.RS.World) {
+		return nil, nil, fmt.Errorf("%w: %q", errInvalidWorldName, config.RS.World)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(config.RS.Worlds)) {
+		switch {
+		case !worldNamePattern.MatchString(name):
+			return nil, nil, fmt.Errorf("%w: %q", errInvalidWorldName, name)
+		case name == config.RS.World:
+			return nil, nil, fmt.Errorf("%w: %q", errDuplicateWorld, name)
+		case config.RS.Worlds[name] == "":
+			return nil, nil, fmt.Errorf("%w: %q", errWorldDataDirMissing, name)
+		}
+	}
+
+	playerService, closePlayers, err := openPlayerService(ctx, logger, config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dataDir, _ := config.RS.WorldDataDir(config.RS.World)
+
+	worlds := []services.VoidWorld{
+		{
+			Name:    config.RS.World,
+			Players: playerService,
+			DataDir: dataDir,
+		},
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(config.RS.Worlds)) {
+		dataDir := config.RS.Worlds[name]
+
+		worlds = append(worlds, services.VoidWorld{
+			Name:    name,
+			Players: services.NewVoidPlayerFileService(os.DirFS(dataDir)),
+			DataDir: dataDir,
+		})
+	}
+
+	return worlds, closePlayers, nil
+}
+
+// worldDataDir is the data directory of a configured world. It's empty for a world that's read
+// from Void's database.
+func worldDataDir(config configuration.Configuration, world string) (string, error) {
+	dataDir, ok := config.RS.WorldDataDir(world)
+	if !ok {
+		return "", fmt.Errorf("%w %q", errUnknownWorld, world)
+	}
+
+	return dataDir, nil
+}
+
+// openPlayerService returns the service for wherever Void keeps its players along with a
+// function to close any connection it needed.
+func openPlayerService(
+	ctx context.Context,
+	logger *slog.Logger,
+	config configuration.Configuration,
+) (services.VoidPlayerService, func(), error) {
+	switch config.RS.Source {
+	case configuration.PlayerSourceDatabase:
+		if config.RS.DatabaseURL == "" {
+			return nil, nil, errDatabaseURLRequired
+		}
+
+		logger.DebugContext(ctx, "Opening connection pool to Void database")
+
+		// Void's database is only ever read so it doesn't need migrating
+		pool, err := configuration.PostgresConfiguration{URL: config.RS.DatabaseURL}.Connect(ctx)
+		if err != nil {
+			logger.ErrorContext(ctx, "Unable to connect to Void database", logging.Err(err))
+
+			return nil, nil, fmt.Errorf("unable to connect to Void database: %w", err)
+		}
+
+		return services.NewVoidPlayerDatabaseService(voiddb.New(pool)), pool.Close, nil
+
+	case configuration.PlayerSourceFiles:
+		if config.RS.DataDir == "" {
+			return nil, nil, errDataDirRequired
+		}
+
+		return services.NewVoidPlayerFileService(config.RS.DataDirFS()), func() {}, nil
+	}
+
+	return nil, nil, fmt.Errorf("unsupported player source %q", config.RS.Source)
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/cadyyan/void-tool/internal/configuration"
+	"github.com/cadyyan/void-tool/internal/retention"
+	"github.com/spf13/cobra"
+)
+
+func newRetentionCommand() *cobra.Command {
+	var dryRun bool
+
+	command := cobra.Command{
+		Use:   "retention",
+		Short: "Downsample old player history",
+		Long: `Downsample old player history using the configured retention policy.
+
+Days that are identical to the day before them are removed. Days older than
+VOID_RETENTION_DAILYHISTORY are reduced to the last day of each week and days older than
+VOID_RETENTION_WEEKLYHISTORY to the last day of each month. The same policy is applied by the
+server every VOID_RETENTION_DOWNSAMPLEFREQUENCY.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			config, err := configuration.NewConfigurationFromEnv()
+			if err != nil {
+				return fmt.Errorf("unable to prepare retention: %w", err)
+			}
+
+			logger := config.Logging.BuildLogger()
+
+			shutdownTracing, err := setupTracing(ctx, logger, config)
+			if err != nil {
+				return err
+			}
+			defer shutdownTracing()
+
+			storageService, closeStorage, err := openStorage(ctx, logger, config)
+			if err != nil {
+				return err
+			}
+			defer closeStorage()
+
+			summary, err := retention.Apply(
+				ctx,
+				logger,
+				storageService,
+				config.Retention.Policy(),
+				dryRun,
+			)
+			if err != nil {
+				return fmt.Errorf("unable to apply retention policy: %w", err)
+			}
+
+			verb := "Removed"
+			if dryRun {
+				verb = "Would remove"
+			}
+
+			fmt.Fprintf(
+				cmd.OutOrStdout(),
+				"%s %d redundant and %d downsampled days from %d of %d players\n",
+				verb,
+				summary.Redundant,
+				summary.Downsampled,
+				summary.PlayersChanged,
+				summary.PlayersChecked,
+			)
+
+			return nil
+		},
+	}
+
+	command.Flags().BoolVar(
+		&dryRun,
+		"dry-run",
+		false,
+		"print what would be removed without removing anything",
+	)
+
+	return &command
+}
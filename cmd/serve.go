@@ -2,22 +2,12 @@ package cmd
 
 import (
 	"context"
-	"database/sql"
-	"errors"
 	"fmt"
-	"log/slog"
 
-	"github.com/cadyyan/void-tool/database/sqlite"
 	"github.com/cadyyan/void-tool/internal"
 	"github.com/cadyyan/void-tool/internal/configuration"
-	"github.com/cadyyan/void-tool/internal/database/sqlitedb"
 	"github.com/cadyyan/void-tool/internal/logging"
-	"github.com/cadyyan/void-tool/internal/services"
-	"github.com/golang-migrate/migrate/v4"
-	migrateSQLiteDriver "github.com/golang-migrate/migrate/v4/database/sqlite"
-	"github.com/golang-migrate/migrate/v4/source/iofs"
 	"github.com/spf13/cobra"
-	_ "modernc.org/sqlite"
 )
 
 func newServeCommand() *cobra.Command {
@@ -35,40 +25,33 @@ func newServeCommand() *cobra.Command {
 			logger := config.Logging.BuildLogger()
 			logger.DebugContext(ctx, "Configuration loaded")
 
-			logger.DebugContext(ctx, "Opening connection to SQLite database")
+			logger.DebugContext(ctx, "Setting up services")
 
-			sqliteConnection, err := config.SQLite.Connect()
+			shutdownTracing, err := setupTracing(ctx, logger, config)
 			if err != nil {
-				logger.ErrorContext(ctx, "Unable to connect to SQLite database", logging.Err(err))
-
-				return fmt.Errorf("unable to connect to SQLite database: %w", err)
+				return err
 			}
-			defer sqliteConnection.Close()
+			defer shutdownTracing()
 
-			err = runSQLiteMigrations(
-				ctx,
-				logger,
-				sqliteConnection,
-			)
+			storageService, closeStorage, err := openStorage(ctx, logger, config)
 			if err != nil {
 				return err
 			}
+			defer closeStorage()
 
-			queries := sqlitedb.New(sqliteConnection)
-
-			logger.DebugContext(ctx, "Setting up services")
-			storageService := services.NewStorageSQLiteService(sqliteConnection, queries)
-			voidPlayerService := services.NewVoidPlayerFileService(config.RS.DataDirFS())
+			worlds, closeWorlds, err := openWorlds(ctx, logger, config)
+			if err != nil {
+				return err
+			}
+			defer closeWorlds()
 
 			logger.DebugContext(ctx, "Setting up server")
 
 			server, err := internal.NewServer(
 				logger,
 				config,
-				sqliteConnection,
-				queries,
 				storageService,
-				voidPlayerService,
+				worlds,
 			)
 			if err != nil {
 				return fmt.Errorf("unable to setup server: %w", err)
@@ -109,54 +92,3 @@ func newServeCommand() *cobra.Command {
 
 	return &command
 }
-
-func runSQLiteMigrations(
-	ctx context.Context,
-	logger *slog.Logger,
-	dbConn *sql.DB,
-) error {
-	logger.DebugContext(ctx, "Applying database migrations")
-
-	sourceDriver, err := iofs.New(sqlite.MigrationsFS, "migrations")
-	if err != nil {
-		logger.ErrorContext(ctx, "Unable to get migrations", logging.Err(err))
-
-		return fmt.Errorf("unable to get migrations: %w", err)
-	}
-
-	dbDriver, err := migrateSQLiteDriver.WithInstance(dbConn, &migrateSQLiteDriver.Config{})
-	if err != nil {
-		logger.ErrorContext(ctx, "Unable to create migration database driver", logging.Err(err))
-
-		return fmt.Errorf("unable to create migration database driver: %w", err)
-	}
-
-	migrator, err := migrate.NewWithInstance(
-		"iofs",
-		sourceDriver,
-		"sqlite",
-		dbDriver,
-	)
-	if err != nil {
-		logger.ErrorContext(
-			ctx,
-			"Unable to setup SQLite migrator",
-			logging.Err(err),
-		)
-
-		return fmt.Errorf("unable to setup SQLite migrator: %w", err)
-	}
-
-	err = migrator.Up()
-	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
-		logger.ErrorContext(
-			ctx,
-			"Unable to apply migrations to SQLite",
-			logging.Err(err),
-		)
-
-		return fmt.Errorf("unable to apply migrations to SQLite: %w", err)
-	}
-
-	return nil
-}
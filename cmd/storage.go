@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/cadyyan/void-tool/database/postgres"
+	"github.com/cadyyan/void-tool/database/sqlite"
+	"github.com/cadyyan/void-tool/internal/configuration"
+	"github.com/cadyyan/void-tool/internal/database/postgresdb"
+	"github.com/cadyyan/void-tool/internal/database/sqlitedb"
+	"github.com/cadyyan/void-tool/internal/logging"
+	"github.com/cadyyan/void-tool/internal/metrics"
+	"github.com/cadyyan/void-tool/internal/services"
+	"github.com/golang-migrate/migrate/v4"
+	migratePgxDriver "github.com/golang-migrate/migrate/v4/database/pgx/v5"
+	migrateSQLiteDriver "github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+)
+
+var (
+	errSQLitePathRequired  = errors.New("VOID_SQLITE_PATH is required when using the SQLite storage driver")
+	errPostgresURLRequired = errors.New("VOID_POSTGRES_URL is required when using the PostgreSQL storage driver")
+)
+
+// openStorage connects to the configured database, applies any pending migrations and returns
+// the storage service for it along with a function to close the connection.
+func openStorage(
+	ctx context.Context,
+	logger *slog.Logger,
+	config configuration.Configuration,
+) (services.StorageService, func(), error) {
+	switch config.Storage.Driver {
+	case configuration.StorageDriverPostgres:
+		pool, err := connectPostgres(ctx, logger, config)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return services.NewTracedStorageService(
+			services.NewStoragePostgresService(pool, postgresdb.New(pool)),
+		), pool.Close, nil
+
+	case configuration.StorageDriverSQLite:
+		sqliteConnection, err := connectSQLite(ctx, logger, config)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		closeSQLite := func() {
+			sqliteConnection.Close()
+		}
+
+		if err := metrics.RegisterDBStats(sqliteConnection, metrics.DatabaseSQLite); err != nil {
+			closeSQLite()
+
+			return nil, nil, err
+		}
+
+		return services.NewTracedStorageService(services.NewStorageSQLiteService(
+			sqliteConnection,
+			sqlitedb.New(metrics.ObserveQueries(sqliteConnection, metrics.DatabaseSQLite)),
+		)), closeSQLite, nil
+	}
+
+	return nil, nil, fmt.Errorf("unsupported storage driver %q", config.Storage.Driver)
+}
+
+// connectSQLite opens the SQLite database and makes sure it's up to date.
+func connectSQLite(
+	ctx context.Context,
+	logger *slog.Logger,
+	config configuration.Configuration,
+) (*sql.DB, error) {
+	if config.SQLite.Path == "" {
+		return nil, errSQLitePathRequired
+	}
+
+	logger.DebugContext(ctx, "Opening connection to SQLite database")
+
+	sqliteConnection, err := config.SQLite.Connect()
+	if err != nil {
+		logger.ErrorContext(ctx, "Unable to connect to SQLite database", logging.Err(err))
+
+		return nil, fmt.Errorf("unable to connect to SQLite database: %w", err)
+	}
+
+	if err := runSQLiteMigrations(ctx, logger, sqliteConnection); err != nil {
+		sqliteConnection.Close()
+
+		return nil, err
+	}
+
+	return sqliteConnection, nil
+}
+
+func runSQLiteMigrations(
+	ctx context.Context,
+	logger *slog.Logger,
+	dbConn *sql.DB,
+) error {
+	logger.DebugContext(ctx, "Applying database migrations")
+
+	sourceDriver, err := iofs.New(sqlite.MigrationsFS, "migrations")
+	if err != nil {
+		logger.ErrorContext(ctx, "Unable to get migrations", logging.Err(err))
+
+		return fmt.Errorf("unable to get migrations: %w", err)
+	}
+
+	dbDriver, err := migrateSQLiteDriver.WithInstance(dbConn, &migrateSQLiteDriver.Config{})
+	if err != nil {
+		logger.ErrorContext(ctx, "Unable to create migration database driver", logging.Err(err))
+
+		return fmt.Errorf("unable to create migration database driver: %w", err)
+	}
+
+	migrator, err := migrate.NewWithInstance(
+		"iofs",
+		sourceDriver,
+		"sqlite",
+		dbDriver,
+	)
+	if err != nil {
+		logger.ErrorContext(
+			ctx,
+			"Unable to setup SQLite migrator",
+			logging.Err(err),
+		)
+
+		return fmt.Errorf("unable to setup SQLite migrator: %w", err)
+	}
+
+	err = migrator.Up()
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		logger.ErrorContext(
+			ctx,
+			"Unable to apply migrations to SQLite",
+			logging.Err(err),
+		)
+
+		return fmt.Errorf("unable to apply migrations to SQLite: %w", err)
+	}
+
+	return nil
+}
+
+// connectPostgres opens a connection pool to PostgreSQL and makes sure the schema is up to date.
+func connectPostgres(
+	ctx context.Context,
+	logger *slog.Logger,
+	config configuration.Configuration,
+) (*pgxpool.Pool, error) {
+	if config.Postgres.URL == "" {
+		return nil, errPostgresURLRequired
+	}
+
+	logger.DebugContext(ctx, "Opening connection pool to PostgreSQL database")
+
+	pool, err := config.Postgres.Connect(ctx)
+	if err != nil {
+		logger.ErrorContext(ctx, "Unable to connect to PostgreSQL database", logging.Err(err))
+
+		return nil, fmt.Errorf("unable to connect to PostgreSQL database: %w", err)
+	}
+
+	if err := runPostgresMigrations(ctx, logger, pool); err != nil {
+		pool.Close()
+
+		return nil, err
+	}
+
+	return pool, nil
+}
+
+func runPostgresMigrations(
+	ctx context.Context,
+	logger *slog.Logger,
+	pool *pgxpool.Pool,
+) error {
+	logger.DebugContext(ctx, "Applying database migrations")
+
+	sourceDriver, err := iofs.New(postgres.MigrationsFS, "migrations")
+	if err != nil {
+		logger.ErrorContext(ctx, "Unable to get migrations", logging.Err(err))
+
+		return fmt.Errorf("unable to get migrations: %w", err)
+	}
+
+	// The migration driver only works with database/sql so it gets its own handle on the pool
+	dbDriver, err := migratePgxDriver.WithInstance(stdlib.OpenDBFromPool(pool), &migratePgxDriver.Config{})
+	if err != nil {
+		logger.ErrorContext(ctx, "Unable to create migration database driver", logging.Err(err))
+
+		return fmt.Errorf("unable to create migration database driver: %w", err)
+	}
+
+	migrator, err := migrate.NewWithInstance(
+		"iofs",
+		sourceDriver,
+		"pgx5",
+		dbDriver,
+	)
+	if err != nil {
+		logger.ErrorContext(
+			ctx,
+			"Unable to setup PostgreSQL migrator",
+			logging.Err(err),
+		)
+
+		return fmt.Errorf("unable to setup PostgreSQL migrator: %w", err)
+	}
+	// Closing the migrator releases the connection it holds without closing the pool
+	defer migrator.Close()
+
+	err = migrator.Up()
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		logger.ErrorContext(
+			ctx,
+			"Unable to apply migrations to PostgreSQL",
+			logging.Err(err),
+		)
+
+		return fmt.Errorf("unable to apply migrations to PostgreSQL: %w", err)
+	}
+
+	return nil
+}
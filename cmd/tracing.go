@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/cadyyan/void-tool/internal/configuration"
+	"github.com/cadyyan/void-tool/internal/logging"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// setupTracing sends traces to the configured exporter. The returned function sends any traces
+// that haven't been sent yet and has to be called before exiting.
+func setupTracing(
+	ctx context.Context,
+	logger *slog.Logger,
+	config configuration.Configuration,
+) (func(), error) {
+	if !config.Tracing.Enabled() {
+		return func() {}, nil
+	}
+
+	logger.DebugContext(ctx, "Setting up tracing", slog.String("exporter", string(config.Tracing.Exporter)))
+
+	provider, err := config.Tracing.BuildTracerProvider(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to set up tracing: %w", err)
+	}
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	shutdown := func() {
+		// The command's context is usually cancelled by now but the last traces still need to
+		// be sent
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), tracingShutdownTimeout)
+		defer cancel()
+
+		if err := provider.Shutdown(shutdownCtx); err != nil {
+			logger.ErrorContext(shutdownCtx, "Unable to send remaining traces", logging.Err(err))
+		}
+	}
+
+	return shutdown, nil
+}
+
+const tracingShutdownTimeout = 10 * time.Second
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS player_skills (
+    player_id VARCHAR NOT NULL REFERENCES players (id),
+    name VARCHAR NOT NULL,
+    day DATE NOT NULL,
+    level INTEGER NOT NULL CHECK (level >= 1),
+    experience DOUBLE PRECISION NOT NULL CHECK (experience >= 0),
+
+    PRIMARY KEY (player_id, name, day)
+);
+
+CREATE INDEX IF NOT EXISTS idx__player_skills__lookup ON player_skills (
+    player_id,
+    name
+);
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS player_combat_levels (
+    player_id VARCHAR NOT NULL REFERENCES players (id),
+    day DATE NOT NULL,
+    level INTEGER NOT NULL CHECK (level >= 3),
+
+    PRIMARY KEY (player_id, day)
+);
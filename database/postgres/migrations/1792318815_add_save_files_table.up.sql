@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS save_files (
+    path VARCHAR PRIMARY KEY NOT NULL,
+    modified_on TIMESTAMPTZ NOT NULL,
+    size BIGINT NOT NULL CHECK (size >= 0),
+    hash VARCHAR NOT NULL
+);
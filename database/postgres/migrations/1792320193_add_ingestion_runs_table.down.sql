@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS ingestion_run_failures;
+DROP TABLE IF EXISTS ingestion_runs;
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS ingestion_runs (
+    id VARCHAR PRIMARY KEY NOT NULL,
+    started_on TIMESTAMPTZ NOT NULL,
+    finished_on TIMESTAMPTZ,
+    files_scanned INTEGER NOT NULL DEFAULT 0 CHECK (files_scanned >= 0),
+    players_updated INTEGER NOT NULL DEFAULT 0 CHECK (players_updated >= 0),
+    error VARCHAR
+);
+
+CREATE INDEX IF NOT EXISTS idx__ingestion_runs__started_on ON ingestion_runs (
+    started_on
+);
+
+CREATE TABLE IF NOT EXISTS ingestion_run_failures (
+    run_id VARCHAR NOT NULL REFERENCES ingestion_runs (id) ON DELETE CASCADE,
+    file VARCHAR NOT NULL,
+    error VARCHAR NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx__ingestion_run_failures__run_id ON ingestion_run_failures (
+    run_id
+);
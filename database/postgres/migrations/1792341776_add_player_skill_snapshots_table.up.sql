@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS player_skill_snapshots (
+    player_id VARCHAR NOT NULL REFERENCES players (id),
+    name VARCHAR NOT NULL,
+    recorded_on TIMESTAMPTZ NOT NULL,
+    level INTEGER NOT NULL CHECK (level >= 1),
+    experience DOUBLE PRECISION NOT NULL CHECK (experience >= 0),
+
+    PRIMARY KEY (player_id, name, recorded_on)
+);
+
+CREATE INDEX IF NOT EXISTS idx__player_skill_snapshots__recorded_on ON player_skill_snapshots (
+    recorded_on
+);
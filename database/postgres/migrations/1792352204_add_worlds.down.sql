@@ -0,0 +1,32 @@
+-- Players from every world but the main one are removed along with everything recorded for them
+DELETE FROM player_skill_snapshots
+WHERE player_id IN (SELECT id FROM players WHERE world != 'main');
+
+DELETE FROM player_combat_levels
+WHERE player_id IN (SELECT id FROM players WHERE world != 'main');
+
+DELETE FROM player_skills
+WHERE player_id IN (SELECT id FROM players WHERE world != 'main');
+
+DELETE FROM players
+WHERE world != 'main';
+
+ALTER TABLE players DROP CONSTRAINT players_world_username_key;
+ALTER TABLE players ADD CONSTRAINT players_username_key UNIQUE (username);
+ALTER TABLE players DROP COLUMN world;
+
+DELETE FROM save_files
+WHERE world != 'main';
+
+ALTER TABLE save_files DROP CONSTRAINT save_files_pkey;
+ALTER TABLE save_files ADD PRIMARY KEY (path);
+ALTER TABLE save_files DROP COLUMN world;
+
+DELETE FROM quarantined_save_files
+WHERE world != 'main';
+
+ALTER TABLE quarantined_save_files DROP CONSTRAINT quarantined_save_files_pkey;
+ALTER TABLE quarantined_save_files ADD PRIMARY KEY (path);
+ALTER TABLE quarantined_save_files DROP COLUMN world;
+
+ALTER TABLE ingestion_runs DROP COLUMN world;
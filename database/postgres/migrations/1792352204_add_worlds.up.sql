@@ -0,0 +1,14 @@
+-- Usernames are only unique within a world
+ALTER TABLE players ADD COLUMN world VARCHAR NOT NULL DEFAULT 'main';
+ALTER TABLE players DROP CONSTRAINT players_username_key;
+ALTER TABLE players ADD CONSTRAINT players_world_username_key UNIQUE (world, username);
+
+ALTER TABLE save_files ADD COLUMN world VARCHAR NOT NULL DEFAULT 'main';
+ALTER TABLE save_files DROP CONSTRAINT save_files_pkey;
+ALTER TABLE save_files ADD PRIMARY KEY (world, path);
+
+ALTER TABLE quarantined_save_files ADD COLUMN world VARCHAR NOT NULL DEFAULT 'main';
+ALTER TABLE quarantined_save_files DROP CONSTRAINT quarantined_save_files_pkey;
+ALTER TABLE quarantined_save_files ADD PRIMARY KEY (world, path);
+
+ALTER TABLE ingestion_runs ADD COLUMN world VARCHAR NOT NULL DEFAULT 'main';
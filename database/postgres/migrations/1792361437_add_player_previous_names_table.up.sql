@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS player_previous_names (
+    player_id VARCHAR NOT NULL REFERENCES players (id),
+    username VARCHAR NOT NULL,
+    renamed_on TIMESTAMPTZ NOT NULL,
+
+    PRIMARY KEY (player_id, username)
+);
+
+CREATE INDEX IF NOT EXISTS idx__player_previous_names__username ON player_previous_names (
+    username
+);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx__player_previous_names__normalized_username;
+ALTER TABLE player_previous_names DROP COLUMN IF EXISTS normalized_username;
+
+DROP INDEX IF EXISTS idx__players__world__normalized_username;
+ALTER TABLE players DROP COLUMN IF EXISTS normalized_username;
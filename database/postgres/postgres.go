@@ -0,0 +1,8 @@
+package postgres
+
+import (
+	"embed"
+)
+
+//go:embed migrations/*.sql
+var MigrationsFS embed.FS
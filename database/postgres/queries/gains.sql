@@ -0,0 +1,259 @@
+-- The start of a period is the last snapshot on or before its first day. Players that were
+-- first seen during the period start from their earliest snapshot instead.
+
+-- name: GetPlayerSkillGainsByPlayerName :many
+WITH player_rows AS (
+    SELECT
+        player_skills.name,
+        player_skills.day,
+        player_skills.experience,
+        player_skills.level,
+        player_skills.day > sqlc.arg(from_day) AS after_start
+    FROM player_skills
+    INNER JOIN players
+        ON
+            player_skills.player_id = players.id
+            AND
+            players.world = sqlc.arg(world)
+            AND
+            players.normalized_username = sqlc.arg(normalized_username)
+    WHERE
+        player_skills.day <= sqlc.arg(to_day)
+),
+
+ordered_start_rows AS (
+    SELECT
+        player_rows.name,
+        player_rows.experience,
+        player_rows.level,
+        ROW_NUMBER() OVER (
+            PARTITION BY player_rows.name
+            ORDER BY
+                player_rows.after_start ASC,
+                CASE WHEN NOT player_rows.after_start THEN player_rows.day END DESC NULLS LAST,
+                player_rows.day ASC
+        ) AS row_num
+    FROM player_rows
+),
+
+ordered_end_rows AS (
+    SELECT
+        player_rows.name,
+        player_rows.experience,
+        player_rows.level,
+        ROW_NUMBER() OVER (
+            PARTITION BY player_rows.name
+            ORDER BY player_rows.day DESC
+        ) AS row_num
+    FROM player_rows
+)
+
+SELECT
+    end_rows.name,
+    start_rows.experience AS start_experience,
+    start_rows.level AS start_level,
+    end_rows.experience AS end_experience,
+    end_rows.level AS end_level
+FROM ordered_end_rows AS end_rows
+INNER JOIN ordered_start_rows AS start_rows
+    ON
+        end_rows.name = start_rows.name
+        AND
+        start_rows.row_num = 1
+WHERE
+    end_rows.row_num = 1;
+
+-- name: GetTopGainersForSkill :many
+WITH skill_rows AS (
+    SELECT
+        player_skills.player_id,
+        player_skills.day,
+        player_skills.experience,
+        player_skills.level,
+        player_skills.day > sqlc.arg(from_day) AS after_start
+    FROM player_skills
+    WHERE
+        player_skills.name = sqlc.arg(name)
+        AND
+        player_skills.day <= sqlc.arg(to_day)
+),
+
+ordered_start_rows AS (
+    SELECT
+        skill_rows.player_id,
+        skill_rows.experience,
+        skill_rows.level,
+        ROW_NUMBER() OVER (
+            PARTITION BY skill_rows.player_id
+            ORDER BY
+                skill_rows.after_start ASC,
+                CASE WHEN NOT skill_rows.after_start THEN skill_rows.day END DESC NULLS LAST,
+                skill_rows.day ASC
+        ) AS row_num
+    FROM skill_rows
+),
+
+ordered_end_rows AS (
+    SELECT
+        skill_rows.player_id,
+        skill_rows.experience,
+        skill_rows.level,
+        ROW_NUMBER() OVER (
+            PARTITION BY skill_rows.player_id
+            ORDER BY skill_rows.day DESC
+        ) AS row_num
+    FROM skill_rows
+),
+
+gains AS (
+    SELECT
+        end_rows.player_id,
+        start_rows.experience AS start_experience,
+        start_rows.level AS start_level,
+        end_rows.experience AS end_experience,
+        end_rows.level AS end_level,
+        end_rows.experience - start_rows.experience AS experience_gained
+    FROM ordered_end_rows AS end_rows
+    INNER JOIN ordered_start_rows AS start_rows
+        ON
+            end_rows.player_id = start_rows.player_id
+            AND
+            start_rows.row_num = 1
+    WHERE
+        end_rows.row_num = 1
+),
+
+ranked_gains AS (
+    SELECT
+        players.id,
+        players.username,
+        gains.start_experience,
+        gains.start_level,
+        gains.end_experience,
+        gains.end_level,
+        gains.experience_gained,
+        RANK() OVER (
+            ORDER BY gains.experience_gained DESC
+        ) AS player_rank
+    FROM gains
+    INNER JOIN players
+        ON
+            gains.player_id = players.id
+            AND
+            players.world = sqlc.arg(world)
+    WHERE
+        gains.experience_gained > 0
+)
+
+SELECT
+    ranked_gains.id,
+    ranked_gains.username,
+    ranked_gains.start_experience,
+    ranked_gains.start_level,
+    ranked_gains.end_experience,
+    ranked_gains.end_level,
+    CAST(ranked_gains.player_rank AS INTEGER) AS player_rank
+FROM ranked_gains
+ORDER BY ranked_gains.player_rank ASC, ranked_gains.username ASC
+LIMIT sqlc.arg(page_size)
+OFFSET sqlc.arg(page_offset);
+
+-- name: GetTopOverallGainers :many
+WITH skill_rows AS (
+    SELECT
+        player_skills.player_id,
+        player_skills.name,
+        player_skills.day,
+        player_skills.experience,
+        player_skills.level,
+        player_skills.day > sqlc.arg(from_day) AS after_start
+    FROM player_skills
+    WHERE
+        player_skills.day <= sqlc.arg(to_day)
+),
+
+ordered_start_rows AS (
+    SELECT
+        skill_rows.player_id,
+        skill_rows.experience,
+        skill_rows.level,
+        ROW_NUMBER() OVER (
+            PARTITION BY skill_rows.player_id, skill_rows.name
+            ORDER BY
+                skill_rows.after_start ASC,
+                CASE WHEN NOT skill_rows.after_start THEN skill_rows.day END DESC NULLS LAST,
+                skill_rows.day ASC
+        ) AS row_num
+    FROM skill_rows
+),
+
+ordered_end_rows AS (
+    SELECT
+        skill_rows.player_id,
+        skill_rows.experience,
+        skill_rows.level,
+        ROW_NUMBER() OVER (
+            PARTITION BY skill_rows.player_id, skill_rows.name
+            ORDER BY skill_rows.day DESC
+        ) AS row_num
+    FROM skill_rows
+),
+
+start_totals AS (
+    SELECT
+        ordered_start_rows.player_id,
+        SUM(ordered_start_rows.experience) AS total_experience,
+        SUM(ordered_start_rows.level) AS total_level
+    FROM ordered_start_rows
+    WHERE
+        ordered_start_rows.row_num = 1
+    GROUP BY ordered_start_rows.player_id
+),
+
+end_totals AS (
+    SELECT
+        ordered_end_rows.player_id,
+        SUM(ordered_end_rows.experience) AS total_experience,
+        SUM(ordered_end_rows.level) AS total_level
+    FROM ordered_end_rows
+    WHERE
+        ordered_end_rows.row_num = 1
+    GROUP BY ordered_end_rows.player_id
+),
+
+ranked_gains AS (
+    SELECT
+        players.id,
+        players.username,
+        start_totals.total_experience AS start_experience,
+        start_totals.total_level AS start_level,
+        end_totals.total_experience AS end_experience,
+        end_totals.total_level AS end_level,
+        RANK() OVER (
+            ORDER BY end_totals.total_experience - start_totals.total_experience DESC
+        ) AS player_rank
+    FROM end_totals
+    INNER JOIN start_totals
+        ON
+            end_totals.player_id = start_totals.player_id
+    INNER JOIN players
+        ON
+            end_totals.player_id = players.id
+            AND
+            players.world = sqlc.arg(world)
+    WHERE
+        end_totals.total_experience > start_totals.total_experience
+)
+
+SELECT
+    ranked_gains.id,
+    ranked_gains.username,
+    CAST(ranked_gains.start_experience AS DOUBLE PRECISION) AS start_experience,
+    CAST(ranked_gains.start_level AS INTEGER) AS start_level,
+    CAST(ranked_gains.end_experience AS DOUBLE PRECISION) AS end_experience,
+    CAST(ranked_gains.end_level AS INTEGER) AS end_level,
+    CAST(ranked_gains.player_rank AS INTEGER) AS player_rank
+FROM ranked_gains
+ORDER BY ranked_gains.player_rank ASC, ranked_gains.username ASC
+LIMIT sqlc.arg(page_size)
+OFFSET sqlc.arg(page_offset);
@@ -0,0 +1,76 @@
+-- name: CreateIngestionRun :exec
+INSERT INTO ingestion_runs (
+    id,
+    world,
+    started_on
+) VALUES (
+    $1,
+    $2,
+    $3
+);
+
+-- name: FinishIngestionRun :exec
+UPDATE ingestion_runs
+SET
+    finished_on = sqlc.arg(finished_on),
+    files_scanned = sqlc.arg(files_scanned),
+    players_updated = sqlc.arg(players_updated),
+    error = sqlc.narg(error)
+WHERE
+    id = sqlc.arg(id);
+
+-- name: RecordIngestionRunFailure :exec
+INSERT INTO ingestion_run_failures (
+    run_id,
+    file,
+    error
+) VALUES (
+    $1,
+    $2,
+    $3
+);
+
+-- name: DeleteIngestionRunsBefore :exec
+DELETE FROM ingestion_runs
+WHERE
+    started_on < $1;
+
+-- name: GetRecentIngestionRuns :many
+SELECT
+    id,
+    started_on,
+    finished_on,
+    files_scanned,
+    players_updated,
+    error,
+    world
+FROM ingestion_runs
+ORDER BY started_on DESC
+LIMIT $1;
+
+-- name: GetLastSuccessfulIngestionRun :one
+SELECT
+    id,
+    started_on,
+    finished_on,
+    files_scanned,
+    players_updated,
+    error,
+    world
+FROM ingestion_runs
+WHERE
+    finished_on IS NOT NULL
+    AND
+    error IS NULL
+ORDER BY started_on DESC
+LIMIT 1;
+
+-- name: GetIngestionRunFailures :many
+SELECT
+    run_id,
+    file,
+    error
+FROM ingestion_run_failures
+WHERE
+    run_id = ANY(sqlc.arg(run_ids)::VARCHAR [])
+ORDER BY run_id, file;
@@ -0,0 +1,93 @@
+-- name: GetPlayerIndex :many
+WITH latest_skills AS (
+    SELECT DISTINCT ON (player_skills.player_id, player_skills.name)
+        player_skills.player_id,
+        player_skills.day,
+        player_skills.experience,
+        player_skills.level
+    FROM player_skills
+    ORDER BY player_skills.player_id ASC, player_skills.name ASC, player_skills.day DESC
+),
+
+totals_by_player AS (
+    SELECT
+        latest_skills.player_id,
+        SUM(latest_skills.experience) AS total_experience,
+        SUM(latest_skills.level) AS total_level,
+        MAX(latest_skills.day) AS last_changed
+    FROM latest_skills
+    GROUP BY latest_skills.player_id
+),
+
+latest_combat_levels AS (
+    SELECT DISTINCT ON (player_combat_levels.player_id)
+        player_combat_levels.player_id,
+        player_combat_levels.level
+    FROM player_combat_levels
+    ORDER BY player_combat_levels.player_id ASC, player_combat_levels.day DESC
+),
+
+player_index AS (
+    SELECT
+        players.id,
+        players.username,
+        players.normalized_username,
+        COALESCE(totals.total_level, 0) AS total_level,
+        COALESCE(totals.total_experience, 0) AS total_experience,
+        COALESCE(latest_combat_levels.level, 0) AS combat_level,
+        COALESCE(TO_CHAR(totals.last_changed, 'YYYY-MM-DD'), '') AS last_changed
+    FROM players
+    LEFT JOIN totals_by_player AS totals
+        ON players.id = totals.player_id
+    LEFT JOIN latest_combat_levels
+        ON players.id = latest_combat_levels.player_id
+    WHERE players.world = sqlc.arg(world)
+)
+
+SELECT
+    player_index.id,
+    player_index.username,
+    CAST(player_index.total_level AS INTEGER) AS total_level,
+    CAST(player_index.total_experience AS DOUBLE PRECISION) AS total_experience,
+    CAST(player_index.combat_level AS INTEGER) AS combat_level,
+    CAST(player_index.last_changed AS TEXT) AS last_changed
+FROM player_index
+ORDER BY
+    CASE
+        WHEN NOT sqlc.arg(descending)::BOOLEAN THEN
+            CASE sqlc.arg(sort_by)::TEXT
+                WHEN 'total-level' THEN player_index.total_level
+                WHEN 'total-experience' THEN player_index.total_experience
+                WHEN 'combat-level' THEN player_index.combat_level
+            END
+    END ASC,
+    CASE
+        WHEN sqlc.arg(descending)::BOOLEAN THEN
+            CASE sqlc.arg(sort_by)::TEXT
+                WHEN 'total-level' THEN player_index.total_level
+                WHEN 'total-experience' THEN player_index.total_experience
+                WHEN 'combat-level' THEN player_index.combat_level
+            END
+    END DESC,
+    CASE
+        WHEN NOT sqlc.arg(descending)::BOOLEAN THEN
+            CASE sqlc.arg(sort_by)::TEXT
+                WHEN 'last-changed' THEN player_index.last_changed
+                WHEN 'username' THEN player_index.normalized_username
+            END
+    END ASC,
+    CASE
+        WHEN sqlc.arg(descending)::BOOLEAN THEN
+            CASE sqlc.arg(sort_by)::TEXT
+                WHEN 'last-changed' THEN player_index.last_changed
+                WHEN 'username' THEN player_index.normalized_username
+            END
+    END DESC,
+    player_index.normalized_username ASC
+LIMIT sqlc.arg(page_size)
+OFFSET sqlc.arg(page_offset);
+
+-- name: CountPlayers :one
+SELECT COUNT(*) AS player_count
+FROM players
+WHERE world = sqlc.arg(world);
@@ -0,0 +1,285 @@
+-- name: GetAllPlayers :many
+SELECT
+    id,
+    username,
+    created_on,
+    world,
+    normalized_username
+FROM players;
+
+-- name: GetPlayerByName :one
+SELECT
+    id,
+    username,
+    created_on,
+    world,
+    normalized_username
+FROM players
+WHERE
+    world = sqlc.arg(world)
+    AND
+    normalized_username = sqlc.arg(normalized_username);
+
+-- name: CreatePlayer :one
+INSERT INTO players (
+    id,
+    world,
+    username,
+    normalized_username,
+    created_on
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+) RETURNING *;
+
+-- name: CreatePlayerIfNotExist :exec
+INSERT INTO players (
+    id,
+    world,
+    username,
+    normalized_username,
+    created_on
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+) ON CONFLICT (world, normalized_username) DO NOTHING;
+
+-- name: GetPlayerByID :one
+SELECT
+    id,
+    username,
+    created_on,
+    world,
+    normalized_username
+FROM players
+WHERE id = sqlc.arg(id);
+
+-- name: GetPlayersByCreatedOn :many
+SELECT
+    id,
+    username,
+    created_on,
+    world,
+    normalized_username
+FROM players
+WHERE
+    world = sqlc.arg(world)
+    AND
+    created_on = sqlc.arg(created_on)
+ORDER BY username ASC;
+
+-- name: RenamePlayer :exec
+UPDATE players
+SET
+    username = sqlc.arg(username),
+    normalized_username = sqlc.arg(normalized_username)
+WHERE id = sqlc.arg(id);
+
+-- name: SearchPlayers :many
+SELECT
+    id,
+    username,
+    created_on,
+    world,
+    normalized_username
+FROM players
+WHERE
+    world = sqlc.arg(world)
+    AND
+    normalized_username LIKE sqlc.arg(pattern)::TEXT ESCAPE '\'
+ORDER BY
+    LENGTH(normalized_username) ASC,
+    normalized_username ASC
+LIMIT sqlc.arg(max_results);
+
+-- name: GetAllPlayerSkillsByPlayerName :many
+SELECT DISTINCT ON (player_skills.name)
+    player_skills.player_id,
+    player_skills.name,
+    player_skills.day,
+    player_skills.experience,
+    player_skills.level
+FROM player_skills
+INNER JOIN players
+    ON
+        player_skills.player_id = players.id
+        AND
+        players.world = sqlc.arg(world)
+        AND
+        players.normalized_username = sqlc.arg(normalized_username)
+ORDER BY player_skills.name ASC, player_skills.day DESC;
+
+-- name: GetPlayerSkillOverTimeByPlayerName :many
+SELECT
+    player_skills.player_id,
+    player_skills.name,
+    player_skills.day,
+    player_skills.experience,
+    player_skills.level
+FROM player_skills
+INNER JOIN players
+    ON
+        player_skills.player_id = players.id
+        AND
+        players.world = sqlc.arg(world)
+        AND
+        players.normalized_username = sqlc.arg(normalized_username)
+WHERE
+    player_skills.name = sqlc.arg(name)
+    AND
+    player_skills.day >= sqlc.arg(from_day)
+    AND
+    player_skills.day <= sqlc.arg(to_day)
+ORDER BY player_skills.day ASC;
+
+-- name: GetPlayerOverallOverTimeByPlayerName :many
+SELECT
+    player_skills.day,
+    CAST(SUM(player_skills.experience) AS DOUBLE PRECISION) AS total_experience,
+    CAST(SUM(player_skills.level) AS INTEGER) AS total_level
+FROM player_skills
+INNER JOIN players
+    ON
+        player_skills.player_id = players.id
+        AND
+        players.world = sqlc.arg(world)
+        AND
+        players.normalized_username = sqlc.arg(normalized_username)
+WHERE
+    player_skills.day >= sqlc.arg(from_day)
+    AND
+    player_skills.day <= sqlc.arg(to_day)
+GROUP BY player_skills.day
+ORDER BY player_skills.day ASC;
+
+-- name: GetHighscoresForSkill :many
+WITH latest_skills_by_player AS (
+    SELECT DISTINCT ON (player_skills.player_id)
+        player_skills.player_id,
+        player_skills.name,
+        player_skills.experience,
+        player_skills.level
+    FROM player_skills
+    WHERE
+        player_skills.name = sqlc.arg(name)
+    ORDER BY player_skills.player_id ASC, player_skills.day DESC
+),
+
+ranked_skills AS (
+    SELECT
+        players.id,
+        players.username,
+        skills.name,
+        skills.experience,
+        skills.level,
+        RANK() OVER (
+            ORDER BY skills.experience DESC
+        ) AS player_rank
+    FROM latest_skills_by_player AS skills
+    INNER JOIN players
+        ON
+            skills.player_id = players.id
+            AND
+            players.world = sqlc.arg(world)
+)
+
+SELECT
+    ranked_skills.id,
+    ranked_skills.username,
+    ranked_skills.name,
+    ranked_skills.experience,
+    ranked_skills.level,
+    CAST(ranked_skills.player_rank AS INTEGER) AS player_rank
+FROM ranked_skills
+ORDER BY ranked_skills.player_rank ASC, ranked_skills.username ASC
+LIMIT sqlc.arg(page_size)
+OFFSET sqlc.arg(page_offset);
+
+-- name: CountHighscoresForSkill :one
+SELECT COUNT(DISTINCT player_skills.player_id) AS player_count
+FROM player_skills
+INNER JOIN players
+    ON
+        player_skills.player_id = players.id
+        AND
+        players.world = sqlc.arg(world)
+WHERE
+    player_skills.name = sqlc.arg(name);
+
+-- name: GetOverallHighscores :many
+WITH latest_skills AS (
+    SELECT DISTINCT ON (player_skills.player_id, player_skills.name)
+        player_skills.player_id,
+        player_skills.experience,
+        player_skills.level
+    FROM player_skills
+    ORDER BY player_skills.player_id ASC, player_skills.name ASC, player_skills.day DESC
+),
+
+totals_by_player AS (
+    SELECT
+        latest_skills.player_id,
+        SUM(latest_skills.experience) AS total_experience,
+        SUM(latest_skills.level) AS total_level
+    FROM latest_skills
+    GROUP BY latest_skills.player_id
+),
+
+ranked_totals AS (
+    SELECT
+        players.id,
+        players.username,
+        totals.total_experience,
+        totals.total_level,
+        RANK() OVER (
+            ORDER BY totals.total_level DESC, totals.total_experience DESC
+        ) AS player_rank
+    FROM totals_by_player AS totals
+    INNER JOIN players
+        ON
+            totals.player_id = players.id
+            AND
+            players.world = sqlc.arg(world)
+)
+
+SELECT
+    ranked_totals.id,
+    ranked_totals.username,
+    CAST(ranked_totals.total_experience AS DOUBLE PRECISION) AS total_experience,
+    CAST(ranked_totals.total_level AS INTEGER) AS total_level,
+    CAST(ranked_totals.player_rank AS INTEGER) AS player_rank
+FROM ranked_totals
+ORDER BY ranked_totals.player_rank ASC, ranked_totals.username ASC
+LIMIT sqlc.arg(page_size)
+OFFSET sqlc.arg(page_offset);
+
+-- name: CountOverallHighscores :one
+SELECT COUNT(DISTINCT player_skills.player_id) AS player_count
+FROM player_skills
+INNER JOIN players
+    ON
+        player_skills.player_id = players.id
+        AND
+        players.world = sqlc.arg(world);
+
+-- name: RecordPlayerSkill :exec
+INSERT INTO player_skills (
+    player_id,
+    name,
+    day,
+    experience,
+    level
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+) ON CONFLICT (player_id, name, day)
+DO UPDATE SET experience = excluded.experience, level = excluded.level;
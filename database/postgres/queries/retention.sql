@@ -0,0 +1,23 @@
+-- name: GetPlayerSkillDaysByPlayerID :many
+SELECT
+    day,
+    name,
+    level,
+    experience
+FROM player_skills
+WHERE player_id = $1
+ORDER BY day ASC, name ASC;
+
+-- name: DeletePlayerSkillsOnDay :exec
+DELETE FROM player_skills
+WHERE
+    player_id = $1
+    AND
+    day = $2;
+
+-- name: DeletePlayerCombatLevelOnDay :exec
+DELETE FROM player_combat_levels
+WHERE
+    player_id = $1
+    AND
+    day = $2;
@@ -0,0 +1,67 @@
+-- name: GetAllSaveFiles :many
+SELECT
+    path,
+    modified_on,
+    size,
+    hash
+FROM save_files
+WHERE
+    world = $1;
+
+-- name: RecordSaveFile :exec
+INSERT INTO save_files (
+    world,
+    path,
+    modified_on,
+    size,
+    hash
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+) ON CONFLICT (world, path)
+DO UPDATE SET
+    modified_on = excluded.modified_on,
+    size = excluded.size,
+    hash = excluded.hash;
+
+-- name: GetQuarantinedSaveFiles :many
+SELECT
+    world,
+    path,
+    hash,
+    error,
+    first_seen_on,
+    last_seen_on
+FROM quarantined_save_files
+ORDER BY world, path;
+
+-- name: QuarantineSaveFile :exec
+INSERT INTO quarantined_save_files (
+    world,
+    path,
+    hash,
+    error,
+    first_seen_on,
+    last_seen_on
+) VALUES (
+    sqlc.arg(world),
+    sqlc.arg(path),
+    sqlc.arg(hash),
+    sqlc.arg(error),
+    sqlc.arg(seen_on),
+    sqlc.arg(seen_on)
+) ON CONFLICT (world, path)
+DO UPDATE SET
+    hash = excluded.hash,
+    error = excluded.error,
+    last_seen_on = excluded.last_seen_on;
+
+-- name: ReleaseSaveFile :exec
+DELETE FROM quarantined_save_files
+WHERE
+    world = sqlc.arg(world)
+    AND
+    path = sqlc.arg(path);
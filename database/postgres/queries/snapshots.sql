@@ -0,0 +1,70 @@
+-- name: RecordPlayerSkillSnapshot :exec
+INSERT INTO player_skill_snapshots (
+    player_id,
+    name,
+    recorded_on,
+    level,
+    experience
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+) ON CONFLICT (player_id, name, recorded_on)
+DO UPDATE SET
+    level = excluded.level,
+    experience = excluded.experience;
+
+-- name: GetPlayerSkillHourlyByPlayerName :many
+SELECT DISTINCT ON (hour)
+    CAST(DATE_TRUNC('hour', player_skill_snapshots.recorded_on) AS TIMESTAMPTZ) AS hour,
+    player_skill_snapshots.level,
+    player_skill_snapshots.experience
+FROM player_skill_snapshots
+INNER JOIN players
+    ON
+        player_skill_snapshots.player_id = players.id
+        AND
+        players.world = sqlc.arg(world)
+        AND
+        players.normalized_username = sqlc.arg(normalized_username)
+WHERE
+    player_skill_snapshots.name = sqlc.arg(name)
+    AND
+    player_skill_snapshots.recorded_on >= sqlc.arg(from_time)
+    AND
+    player_skill_snapshots.recorded_on <= sqlc.arg(to_time)
+ORDER BY hour ASC, player_skill_snapshots.recorded_on DESC;
+
+-- name: GetPlayerOverallHourlyByPlayerName :many
+WITH totals AS (
+    SELECT
+        player_skill_snapshots.recorded_on,
+        SUM(player_skill_snapshots.level) AS total_level,
+        SUM(player_skill_snapshots.experience) AS total_experience
+    FROM player_skill_snapshots
+    INNER JOIN players
+        ON
+            player_skill_snapshots.player_id = players.id
+            AND
+            players.world = sqlc.arg(world)
+            AND
+            players.normalized_username = sqlc.arg(normalized_username)
+    WHERE
+        player_skill_snapshots.recorded_on >= sqlc.arg(from_time)
+        AND
+        player_skill_snapshots.recorded_on <= sqlc.arg(to_time)
+    GROUP BY player_skill_snapshots.recorded_on
+)
+
+SELECT DISTINCT ON (hour)
+    CAST(DATE_TRUNC('hour', totals.recorded_on) AS TIMESTAMPTZ) AS hour,
+    CAST(totals.total_level AS INTEGER) AS total_level,
+    CAST(totals.total_experience AS DOUBLE PRECISION) AS total_experience
+FROM totals
+ORDER BY hour ASC, totals.recorded_on DESC;
+
+-- name: DeletePlayerSkillSnapshotsBefore :execrows
+DELETE FROM player_skill_snapshots
+WHERE recorded_on < $1;
@@ -0,0 +1,49 @@
+PRAGMA foreign_keys = ON;
+
+CREATE TABLE IF NOT EXISTS player_combat_levels (
+    player_id VARCHAR NOT NULL,
+    day VARCHAR NOT NULL CHECK (day IS date(day)),
+    level INT NOT NULL CHECK (level >= 3),
+
+    PRIMARY KEY (player_id, day),
+    FOREIGN KEY (player_id) REFERENCES players (id)
+);
+
+-- Calculate combat levels for snapshots recorded before this table existed
+INSERT OR IGNORE INTO player_combat_levels (
+    player_id,
+    day,
+    level
+)
+SELECT
+    levels.player_id,
+    levels.day,
+    CAST(
+        0.25 * (
+            levels.defence
+            + levels.constitution
+            + levels.prayer / 2
+            + levels.summoning / 2
+        )
+        + MAX(
+            0.325 * (levels.attack + levels.strength),
+            0.325 * (levels.ranged * 3 / 2),
+            0.325 * (levels.magic * 3 / 2)
+        )
+        AS INTEGER
+    ) AS level
+FROM (
+    SELECT
+        player_id,
+        day,
+        MAX(CASE WHEN name = 'Attack' THEN level ELSE 1 END) AS attack,
+        MAX(CASE WHEN name = 'Defence' THEN level ELSE 1 END) AS defence,
+        MAX(CASE WHEN name = 'Strength' THEN level ELSE 1 END) AS strength,
+        MAX(CASE WHEN name = 'Constitution' THEN level ELSE 10 END) AS constitution,
+        MAX(CASE WHEN name = 'Ranged' THEN level ELSE 1 END) AS ranged,
+        MAX(CASE WHEN name = 'Prayer' THEN level ELSE 1 END) AS prayer,
+        MAX(CASE WHEN name = 'Magic' THEN level ELSE 1 END) AS magic,
+        MAX(CASE WHEN name = 'Summoning' THEN level ELSE 1 END) AS summoning
+    FROM player_skills
+    GROUP BY player_id, day
+) AS levels;
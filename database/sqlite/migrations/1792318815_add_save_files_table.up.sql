@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS save_files (
+    path VARCHAR PRIMARY KEY NOT NULL,
+    modified_on VARCHAR NOT NULL,
+    size INT NOT NULL CHECK (size >= 0),
+    hash VARCHAR NOT NULL
+);
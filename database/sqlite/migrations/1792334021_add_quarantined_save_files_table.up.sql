@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS quarantined_save_files (
+    path VARCHAR PRIMARY KEY NOT NULL,
+    hash VARCHAR NOT NULL,
+    error VARCHAR NOT NULL,
+    first_seen_on VARCHAR NOT NULL,
+    last_seen_on VARCHAR NOT NULL
+);
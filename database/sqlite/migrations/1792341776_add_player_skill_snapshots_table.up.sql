@@ -0,0 +1,16 @@
+PRAGMA foreign_keys = ON;
+
+CREATE TABLE IF NOT EXISTS player_skill_snapshots (
+    player_id VARCHAR NOT NULL,
+    name VARCHAR NOT NULL,
+    recorded_on VARCHAR NOT NULL,
+    level INT NOT NULL CHECK (level >= 1),
+    experience REAL NOT NULL CHECK (experience >= 0),
+
+    PRIMARY KEY (player_id, name, recorded_on),
+    FOREIGN KEY (player_id) REFERENCES players (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx__player_skill_snapshots__recorded_on ON player_skill_snapshots (
+    recorded_on
+);
@@ -0,0 +1,199 @@
+-- Players from every world but the main one are removed along with everything recorded for them
+DELETE FROM player_skill_snapshots
+WHERE player_id IN (SELECT id FROM players WHERE world != 'main');
+
+DELETE FROM player_combat_levels
+WHERE player_id IN (SELECT id FROM players WHERE world != 'main');
+
+DELETE FROM player_skills
+WHERE player_id IN (SELECT id FROM players WHERE world != 'main');
+
+CREATE TABLE players_without_world (
+    id VARCHAR PRIMARY KEY NOT NULL,
+    username VARCHAR UNIQUE NOT NULL,
+    created_on VARCHAR NOT NULL
+);
+
+INSERT INTO players_without_world (
+    id,
+    username,
+    created_on
+)
+SELECT
+    id,
+    username,
+    created_on
+FROM players
+WHERE world = 'main';
+
+CREATE TEMPORARY TABLE saved_player_skills AS
+SELECT
+    player_id,
+    name,
+    day,
+    level,
+    experience
+FROM player_skills;
+
+CREATE TEMPORARY TABLE saved_player_combat_levels AS
+SELECT
+    player_id,
+    day,
+    level
+FROM player_combat_levels;
+
+CREATE TEMPORARY TABLE saved_player_skill_snapshots AS
+SELECT
+    player_id,
+    name,
+    recorded_on,
+    level,
+    experience
+FROM player_skill_snapshots;
+
+DROP TABLE player_skill_snapshots;
+DROP TABLE player_combat_levels;
+DROP TABLE player_skills;
+DROP TABLE players;
+
+ALTER TABLE players_without_world RENAME TO players;
+
+CREATE TABLE player_skills (
+    player_id VARCHAR NOT NULL,
+    name VARCHAR NOT NULL,
+    day VARCHAR NOT NULL CHECK (day IS date(day)),
+    level INT NOT NULL CHECK (level >= 1),
+    experience REAL NOT NULL CHECK (experience >= 0),
+
+    PRIMARY KEY (player_id, name, day),
+    FOREIGN KEY (player_id) REFERENCES players (id)
+);
+
+CREATE INDEX idx__player_skills__lookup ON player_skills (
+    player_id,
+    name
+);
+
+INSERT INTO player_skills (
+    player_id,
+    name,
+    day,
+    level,
+    experience
+)
+SELECT
+    player_id,
+    name,
+    day,
+    level,
+    experience
+FROM saved_player_skills;
+
+CREATE TABLE player_combat_levels (
+    player_id VARCHAR NOT NULL,
+    day VARCHAR NOT NULL CHECK (day IS date(day)),
+    level INT NOT NULL CHECK (level >= 3),
+
+    PRIMARY KEY (player_id, day),
+    FOREIGN KEY (player_id) REFERENCES players (id)
+);
+
+INSERT INTO player_combat_levels (
+    player_id,
+    day,
+    level
+)
+SELECT
+    player_id,
+    day,
+    level
+FROM saved_player_combat_levels;
+
+CREATE TABLE player_skill_snapshots (
+    player_id VARCHAR NOT NULL,
+    name VARCHAR NOT NULL,
+    recorded_on VARCHAR NOT NULL,
+    level INT NOT NULL CHECK (level >= 1),
+    experience REAL NOT NULL CHECK (experience >= 0),
+
+    PRIMARY KEY (player_id, name, recorded_on),
+    FOREIGN KEY (player_id) REFERENCES players (id)
+);
+
+CREATE INDEX idx__player_skill_snapshots__recorded_on ON player_skill_snapshots (
+    recorded_on
+);
+
+INSERT INTO player_skill_snapshots (
+    player_id,
+    name,
+    recorded_on,
+    level,
+    experience
+)
+SELECT
+    player_id,
+    name,
+    recorded_on,
+    level,
+    experience
+FROM saved_player_skill_snapshots;
+
+DROP TABLE saved_player_skills;
+DROP TABLE saved_player_combat_levels;
+DROP TABLE saved_player_skill_snapshots;
+
+CREATE TABLE save_files_without_world (
+    path VARCHAR PRIMARY KEY NOT NULL,
+    modified_on VARCHAR NOT NULL,
+    size INT NOT NULL CHECK (size >= 0),
+    hash VARCHAR NOT NULL
+);
+
+INSERT INTO save_files_without_world (
+    path,
+    modified_on,
+    size,
+    hash
+)
+SELECT
+    path,
+    modified_on,
+    size,
+    hash
+FROM save_files
+WHERE world = 'main';
+
+DROP TABLE save_files;
+
+ALTER TABLE save_files_without_world RENAME TO save_files;
+
+CREATE TABLE quarantined_save_files_without_world (
+    path VARCHAR PRIMARY KEY NOT NULL,
+    hash VARCHAR NOT NULL,
+    error VARCHAR NOT NULL,
+    first_seen_on VARCHAR NOT NULL,
+    last_seen_on VARCHAR NOT NULL
+);
+
+INSERT INTO quarantined_save_files_without_world (
+    path,
+    hash,
+    error,
+    first_seen_on,
+    last_seen_on
+)
+SELECT
+    path,
+    hash,
+    error,
+    first_seen_on,
+    last_seen_on
+FROM quarantined_save_files
+WHERE world = 'main';
+
+DROP TABLE quarantined_save_files;
+
+ALTER TABLE quarantined_save_files_without_world RENAME TO quarantined_save_files;
+
+ALTER TABLE ingestion_runs DROP COLUMN world;
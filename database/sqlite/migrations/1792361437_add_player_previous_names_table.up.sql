@@ -0,0 +1,14 @@
+PRAGMA foreign_keys = ON;
+
+CREATE TABLE IF NOT EXISTS player_previous_names (
+    player_id VARCHAR NOT NULL,
+    username VARCHAR NOT NULL,
+    renamed_on VARCHAR NOT NULL,
+
+    PRIMARY KEY (player_id, username),
+    FOREIGN KEY (player_id) REFERENCES players (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx__player_previous_names__username ON player_previous_names (
+    username
+);
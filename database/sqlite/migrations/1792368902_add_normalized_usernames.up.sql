@@ -0,0 +1,113 @@
+-- Usernames are compared without regard to case and with spaces, underscores and hyphens treated
+-- as the same character. This has to match services.NormalizeUsername.
+ALTER TABLE players ADD COLUMN normalized_username VARCHAR NOT NULL DEFAULT '';
+
+UPDATE players SET normalized_username = LOWER(
+    REPLACE(REPLACE(REPLACE(TRIM(username), ' ', '_'), '-', '_'), CHAR(160), '_')
+);
+
+ALTER TABLE player_previous_names ADD COLUMN normalized_username VARCHAR NOT NULL DEFAULT '';
+
+UPDATE player_previous_names SET normalized_username = LOWER(
+    REPLACE(REPLACE(REPLACE(TRIM(username), ' ', '_'), '-', '_'), CHAR(160), '_')
+);
+
+-- Usernames used to be matched exactly so the same player can have been recorded under names that
+-- are now the same, e.g. "Zezima" and "zezima". They're merged into whichever of them had skills
+-- recorded most recently. Where both have a record for the same time, the one that's kept wins.
+CREATE TEMPORARY TABLE merged_players AS
+SELECT
+    id AS from_id,
+    into_id
+FROM (
+    SELECT
+        players.id,
+        FIRST_VALUE(players.id) OVER (
+            PARTITION BY players.world, players.normalized_username
+            ORDER BY
+                (SELECT MAX(day) FROM player_skills WHERE player_id = players.id) DESC NULLS LAST,
+                players.created_on DESC,
+                players.id
+        ) AS into_id
+    FROM players
+)
+WHERE id <> into_id;
+
+INSERT OR IGNORE INTO player_skills (
+    player_id,
+    name,
+    day,
+    level,
+    experience
+)
+SELECT
+    merged_players.into_id,
+    player_skills.name,
+    player_skills.day,
+    player_skills.level,
+    player_skills.experience
+FROM player_skills
+JOIN merged_players ON merged_players.from_id = player_skills.player_id;
+
+DELETE FROM player_skills WHERE player_id IN (SELECT from_id FROM merged_players);
+
+INSERT OR IGNORE INTO player_combat_levels (
+    player_id,
+    day,
+    level
+)
+SELECT
+    merged_players.into_id,
+    player_combat_levels.day,
+    player_combat_levels.level
+FROM player_combat_levels
+JOIN merged_players ON merged_players.from_id = player_combat_levels.player_id;
+
+DELETE FROM player_combat_levels WHERE player_id IN (SELECT from_id FROM merged_players);
+
+INSERT OR IGNORE INTO player_skill_snapshots (
+    player_id,
+    name,
+    recorded_on,
+    level,
+    experience
+)
+SELECT
+    merged_players.into_id,
+    player_skill_snapshots.name,
+    player_skill_snapshots.recorded_on,
+    player_skill_snapshots.level,
+    player_skill_snapshots.experience
+FROM player_skill_snapshots
+JOIN merged_players ON merged_players.from_id = player_skill_snapshots.player_id;
+
+DELETE FROM player_skill_snapshots WHERE player_id IN (SELECT from_id FROM merged_players);
+
+INSERT OR IGNORE INTO player_previous_names (
+    player_id,
+    username,
+    renamed_on,
+    normalized_username
+)
+SELECT
+    merged_players.into_id,
+    player_previous_names.username,
+    player_previous_names.renamed_on,
+    player_previous_names.normalized_username
+FROM player_previous_names
+JOIN merged_players ON merged_players.from_id = player_previous_names.player_id;
+
+DELETE FROM player_previous_names WHERE player_id IN (SELECT from_id FROM merged_players);
+
+DELETE FROM players WHERE id IN (SELECT from_id FROM merged_players);
+
+DROP TABLE merged_players;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx__players__world__normalized_username ON players (
+    world,
+    normalized_username
+);
+
+CREATE INDEX IF NOT EXISTS idx__player_previous_names__normalized_username ON player_previous_names (
+    normalized_username
+);
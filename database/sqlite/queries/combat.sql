@@ -0,0 +1,94 @@
+-- name: RecordPlayerCombatLevel :exec
+INSERT INTO player_combat_levels (
+    player_id,
+    day,
+    level
+) VALUES (
+    ?,
+    ?,
+    ?
+) ON CONFLICT (player_id, day)
+DO UPDATE SET level = excluded.level;
+
+-- name: GetCombatHighscores :many
+WITH latest_combat_row AS (
+    SELECT
+        player_id,
+        MAX(day) AS latest_day
+    FROM player_combat_levels
+    GROUP BY player_id
+),
+
+latest_skill_row AS (
+    SELECT
+        player_id,
+        name,
+        MAX(day) AS latest_day
+    FROM player_skills
+    WHERE
+        name IN ('Attack', 'Defence', 'Strength', 'Constitution', 'Ranged', 'Prayer', 'Magic', 'Summoning')
+    GROUP BY player_id, name
+),
+
+combat_experience_by_player AS (
+    SELECT
+        player_skills.player_id,
+        SUM(player_skills.experience) AS combat_experience
+    FROM player_skills
+    INNER JOIN latest_skill_row
+        ON
+            player_skills.player_id = latest_skill_row.player_id
+            AND
+            player_skills.name = latest_skill_row.name
+            AND
+            player_skills.day = latest_skill_row.latest_day
+    GROUP BY player_skills.player_id
+),
+
+ranked_combat AS (
+    SELECT
+        players.id,
+        players.username,
+        player_combat_levels.level,
+        COALESCE(combat_experience_by_player.combat_experience, 0) AS combat_experience,
+        RANK() OVER (
+            ORDER BY
+                player_combat_levels.level DESC,
+                COALESCE(combat_experience_by_player.combat_experience, 0) DESC
+        ) AS player_rank
+    FROM player_combat_levels
+    INNER JOIN latest_combat_row
+        ON
+            player_combat_levels.player_id = latest_combat_row.player_id
+            AND
+            player_combat_levels.day = latest_combat_row.latest_day
+    INNER JOIN players
+        ON
+            player_combat_levels.player_id = players.id
+            AND
+            players.world = sqlc.arg(world)
+    LEFT JOIN combat_experience_by_player
+        ON
+            player_combat_levels.player_id = combat_experience_by_player.player_id
+)
+
+SELECT
+    ranked_combat.id,
+    ranked_combat.username,
+    ranked_combat.level,
+    CAST(ranked_combat.combat_experience AS REAL) AS combat_experience,
+    CAST(ranked_combat.player_rank AS INTEGER) AS player_rank
+FROM ranked_combat
+ORDER BY ranked_combat.player_rank ASC, ranked_combat.username ASC
+LIMIT sqlc.arg(page_size)
+OFFSET sqlc.arg(page_offset);
+
+-- name: CountCombatHighscores :one
+SELECT COUNT(DISTINCT player_combat_levels.player_id) AS player_count
+FROM player_combat_levels
+INNER JOIN players
+    ON
+        player_combat_levels.player_id = players.id
+        AND
+        players.world = sqlc.arg(world);
+
@@ -0,0 +1,102 @@
+-- name: GetPlayerIndex :many
+-- SQLite can't take parameters in ORDER BY so the columns to sort by are picked beforehand
+WITH sort_options AS (
+    SELECT
+        CAST(sqlc.arg(sort_by) AS TEXT) AS sort_by,
+        CAST(sqlc.arg(descending) AS INTEGER) AS descending
+),
+
+latest_row AS (
+    SELECT
+        player_id,
+        name,
+        MAX(day) AS latest_day
+    FROM player_skills
+    GROUP BY player_id, name
+),
+
+totals_by_player AS (
+    SELECT
+        player_skills.player_id,
+        SUM(player_skills.experience) AS total_experience,
+        SUM(player_skills.level) AS total_level,
+        MAX(player_skills.day) AS last_changed
+    FROM player_skills
+    INNER JOIN latest_row
+        ON
+            player_skills.player_id = latest_row.player_id
+            AND
+            player_skills.name = latest_row.name
+            AND
+            player_skills.day = latest_row.latest_day
+    GROUP BY player_skills.player_id
+),
+
+latest_combat_row AS (
+    SELECT
+        player_id,
+        MAX(day) AS latest_day
+    FROM player_combat_levels
+    GROUP BY player_id
+),
+
+player_index AS (
+    SELECT
+        players.id,
+        players.username,
+        players.normalized_username,
+        COALESCE(totals.total_level, 0) AS total_level,
+        COALESCE(totals.total_experience, 0) AS total_experience,
+        COALESCE(player_combat_levels.level, 0) AS combat_level,
+        COALESCE(totals.last_changed, '') AS last_changed
+    FROM players
+    LEFT JOIN totals_by_player AS totals
+        ON players.id = totals.player_id
+    LEFT JOIN latest_combat_row
+        ON players.id = latest_combat_row.player_id
+    LEFT JOIN player_combat_levels
+        ON
+            latest_combat_row.player_id = player_combat_levels.player_id
+            AND
+            latest_combat_row.latest_day = player_combat_levels.day
+    WHERE players.world = sqlc.arg(world)
+),
+
+sorted_index AS (
+    SELECT
+        player_index.*,
+        sort_options.descending,
+        CASE
+            WHEN sort_options.sort_by = 'total-level' THEN player_index.total_level
+            WHEN sort_options.sort_by = 'total-experience' THEN player_index.total_experience
+            WHEN sort_options.sort_by = 'combat-level' THEN player_index.combat_level
+        END AS number_key,
+        CASE
+            WHEN sort_options.sort_by = 'last-changed' THEN player_index.last_changed
+            WHEN sort_options.sort_by = 'username' THEN player_index.normalized_username
+        END AS text_key
+    FROM player_index
+    CROSS JOIN sort_options
+)
+
+SELECT
+    sorted_index.id,
+    sorted_index.username,
+    CAST(sorted_index.total_level AS INTEGER) AS total_level,
+    CAST(sorted_index.total_experience AS REAL) AS total_experience,
+    CAST(sorted_index.combat_level AS INTEGER) AS combat_level,
+    CAST(sorted_index.last_changed AS TEXT) AS last_changed
+FROM sorted_index
+ORDER BY
+    CASE WHEN sorted_index.descending = 0 THEN sorted_index.number_key END ASC,
+    CASE WHEN sorted_index.descending = 1 THEN sorted_index.number_key END DESC,
+    CASE WHEN sorted_index.descending = 0 THEN sorted_index.text_key END ASC,
+    CASE WHEN sorted_index.descending = 1 THEN sorted_index.text_key END DESC,
+    sorted_index.normalized_username ASC
+LIMIT sqlc.arg(page_size)
+OFFSET sqlc.arg(page_offset);
+
+-- name: CountPlayers :one
+SELECT COUNT(*) AS player_count
+FROM players
+WHERE world = sqlc.arg(world);
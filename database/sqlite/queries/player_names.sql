@@ -0,0 +1,46 @@
+-- name: GetPlayerPreviousNames :many
+SELECT
+    username,
+    renamed_on
+FROM player_previous_names
+WHERE player_id = sqlc.arg(player_id)
+ORDER BY renamed_on DESC;
+
+-- name: GetPlayerByPreviousName :one
+SELECT
+    players.id,
+    players.world,
+    players.username,
+    players.created_on,
+    players.normalized_username
+FROM player_previous_names
+INNER JOIN players
+    ON
+        player_previous_names.player_id = players.id
+        AND
+        players.world = sqlc.arg(world)
+WHERE
+    player_previous_names.normalized_username = sqlc.arg(normalized_username)
+ORDER BY player_previous_names.renamed_on DESC
+LIMIT 1;
+
+-- name: RecordPlayerPreviousName :exec
+INSERT INTO player_previous_names (
+    player_id,
+    username,
+    normalized_username,
+    renamed_on
+) VALUES (
+    ?,
+    ?,
+    ?,
+    ?
+) ON CONFLICT (player_id, username)
+DO UPDATE SET renamed_on = excluded.renamed_on;
+
+-- name: DeletePlayerPreviousName :exec
+DELETE FROM player_previous_names
+WHERE
+    player_id = sqlc.arg(player_id)
+    AND
+    normalized_username = sqlc.arg(normalized_username);
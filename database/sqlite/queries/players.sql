@@ -1,25 +1,35 @@
 -- name: GetAllPlayers :many
 SELECT
     id,
+    world,
     username,
-    created_on
+    created_on,
+    normalized_username
 FROM players;
 
 -- name: GetPlayerByName :one
 SELECT
     id,
+    world,
     username,
-    created_on
+    created_on,
+    normalized_username
 FROM players
 WHERE
-    username = ?;
+    world = sqlc.arg(world)
+    AND
+    normalized_username = sqlc.arg(normalized_username);
 
 -- name: CreatePlayer :one
 INSERT INTO players (
     id,
+    world,
     username,
+    normalized_username,
     created_on
 ) VALUES (
+    ?,
+    ?,
     ?,
     ?,
     ?
@@ -28,14 +38,66 @@ INSERT INTO players (
 -- name: CreatePlayerIfNotExist :exec
 INSERT OR IGNORE INTO players (
     id,
+    world,
     username,
+    normalized_username,
     created_on
 ) VALUES (
+    ?,
+    ?,
     ?,
     ?,
     ?
 );
 
+-- name: GetPlayerByID :one
+SELECT
+    id,
+    world,
+    username,
+    created_on,
+    normalized_username
+FROM players
+WHERE id = sqlc.arg(id);
+
+-- name: GetPlayersByCreatedOn :many
+SELECT
+    id,
+    world,
+    username,
+    created_on,
+    normalized_username
+FROM players
+WHERE
+    world = sqlc.arg(world)
+    AND
+    created_on = sqlc.arg(created_on)
+ORDER BY username ASC;
+
+-- name: RenamePlayer :exec
+UPDATE players
+SET
+    username = sqlc.arg(username),
+    normalized_username = sqlc.arg(normalized_username)
+WHERE id = sqlc.arg(id);
+
+-- name: SearchPlayers :many
+SELECT
+    id,
+    world,
+    username,
+    created_on,
+    normalized_username
+FROM players
+WHERE
+    world = sqlc.arg(world)
+    AND
+    LIKE(sqlc.arg(pattern), normalized_username, '\')
+ORDER BY
+    LENGTH(normalized_username) ASC,
+    normalized_username ASC
+LIMIT sqlc.arg(max_results);
+
 -- name: GetAllPlayerSkillsByPlayerName :many
 WITH ordered_skill_entries AS (
     SELECT
@@ -53,7 +115,9 @@ WITH ordered_skill_entries AS (
         ON
             player_skills.player_id = players.id
             AND
-            players.username = ?
+            players.world = sqlc.arg(world)
+            AND
+            players.normalized_username = sqlc.arg(normalized_username)
 )
 
 SELECT
@@ -78,9 +142,35 @@ INNER JOIN players
     ON
         player_skills.player_id = players.id
         AND
-        players.username = ?
+        players.world = sqlc.arg(world)
+        AND
+        players.normalized_username = sqlc.arg(normalized_username)
 WHERE
-    player_skills.name = ?
+    player_skills.name = sqlc.arg(name)
+    AND
+    player_skills.day >= sqlc.arg(from_day)
+    AND
+    player_skills.day <= sqlc.arg(to_day)
+ORDER BY player_skills.day ASC;
+
+-- name: GetPlayerOverallOverTimeByPlayerName :many
+SELECT
+    player_skills.day,
+    CAST(SUM(player_skills.experience) AS REAL) AS total_experience,
+    CAST(SUM(player_skills.level) AS INTEGER) AS total_level
+FROM player_skills
+INNER JOIN players
+    ON
+        player_skills.player_id = players.id
+        AND
+        players.world = sqlc.arg(world)
+        AND
+        players.normalized_username = sqlc.arg(normalized_username)
+WHERE
+    player_skills.day >= sqlc.arg(from_day)
+    AND
+    player_skills.day <= sqlc.arg(to_day)
+GROUP BY player_skills.day
 ORDER BY player_skills.day ASC;
 
 -- name: GetHighscoresForSkill :many
@@ -108,20 +198,112 @@ latest_skills_by_player AS (
             AND
             player_skills.day = latest_row.latest_day
     WHERE
-        player_skills.name = ?
+        player_skills.name = sqlc.arg(name)
+),
+
+ranked_skills AS (
+    SELECT
+        players.id,
+        players.username,
+        skills.name,
+        skills.experience,
+        skills.level,
+        RANK() OVER (
+            ORDER BY skills.experience DESC
+        ) AS player_rank
+    FROM latest_skills_by_player AS skills
+    INNER JOIN players
+        ON
+            skills.player_id = players.id
+            AND
+            players.world = sqlc.arg(world)
 )
 
 SELECT
-    players.id,
-    players.username,
-    skills.name,
-    skills.experience,
-    skills.level
-FROM latest_skills_by_player AS skills
+    ranked_skills.id,
+    ranked_skills.username,
+    ranked_skills.name,
+    ranked_skills.experience,
+    ranked_skills.level,
+    CAST(ranked_skills.player_rank AS INTEGER) AS player_rank
+FROM ranked_skills
+ORDER BY ranked_skills.player_rank ASC, ranked_skills.username ASC
+LIMIT sqlc.arg(page_size)
+OFFSET sqlc.arg(page_offset);
+
+-- name: CountHighscoresForSkill :one
+SELECT COUNT(DISTINCT player_skills.player_id) AS player_count
+FROM player_skills
 INNER JOIN players
     ON
-        skills.player_id = players.id
-ORDER BY skills.experience DESC;
+        player_skills.player_id = players.id
+        AND
+        players.world = sqlc.arg(world)
+WHERE
+    player_skills.name = sqlc.arg(name);
+
+-- name: GetOverallHighscores :many
+WITH latest_row AS (
+    SELECT
+        player_id,
+        name,
+        MAX(day) AS latest_day
+    FROM player_skills
+    GROUP BY player_id, name
+),
+
+totals_by_player AS (
+    SELECT
+        player_skills.player_id,
+        SUM(player_skills.experience) AS total_experience,
+        SUM(player_skills.level) AS total_level
+    FROM player_skills
+    INNER JOIN latest_row
+        ON
+            player_skills.player_id = latest_row.player_id
+            AND
+            player_skills.name = latest_row.name
+            AND
+            player_skills.day = latest_row.latest_day
+    GROUP BY player_skills.player_id
+),
+
+ranked_totals AS (
+    SELECT
+        players.id,
+        players.username,
+        totals.total_experience,
+        totals.total_level,
+        RANK() OVER (
+            ORDER BY totals.total_level DESC, totals.total_experience DESC
+        ) AS player_rank
+    FROM totals_by_player AS totals
+    INNER JOIN players
+        ON
+            totals.player_id = players.id
+            AND
+            players.world = sqlc.arg(world)
+)
+
+SELECT
+    ranked_totals.id,
+    ranked_totals.username,
+    CAST(ranked_totals.total_experience AS REAL) AS total_experience,
+    CAST(ranked_totals.total_level AS INTEGER) AS total_level,
+    CAST(ranked_totals.player_rank AS INTEGER) AS player_rank
+FROM ranked_totals
+ORDER BY ranked_totals.player_rank ASC, ranked_totals.username ASC
+LIMIT sqlc.arg(page_size)
+OFFSET sqlc.arg(page_offset);
+
+-- name: CountOverallHighscores :one
+SELECT COUNT(DISTINCT player_skills.player_id) AS player_count
+FROM player_skills
+INNER JOIN players
+    ON
+        player_skills.player_id = players.id
+        AND
+        players.world = sqlc.arg(world);
 
 -- name: RecordPlayerSkill :exec
 INSERT INTO player_skills (
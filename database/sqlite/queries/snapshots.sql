@@ -0,0 +1,96 @@
+-- name: RecordPlayerSkillSnapshot :exec
+INSERT INTO player_skill_snapshots (
+    player_id,
+    name,
+    recorded_on,
+    level,
+    experience
+) VALUES (
+    ?,
+    ?,
+    ?,
+    ?,
+    ?
+) ON CONFLICT (player_id, name, recorded_on)
+DO UPDATE SET
+    level = excluded.level,
+    experience = excluded.experience;
+
+-- name: GetPlayerSkillHourlyByPlayerName :many
+WITH snapshots_in_range AS (
+    SELECT
+        player_skill_snapshots.recorded_on,
+        player_skill_snapshots.level,
+        player_skill_snapshots.experience,
+        ROW_NUMBER() OVER (
+            PARTITION BY strftime('%Y-%m-%dT%H', player_skill_snapshots.recorded_on)
+            ORDER BY player_skill_snapshots.recorded_on DESC
+        ) AS position
+    FROM player_skill_snapshots
+    INNER JOIN players
+        ON
+            player_skill_snapshots.player_id = players.id
+            AND
+            players.world = sqlc.arg(world)
+            AND
+            players.normalized_username = sqlc.arg(normalized_username)
+    WHERE
+        player_skill_snapshots.name = sqlc.arg(name)
+        AND
+        player_skill_snapshots.recorded_on >= sqlc.arg(from_time)
+        AND
+        player_skill_snapshots.recorded_on <= sqlc.arg(to_time)
+)
+
+SELECT
+    CAST(strftime('%Y-%m-%dT%H:00:00Z', recorded_on) AS TEXT) AS hour,
+    level,
+    experience
+FROM snapshots_in_range
+WHERE position = 1
+ORDER BY hour ASC;
+
+-- name: GetPlayerOverallHourlyByPlayerName :many
+WITH totals AS (
+    SELECT
+        player_skill_snapshots.recorded_on,
+        SUM(player_skill_snapshots.level) AS total_level,
+        SUM(player_skill_snapshots.experience) AS total_experience
+    FROM player_skill_snapshots
+    INNER JOIN players
+        ON
+            player_skill_snapshots.player_id = players.id
+            AND
+            players.world = sqlc.arg(world)
+            AND
+            players.normalized_username = sqlc.arg(normalized_username)
+    WHERE
+        player_skill_snapshots.recorded_on >= sqlc.arg(from_time)
+        AND
+        player_skill_snapshots.recorded_on <= sqlc.arg(to_time)
+    GROUP BY player_skill_snapshots.recorded_on
+),
+
+hourly_totals AS (
+    SELECT
+        recorded_on,
+        total_level,
+        total_experience,
+        ROW_NUMBER() OVER (
+            PARTITION BY strftime('%Y-%m-%dT%H', recorded_on)
+            ORDER BY recorded_on DESC
+        ) AS position
+    FROM totals
+)
+
+SELECT
+    CAST(strftime('%Y-%m-%dT%H:00:00Z', recorded_on) AS TEXT) AS hour,
+    CAST(total_level AS INTEGER) AS total_level,
+    CAST(total_experience AS REAL) AS total_experience
+FROM hourly_totals
+WHERE position = 1
+ORDER BY hour ASC;
+
+-- name: DeletePlayerSkillSnapshotsBefore :execrows
+DELETE FROM player_skill_snapshots
+WHERE recorded_on < ?;
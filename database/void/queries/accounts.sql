@@ -0,0 +1,36 @@
+-- name: GetAllAccounts :many
+SELECT
+    accounts.name,
+    sqlc.embed(experience),
+    sqlc.embed(levels),
+    COALESCE(variables.long_value, 0)::BIGINT AS creation
+FROM accounts
+INNER JOIN experience
+    ON accounts.id = experience.player_id
+INNER JOIN levels
+    ON accounts.id = levels.player_id
+LEFT JOIN variables
+    ON
+        accounts.id = variables.player_id
+        AND
+        variables.name = 'creation'
+ORDER BY accounts.name;
+
+-- name: GetAccountByName :one
+SELECT
+    accounts.name,
+    sqlc.embed(experience),
+    sqlc.embed(levels),
+    COALESCE(variables.long_value, 0)::BIGINT AS creation
+FROM accounts
+INNER JOIN experience
+    ON accounts.id = experience.player_id
+INNER JOIN levels
+    ON accounts.id = levels.player_id
+LEFT JOIN variables
+    ON
+        accounts.id = variables.player_id
+        AND
+        variables.name = 'creation'
+WHERE
+    LOWER(accounts.name) = LOWER(sqlc.arg(name));
@@ -0,0 +1,78 @@
+-- The parts of the Void game server's database storage that void-tool reads. void-tool never
+-- writes to these tables or migrates them, Void owns the schema.
+--
+-- This is based on Void's SQL account storage: one row per account plus one row of experience
+-- and one row of levels per account, with a column for each skill. Experience is stored the same
+-- way as in save files, in tenths of a point. The account's creation time is the "creation"
+-- player variable, in milliseconds since the epoch, which is optional.
+
+CREATE TABLE accounts (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(12) UNIQUE NOT NULL
+);
+
+CREATE TABLE experience (
+    player_id INTEGER PRIMARY KEY REFERENCES accounts (id) ON DELETE CASCADE,
+    attack DOUBLE PRECISION NOT NULL,
+    defence DOUBLE PRECISION NOT NULL,
+    strength DOUBLE PRECISION NOT NULL,
+    constitution DOUBLE PRECISION NOT NULL,
+    ranged DOUBLE PRECISION NOT NULL,
+    prayer DOUBLE PRECISION NOT NULL,
+    magic DOUBLE PRECISION NOT NULL,
+    cooking DOUBLE PRECISION NOT NULL,
+    woodcutting DOUBLE PRECISION NOT NULL,
+    fletching DOUBLE PRECISION NOT NULL,
+    fishing DOUBLE PRECISION NOT NULL,
+    firemaking DOUBLE PRECISION NOT NULL,
+    crafting DOUBLE PRECISION NOT NULL,
+    smithing DOUBLE PRECISION NOT NULL,
+    mining DOUBLE PRECISION NOT NULL,
+    herblore DOUBLE PRECISION NOT NULL,
+    agility DOUBLE PRECISION NOT NULL,
+    thieving DOUBLE PRECISION NOT NULL,
+    slayer DOUBLE PRECISION NOT NULL,
+    farming DOUBLE PRECISION NOT NULL,
+    runecrafting DOUBLE PRECISION NOT NULL,
+    hunter DOUBLE PRECISION NOT NULL,
+    construction DOUBLE PRECISION NOT NULL,
+    summoning DOUBLE PRECISION NOT NULL,
+    dungeoneering DOUBLE PRECISION NOT NULL
+);
+
+CREATE TABLE levels (
+    player_id INTEGER PRIMARY KEY REFERENCES accounts (id) ON DELETE CASCADE,
+    attack INTEGER NOT NULL,
+    defence INTEGER NOT NULL,
+    strength INTEGER NOT NULL,
+    constitution INTEGER NOT NULL,
+    ranged INTEGER NOT NULL,
+    prayer INTEGER NOT NULL,
+    magic INTEGER NOT NULL,
+    cooking INTEGER NOT NULL,
+    woodcutting INTEGER NOT NULL,
+    fletching INTEGER NOT NULL,
+    fishing INTEGER NOT NULL,
+    firemaking INTEGER NOT NULL,
+    crafting INTEGER NOT NULL,
+    smithing INTEGER NOT NULL,
+    mining INTEGER NOT NULL,
+    herblore INTEGER NOT NULL,
+    agility INTEGER NOT NULL,
+    thieving INTEGER NOT NULL,
+    slayer INTEGER NOT NULL,
+    farming INTEGER NOT NULL,
+    runecrafting INTEGER NOT NULL,
+    hunter INTEGER NOT NULL,
+    construction INTEGER NOT NULL,
+    summoning INTEGER NOT NULL,
+    dungeoneering INTEGER NOT NULL
+);
+
+CREATE TABLE variables (
+    player_id INTEGER NOT NULL REFERENCES accounts (id) ON DELETE CASCADE,
+    name VARCHAR NOT NULL,
+    long_value BIGINT,
+
+    PRIMARY KEY (player_id, name)
+);
@@ -0,0 +1,11 @@
+package void
+
+import (
+	_ "embed"
+)
+
+// Schema is the part of the Void game server's database that void-tool reads. It's only used to
+// seed test databases, Void manages the real schema.
+//
+//go:embed schema.sql
+var Schema string
@@ -0,0 +1,128 @@
+package backfill
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+func isArchive(name string) bool {
+	return isTarArchive(name) || strings.HasSuffix(name, ".zip")
+}
+
+func isTarArchive(name string) bool {
+	return strings.HasSuffix(name, ".tar") ||
+		strings.HasSuffix(name, ".tar.gz") ||
+		strings.HasSuffix(name, ".tgz")
+}
+
+func openArchive(archivePath string) (fs.FS, func() error, error) {
+	if strings.HasSuffix(archivePath, ".zip") {
+		archive, err := zip.OpenReader(archivePath)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to open zip archive: %w", err)
+		}
+
+		return findSaveDir(archive, archive.Close)
+	}
+
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to open tar archive: %w", err)
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+
+	if !strings.HasSuffix(archivePath, ".tar") {
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to decompress tar archive: %w", err)
+		}
+		defer gzipReader.Close()
+
+		reader = gzipReader
+	}
+
+	return extractTar(reader)
+}
+
+var errUnsafeArchivePath = errors.New("archive entry escapes the archive")
+
+// extractTar extracts the saves in a tar archive to a temporary directory since tar archives
+// can't be read in place. The directory is removed when the snapshot is closed.
+func extractTar(reader io.Reader) (fs.FS, func() error, error) {
+	dir, err := os.MkdirTemp("", "void-tool-backfill-")
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to create directory to extract archive to: %w", err)
+	}
+
+	cleanup := func() error {
+		return os.RemoveAll(dir)
+	}
+
+	if err := extractTarSaves(reader, dir); err != nil {
+		_ = cleanup()
+
+		return nil, nil, err
+	}
+
+	return findSaveDir(os.DirFS(dir), cleanup)
+}
+
+func extractTarSaves(reader io.Reader, dir string) error {
+	archive := tar.NewReader(reader)
+
+	for {
+		header, err := archive.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("unable to read tar archive: %w", err)
+		}
+
+		// Only the saves are needed
+		if header.Typeflag != tar.TypeReg || path.Ext(header.Name) != ".toml" {
+			continue
+		}
+
+		name := path.Clean(strings.TrimPrefix(header.Name, "/"))
+		if !fs.ValidPath(name) {
+			return fmt.Errorf("%w: %s", errUnsafeArchivePath, header.Name)
+		}
+
+		if err := extractTarFile(archive, filepath.Join(dir, filepath.FromSlash(name))); err != nil {
+			return err
+		}
+	}
+}
+
+func extractTarFile(archive io.Reader, destination string) error {
+	if err := os.MkdirAll(filepath.Dir(destination), 0o700); err != nil {
+		return fmt.Errorf("unable to extract tar archive: %w", err)
+	}
+
+	file, err := os.OpenFile(destination, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("unable to extract tar archive: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(file, archive); err != nil {
+		return fmt.Errorf("unable to extract tar archive: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("unable to extract tar archive: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package backfill
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// findGitSnapshots treats a git repository of saves as a series of backups. Only the last commit
+// of each day is used since snapshots are recorded by day.
+func findGitSnapshots(root string) ([]Snapshot, error) {
+	output, err := exec.Command(
+		"git", "-C", root, "log", "--format=%H %cs", "--", "*.toml",
+	).Output()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read git history: %w", err)
+	}
+
+	var (
+		snapshots []Snapshot
+		seenDays  = make(map[string]struct{})
+	)
+
+	// The log is newest first so the first commit for each day is the last one made that day
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		commit, day, ok := strings.Cut(scanner.Text(), " ")
+		if !ok {
+			continue
+		}
+
+		if _, seen := seenDays[day]; seen {
+			continue
+		}
+
+		seenDays[day] = struct{}{}
+
+		date, err := time.Parse(time.DateOnly, day)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse git commit date: %w", err)
+		}
+
+		snapshots = append(snapshots, Snapshot{
+			Name: "commit " + commit,
+			Date: date,
+			open: func() (fs.FS, func() error, error) {
+				return openGitCommit(root, commit)
+			},
+		})
+	}
+
+	if len(snapshots) == 0 {
+		return nil, fmt.Errorf("%w in the git history of %s", ErrNoSnapshots, root)
+	}
+
+	sortSnapshots(snapshots)
+
+	return snapshots, nil
+}
+
+func openGitCommit(root string, commit string) (fs.FS, func() error, error) {
+	archive, err := exec.Command("git", "-C", root, "archive", "--format=tar", commit).Output()
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read saves from git commit %s: %w", commit, err)
+	}
+
+	return extractTar(bytes.NewReader(archive))
+}
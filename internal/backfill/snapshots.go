@@ -0,0 +1,188 @@
+// Package backfill finds dated backups of a Void data directory so their saves can be recorded
+// as history.
+package backfill
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Snapshot is a single backup of the saves in a data directory.
+type Snapshot struct {
+	// Name is where the snapshot came from, e.g. the backup's path or a git commit
+	Name string
+	Date time.Time
+
+	open func() (fs.FS, func() error, error)
+}
+
+// Open returns the directory holding the snapshot's saves. Close must be called once the saves
+// have been read.
+func (snapshot Snapshot) Open() (fs.FS, func() error, error) {
+	return snapshot.open()
+}
+
+var (
+	ErrNoSnapshots       = errors.New("no dated snapshots found")
+	ErrNoSaveFiles       = errors.New("no save files found in snapshot")
+	ErrAmbiguousSaveDirs = errors.New("save files found in more than one directory")
+)
+
+// datePattern matches the dates backup tools usually put in file names, e.g. 2024-03-01 or
+// 20240301.
+var datePattern = regexp.MustCompile(`(\d{4})-?(\d{2})-?(\d{2})`)
+
+// FindSnapshots finds the snapshots under root. Root can either be a git repository, in which
+// case every day with a commit is a snapshot, or a directory of backups that each have their date
+// in their name. Backups can be directories or tar, tar.gz or zip archives.
+//
+// Snapshots are sorted oldest first. When there's more than one snapshot for a day the latest
+// comes first.
+func FindSnapshots(root string) ([]Snapshot, error) {
+	if _, err := os.Stat(filepath.Join(root, ".git")); err == nil {
+		return findGitSnapshots(root)
+	}
+
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read backups: %w", err)
+	}
+
+	var snapshots []Snapshot
+
+	for _, entry := range entries {
+		date, ok := parseDate(entry.Name())
+		if !ok {
+			continue
+		}
+
+		entryPath := filepath.Join(root, entry.Name())
+
+		switch {
+		case entry.IsDir():
+			snapshots = append(snapshots, Snapshot{
+				Name: entryPath,
+				Date: date,
+				open: func() (fs.FS, func() error, error) {
+					return findSaveDir(os.DirFS(entryPath), func() error { return nil })
+				},
+			})
+
+		case isArchive(entry.Name()):
+			snapshots = append(snapshots, Snapshot{
+				Name: entryPath,
+				Date: date,
+				open: func() (fs.FS, func() error, error) {
+					return openArchive(entryPath)
+				},
+			})
+		}
+	}
+
+	if len(snapshots) == 0 {
+		return nil, fmt.Errorf("%w in %s", ErrNoSnapshots, root)
+	}
+
+	sortSnapshots(snapshots)
+
+	return snapshots, nil
+}
+
+func parseDate(name string) (time.Time, bool) {
+	match := datePattern.FindStringSubmatch(name)
+	if match == nil {
+		return time.Time{}, false
+	}
+
+	date, err := time.Parse(time.DateOnly, fmt.Sprintf("%s-%s-%s", match[1], match[2], match[3]))
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return date, true
+}
+
+func sortSnapshots(snapshots []Snapshot) {
+	slices.SortStableFunc(snapshots, func(first Snapshot, second Snapshot) int {
+		if compared := first.Date.Compare(second.Date); compared != 0 {
+			return compared
+		}
+
+		// Backup names usually end with the time they were taken so comparing them backwards puts
+		// the later one first
+		return strings.Compare(second.Name, first.Name)
+	})
+}
+
+// findSaveDir finds the directory in the snapshot that holds the saves. Backups often include
+// the rest of the server's data so this picks the shallowest directory with saves in it.
+func findSaveDir(snapshot fs.FS, closer func() error) (fs.FS, func() error, error) {
+	var (
+		saveDirs []string
+
+		// bestDepth is the depth of the saves found so far or -1 when none have been found
+		bestDepth = -1
+	)
+
+	err := fs.WalkDir(snapshot, ".", func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() || path.Ext(filePath) != ".toml" {
+			return nil
+		}
+
+		dir := path.Dir(filePath)
+
+		depth := 0
+		if dir != "." {
+			depth = strings.Count(dir, "/") + 1
+		}
+
+		switch {
+		case slices.Contains(saveDirs, dir):
+		case bestDepth == -1 || depth < bestDepth:
+			saveDirs = []string{dir}
+			bestDepth = depth
+		case depth == bestDepth:
+			saveDirs = append(saveDirs, dir)
+		}
+
+		return nil
+	})
+	if err != nil {
+		_ = closer()
+
+		return nil, nil, fmt.Errorf("unable to search snapshot for saves: %w", err)
+	}
+
+	switch len(saveDirs) {
+	case 0:
+		_ = closer()
+
+		return nil, nil, ErrNoSaveFiles
+	case 1:
+	default:
+		_ = closer()
+
+		return nil, nil, fmt.Errorf("%w: %s", ErrAmbiguousSaveDirs, strings.Join(saveDirs, ", "))
+	}
+
+	saves, err := fs.Sub(snapshot, saveDirs[0])
+	if err != nil {
+		_ = closer()
+
+		return nil, nil, fmt.Errorf("unable to open save directory in snapshot: %w", err)
+	}
+
+	return saves, closer, nil
+}
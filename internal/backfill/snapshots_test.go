@@ -0,0 +1,294 @@
+package backfill
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		expected time.Time
+		ok       bool
+	}{
+		{
+			name:     "2024-03-01",
+			expected: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+			ok:       true,
+		},
+		{
+			name:     "20240301",
+			expected: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+			ok:       true,
+		},
+		{
+			name:     "void-backup-2024-03-01T18-30.tar.gz",
+			expected: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+			ok:       true,
+		},
+		{
+			name:     "saves.zip",
+			expected: time.Time{},
+			ok:       false,
+		},
+		{
+			name:     "2024-13-01",
+			expected: time.Time{},
+			ok:       false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			date, ok := parseDate(test.name)
+			require.Equal(t, test.ok, ok)
+			require.Equal(t, test.expected, date)
+		})
+	}
+}
+
+func TestSortSnapshots(t *testing.T) {
+	t.Parallel()
+
+	march := func(day int) time.Time {
+		return time.Date(2024, time.March, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name      string
+		snapshots []Snapshot
+		expected  []string
+	}{
+		{
+			name: "oldest first",
+			snapshots: []Snapshot{
+				{Name: "backup-2024-03-03", Date: march(3), open: nil},
+				{Name: "backup-2024-03-01", Date: march(1), open: nil},
+				{Name: "backup-2024-03-02", Date: march(2), open: nil},
+			},
+			expected: []string{"backup-2024-03-01", "backup-2024-03-02", "backup-2024-03-03"},
+		},
+		{
+			name: "the later snapshot of a day sorts first",
+			snapshots: []Snapshot{
+				{Name: "backup-2024-03-01-0600", Date: march(1), open: nil},
+				{Name: "backup-2024-03-02-0600", Date: march(2), open: nil},
+				{Name: "backup-2024-03-01-1800", Date: march(1), open: nil},
+			},
+			expected: []string{
+				"backup-2024-03-01-1800",
+				"backup-2024-03-01-0600",
+				"backup-2024-03-02-0600",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			sortSnapshots(test.snapshots)
+
+			names := make([]string, len(test.snapshots))
+			for index, snapshot := range test.snapshots {
+				names[index] = snapshot.Name
+			}
+
+			require.Equal(t, test.expected, names)
+		})
+	}
+}
+
+func TestExtractTarRejectsUnsafePaths(t *testing.T) {
+	t.Parallel()
+
+	archive := buildTar(t, map[string]string{
+		"saves/zezima.toml":   "",
+		"../../etc/void.toml": "",
+	})
+
+	_, _, err := extractTar(bytes.NewReader(archive))
+	require.ErrorIs(t, err, errUnsafeArchivePath)
+}
+
+func TestExtractTarOnlyExtractsSaves(t *testing.T) {
+	t.Parallel()
+
+	archive := buildTar(t, map[string]string{
+		"void/data/saves/zezima.toml": "zezima",
+		"void/data/cache/main.dat":    "cache",
+		"/void/game.properties":       "properties",
+	})
+
+	saves, closeSnapshot, err := extractTar(bytes.NewReader(archive))
+	require.NoError(t, err)
+
+	t.Cleanup(func() { require.NoError(t, closeSnapshot()) })
+
+	requireSaves(t, saves, map[string]string{"zezima.toml": "zezima"})
+}
+
+func TestFindSnapshots(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+
+	writeFile(t, filepath.Join(root, "2024-03-01", "saves", "zezima.toml"), "directory")
+	writeFile(t, filepath.Join(root, "backup-20240302.tar.gz"), string(gzipBytes(t, buildTar(t, map[string]string{
+		"data/saves/zezima.toml": "tar",
+	}))))
+	writeFile(t, filepath.Join(root, "backup-2024-03-01-1800.zip"), string(buildZip(t, map[string]string{
+		"saves/zezima.toml": "zip",
+	})))
+
+	// Neither of these are backups
+	writeFile(t, filepath.Join(root, "2024-03-04.log"), "")
+	writeFile(t, filepath.Join(root, "latest", "zezima.toml"), "")
+
+	snapshots, err := FindSnapshots(root)
+	require.NoError(t, err)
+
+	expected := []struct {
+		name  string
+		date  time.Time
+		saves string
+	}{
+		{
+			name:  "backup-2024-03-01-1800.zip",
+			date:  time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+			saves: "zip",
+		},
+		{
+			name:  "2024-03-01",
+			date:  time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+			saves: "directory",
+		},
+		{
+			name:  "backup-20240302.tar.gz",
+			date:  time.Date(2024, time.March, 2, 0, 0, 0, 0, time.UTC),
+			saves: "tar",
+		},
+	}
+
+	require.Len(t, snapshots, len(expected))
+
+	for index, snapshot := range snapshots {
+		require.Equal(t, filepath.Join(root, expected[index].name), snapshot.Name)
+		require.Equal(t, expected[index].date, snapshot.Date)
+
+		saves, closeSnapshot, err := snapshot.Open()
+		require.NoError(t, err, snapshot.Name)
+
+		requireSaves(t, saves, map[string]string{"zezima.toml": expected[index].saves})
+		require.NoError(t, closeSnapshot())
+	}
+}
+
+func TestFindSnapshotsWithoutBackups(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "saves", "zezima.toml"), "")
+
+	_, err := FindSnapshots(root)
+	require.ErrorIs(t, err, ErrNoSnapshots)
+}
+
+func requireSaves(t *testing.T, saves fs.FS, expected map[string]string) {
+	t.Helper()
+
+	actual := make(map[string]string)
+
+	err := fs.WalkDir(saves, ".", func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		contents, err := fs.ReadFile(saves, filePath)
+		actual[filePath] = string(contents)
+
+		return err
+	})
+	require.NoError(t, err)
+	require.Equal(t, expected, actual)
+}
+
+func writeFile(t *testing.T, filePath string, contents string) {
+	t.Helper()
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(filePath), 0o700))
+	require.NoError(t, os.WriteFile(filePath, []byte(contents), 0o600))
+}
+
+func buildTar(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buffer bytes.Buffer
+
+	archive := tar.NewWriter(&buffer)
+
+	for name, contents := range files {
+		header := new(tar.Header)
+		header.Typeflag = tar.TypeReg
+		header.Name = name
+		header.Mode = 0o600
+		header.Size = int64(len(contents))
+
+		require.NoError(t, archive.WriteHeader(header))
+
+		_, err := io.WriteString(archive, contents)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, archive.Close())
+
+	return buffer.Bytes()
+}
+
+func buildZip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buffer bytes.Buffer
+
+	archive := zip.NewWriter(&buffer)
+
+	for name, contents := range files {
+		file, err := archive.Create(name)
+		require.NoError(t, err)
+
+		_, err = io.WriteString(file, contents)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, archive.Close())
+
+	return buffer.Bytes()
+}
+
+func gzipBytes(t *testing.T, contents []byte) []byte {
+	t.Helper()
+
+	var buffer bytes.Buffer
+
+	writer := gzip.NewWriter(&buffer)
+
+	_, err := writer.Write(contents)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	return buffer.Bytes()
+}
@@ -0,0 +1,157 @@
+package bgtasks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/cadyyan/void-tool/internal/logging"
+	"github.com/cadyyan/void-tool/internal/services"
+)
+
+// resolveUsername works out which username a player's skills are recorded under. Renamed
+// accounts keep their creation time so a player that isn't known by their name is treated as
+// renamed when exactly one player in the world was created at the same time and their save is
+// gone. Renames are only detected when ingesting current saves since older saves still have the
+// old names in them, but those are recorded under the player's current name. It also returns
+// the name the player was renamed from, if they were.
+func resolveUsername(
+	ctx context.Context,
+	logger *slog.Logger,
+	storageService services.StorageService,
+	playerService services.VoidPlayerService,
+	player services.VoidPlayer,
+	options IngestOptions,
+) (string, string, error) {
+	known, err := storageService.GetPlayerByUsername(ctx, services.GetPlayerByUsernameParams{
+		World:    options.World,
+		Username: player.AccountName,
+	})
+	if err == nil {
+		return updateDisplayName(ctx, logger, storageService, known, player, options)
+	} else if !errors.Is(err, services.ErrPlayerNotFound) {
+		return "", "", fmt.Errorf("unable to get player: %w", err)
+	}
+
+	// Saves without a creation time can't be matched up with anyone
+	if player.CreatedOn.UnixMilli() <= 0 {
+		return player.AccountName, "", nil
+	}
+
+	if !options.Date.IsZero() {
+		return resolvePreviousUsername(ctx, storageService, player, options)
+	}
+
+	candidates, err := storageService.GetPlayersCreatedOn(ctx, services.GetPlayersCreatedOnParams{
+		World:     options.World,
+		CreatedOn: player.CreatedOn,
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("unable to get players created at the same time: %w", err)
+	}
+
+	// A player whose save is still around is a different account that happened to be created at
+	// the same time
+	var missing []services.Player
+
+	for _, candidate := range candidates {
+		_, err := playerService.GetPlayer(ctx, candidate.Username)
+		if errors.Is(err, services.ErrPlayerSaveNotFound) {
+			missing = append(missing, candidate)
+		} else if err != nil {
+			return "", "", fmt.Errorf("unable to check for the previous save: %w", err)
+		}
+	}
+
+	if len(missing) != 1 {
+		if len(missing) > 1 {
+			logger.WarnContext(
+				ctx,
+				"Several missing players were created at the same time so the rename is ambiguous",
+				slog.Int("players", len(missing)),
+			)
+		}
+
+		return player.AccountName, "", nil
+	}
+
+	previous := missing[0]
+
+	logger.InfoContext(ctx, "Player was renamed", slog.String("previousName", previous.Username))
+
+	if options.DryRun {
+		return previous.Username, previous.Username, nil
+	}
+
+	_, err = storageService.RenamePlayer(ctx, services.RenamePlayerParams{
+		PlayerID:  previous.ID,
+		Username:  player.AccountName,
+		RenamedOn: time.Now().UTC(),
+	})
+	if err != nil {
+		logger.ErrorContext(ctx, "Unable to rename player", logging.Err(err))
+
+		return "", "", fmt.Errorf("unable to rename player: %w", err)
+	}
+
+	return player.AccountName, previous.Username, nil
+}
+
+// resolvePreviousUsername finds the current name of a player in an older save that's been
+// renamed since.
+func resolvePreviousUsername(
+	ctx context.Context,
+	storageService services.StorageService,
+	player services.VoidPlayer,
+	options IngestOptions,
+) (string, string, error) {
+	renamed, err := storageService.GetPlayerByPreviousUsername(
+		ctx,
+		services.GetPlayerByUsernameParams{
+			World:    options.World,
+			Username: player.AccountName,
+		},
+	)
+	if errors.Is(err, services.ErrPlayerNotFound) {
+		return player.AccountName, "", nil
+	} else if err != nil {
+		return "", "", fmt.Errorf("unable to get player by previous username: %w", err)
+	}
+
+	// Someone else could have used the name before
+	if !renamed.CreatedOn.Truncate(time.Second).Equal(player.CreatedOn.Truncate(time.Second)) {
+		return player.AccountName, "", nil
+	}
+
+	return renamed.Username, "", nil
+}
+
+// updateDisplayName keeps up with players changing how their name is written, like its case,
+// without changing the name itself.
+func updateDisplayName(
+	ctx context.Context,
+	logger *slog.Logger,
+	storageService services.StorageService,
+	known services.Player,
+	player services.VoidPlayer,
+	options IngestOptions,
+) (string, string, error) {
+	if known.Username == player.AccountName || !options.Date.IsZero() || options.DryRun {
+		return known.Username, "", nil
+	}
+
+	_, err := storageService.RenamePlayer(ctx, services.RenamePlayerParams{
+		PlayerID:  known.ID,
+		Username:  player.AccountName,
+		RenamedOn: time.Now().UTC(),
+	})
+	if err != nil {
+		logger.ErrorContext(ctx, "Unable to update player display name", logging.Err(err))
+
+		return "", "", fmt.Errorf("unable to update player display name: %w", err)
+	}
+
+	return player.AccountName, "", nil
+}
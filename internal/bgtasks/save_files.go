@@ -0,0 +1,84 @@
+package bgtasks
+
+import (
+	"github.com/cadyyan/void-tool/internal/services"
+)
+
+// saveFileTracker decides which saves have changed since they were last ingested. Saves are
+// assumed to be unchanged when their modification time and size match, or failing that, when
+// the hash of their contents does.
+type saveFileTracker struct {
+	world string
+	known map[string]services.SaveFileState
+
+	// touched are the saves whose contents are unchanged but were written again
+	touched []services.SaveFileState
+	seen    map[string]struct{}
+	scanned int
+	skipped int
+}
+
+func newSaveFileTracker(world string, known map[string]services.SaveFileState) *saveFileTracker {
+	return &saveFileTracker{
+		world:   world,
+		known:   known,
+		touched: nil,
+		seen:    make(map[string]struct{}),
+		scanned: 0,
+		skipped: 0,
+	}
+}
+
+func (tracker *saveFileTracker) unchanged(source services.PlayerSource) bool {
+	// Every save is checked without its hash first
+	if source.Hash == "" {
+		tracker.scanned++
+		tracker.seen[source.Path] = struct{}{}
+	}
+
+	state, ok := tracker.known[source.Path]
+	if !ok {
+		return false
+	}
+
+	if source.Hash == "" {
+		// Sources without a modification time, like database rows, can only be compared by hash
+		if source.ModifiedOn.IsZero() {
+			return false
+		}
+
+		if state.ModifiedOn.Equal(source.ModifiedOn) && state.Size == source.Size {
+			tracker.skipped++
+
+			return true
+		}
+
+		return false
+	}
+
+	if state.Hash != source.Hash {
+		return false
+	}
+
+	tracker.skipped++
+	tracker.touched = append(tracker.touched, saveFileStateFromSource(tracker.world, source))
+
+	return true
+}
+
+// wasSeen is whether the save was found while scanning the data directory.
+func (tracker *saveFileTracker) wasSeen(path string) bool {
+	_, ok := tracker.seen[path]
+
+	return ok
+}
+
+func saveFileStateFromSource(world string, source services.PlayerSource) services.SaveFileState {
+	return services.SaveFileState{
+		World:      world,
+		Path:       source.Path,
+		ModifiedOn: source.ModifiedOn,
+		Size:       source.Size,
+		Hash:       source.Hash,
+	}
+}
@@ -4,64 +4,471 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"maps"
 	"time"
 
 	"github.com/cadyyan/void-tool/internal/logging"
+	"github.com/cadyyan/void-tool/internal/metrics"
 	"github.com/cadyyan/void-tool/internal/services"
+	"github.com/cadyyan/void-tool/internal/tracing"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func ScrapePlayerSkills(
 	ctx context.Context,
 	logger *slog.Logger,
 	storageService services.StorageService,
-	playerService services.VoidPlayerService,
+	world services.VoidWorld,
 ) {
 	// TODO: job timeout?
-	traceID := must(uuid.NewV7()).String()
+	start := time.Now()
+
+	ctx, span := tracing.Start(
+		ctx,
+		"scrape player skills",
+		trace.WithNewRoot(),
+		trace.WithAttributes(attribute.String("world", world.Name)),
+	)
+
+	// Failures are recorded with the run so they're only counted here
+	summary, err := IngestPlayers(ctx, logger, storageService, world.Players, IngestOptions{
+		World:            world.Name,
+		Date:             time.Time{},
+		DryRun:           false,
+		TrackSaveFiles:   true,
+		RecordRun:        true,
+		PreserveExisting: false,
+	})
+
+	tracing.End(span, err)
+
+	recordIngestionMetrics(ctx, logger, storageService, world.Name, summary, err, time.Since(start))
+}
+
+// recordIngestionMetrics updates the metrics that show whether ingestion is keeping up.
+func recordIngestionMetrics(
+	ctx context.Context,
+	logger *slog.Logger,
+	storageService services.StorageService,
+	world string,
+	summary IngestSummary,
+	err error,
+	duration time.Duration,
+) {
+	outcome := metrics.OutcomeSuccess
+	if err != nil {
+		outcome = metrics.OutcomeFailure
+	} else {
+		metrics.IngestionLastSuccess.WithLabelValues(world).SetToCurrentTime()
+	}
+
+	metrics.IngestionRuns.WithLabelValues(world, outcome).Inc()
+	metrics.IngestionDuration.WithLabelValues(world, outcome).Observe(duration.Seconds())
+	metrics.IngestionFilesScanned.WithLabelValues(world).Add(float64(summary.FilesScanned))
+	metrics.IngestionPlayersUpdated.WithLabelValues(world).Add(float64(len(summary.Changes)))
+	metrics.IngestionFailures.WithLabelValues(world).Add(float64(len(summary.Failures)))
+
+	players, err := storageService.CountPlayers(ctx, world)
+	if err != nil {
+		logger.ErrorContext(ctx, "Unable to count tracked players", logging.Err(err))
+
+		return
+	}
+
+	metrics.TrackedPlayers.WithLabelValues(world).Set(float64(players))
+}
+
+// IngestOptions changes how IngestPlayers records what it finds.
+type IngestOptions struct {
+	// World is the world players are recorded in.
+	World string
+
+	// Date is the day skills are recorded under. When it isn't set skills are recorded under
+	// today's date and players whose skills haven't changed since they were last recorded are
+	// skipped.
+	Date time.Time
+
+	// DryRun works out what would change without recording anything.
+	DryRun bool
+
+	// TrackSaveFiles skips saves that haven't changed since they were last ingested and keeps
+	// the quarantine up to date. It should only be used when every save in the world's data
+	// directory is being ingested since saves are tracked by their path within it.
+	TrackSaveFiles bool
+
+	// RecordRun records the run in the ingestion history. It's ignored for dry runs.
+	RecordRun bool
+
+	// PreserveExisting skips players that already have skills recorded for Date so that data
+	// from newer saves is never replaced with older data.
+	PreserveExisting bool
+}
+
+// IngestSummary is what happened during a single ingestion.
+type IngestSummary struct {
+	FilesScanned int
+
+	// Changes are the players whose skills were recorded, or would have been for a dry run
+	Changes  []PlayerChange
+	Failures []services.IngestionFailure
+}
+
+// PlayerChange is how a player's skills differ from what was last recorded for them. Only the
+// skills that changed are included.
+type PlayerChange struct {
+	Username  string
+	File      string
+	NewPlayer bool
+	// RenamedFrom is the player's previous name when they were renamed since they were last
+	// ingested
+	RenamedFrom string
+	Skills      map[string]SkillChange
+}
+
+type SkillChange struct {
+	Before services.PlayerSkillRecord
+	After  services.PlayerSkillRecord
+}
+
+// IngestPlayers reads every player from the player service and records their skills. Problems
+// with individual players are listed in the summary rather than stopping the rest from being
+// ingested. Unless it's a dry run, the run is recorded in the ingestion history.
+func IngestPlayers(
+	ctx context.Context,
+	logger *slog.Logger,
+	storageService services.StorageService,
+	playerService services.VoidPlayerService,
+	options IngestOptions,
+) (IngestSummary, error) {
+	ctx, span := tracing.Start(
+		ctx,
+		"ingest players",
+		trace.WithAttributes(
+			attribute.String("world", options.World),
+			attribute.Bool("ingestion.dry_run", options.DryRun),
+		),
+	)
+
+	summary, err := ingestPlayers(ctx, logger, storageService, playerService, options)
+
+	span.SetAttributes(
+		attribute.Int("ingestion.files_scanned", summary.FilesScanned),
+		attribute.Int("ingestion.players_updated", len(summary.Changes)),
+		attribute.Int("ingestion.failures", len(summary.Failures)),
+	)
+	tracing.End(span, err)
+
+	return summary, err
+}
+
+func ingestPlayers(
+	ctx context.Context,
+	logger *slog.Logger,
+	storageService services.StorageService,
+	playerService services.VoidPlayerService,
+	options IngestOptions,
+) (IngestSummary, error) {
+	// Runs have their own IDs since traces aren't always recorded
+	runID := must(uuid.NewV7()).String()
 
-	logger = logger.With(slog.String("traceId", traceID))
+	logger = logger.With(slog.String("runId", runID), slog.String("world", options.World))
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("ingestion.run_id", runID))
 
 	logger.DebugContext(ctx, "Fetching player stats")
 	defer logger.DebugContext(ctx, "Finished fetching player stats")
 
-	players, err := playerService.GetAllPlayers(ctx)
+	summary := IngestSummary{
+		FilesScanned: 0,
+		Changes:      nil,
+		Failures:     nil,
+	}
+
+	result := services.FinishIngestionRunParams{
+		ID:             runID,
+		FinishedOn:     time.Time{},
+		FilesScanned:   0,
+		PlayersUpdated: 0,
+		Error:          "",
+		Failures:       nil,
+	}
+
+	if !options.DryRun && options.RecordRun {
+		// Ingestion is more important than keeping a history of it so failing to record the run
+		// doesn't stop it
+		err := storageService.StartIngestionRun(ctx, services.StartIngestionRunParams{
+			ID:        runID,
+			World:     options.World,
+			StartedOn: time.Now().UTC(),
+		})
+		if err != nil {
+			logger.ErrorContext(ctx, "Unable to record start of ingestion run", logging.Err(err))
+		}
+
+		defer finishIngestionRun(ctx, logger, storageService, &result)
+	}
+
+	saveFiles := make(map[string]services.SaveFileState)
+	if options.TrackSaveFiles {
+		var err error
+
+		saveFiles, err = storageService.GetSaveFileStates(ctx, options.World)
+		if err != nil {
+			logger.ErrorContext(ctx, "Unable to fetch save file states", logging.Err(err))
+			result.Error = fmt.Sprintf("unable to fetch save file states: %s", err)
+
+			return summary, fmt.Errorf("unable to fetch save file states: %w", err)
+		}
+	}
+
+	tracker := newSaveFileTracker(options.World, saveFiles)
+
+	players, err := playerService.GetAllPlayers(ctx, services.GetAllPlayersParams{
+		Unchanged: tracker.unchanged,
+	})
+	summary.FilesScanned = tracker.scanned
+	result.FilesScanned = tracker.scanned
+
 	if err != nil {
 		logger.ErrorContext(ctx, "Unable to fetch players", logging.Err(err))
+		result.Error = fmt.Sprintf("unable to fetch players: %s", err)
 
-		return
+		return summary, fmt.Errorf("unable to fetch players: %w", err)
 	}
 
-	for _, player := range players {
-		err := recordPlayerSkills(
+	logger.DebugContext(
+		ctx,
+		"Found changed players",
+		slog.Int("changed", len(players.Players)),
+		slog.Int("unchanged", tracker.skipped),
+		slog.Int("unreadable", len(players.Failures)),
+	)
+
+	for _, failure := range players.Failures {
+		if !options.DryRun && options.TrackSaveFiles {
+			quarantineSaveFile(
+				ctx,
+				logger.With(slog.String("file", failure.Source.Path)),
+				storageService,
+				options.World,
+				failure,
+			)
+		}
+
+		summary.Failures = append(summary.Failures, services.IngestionFailure{
+			File:  failure.Source.Path,
+			Error: failure.Err.Error(),
+		})
+	}
+
+	if !options.DryRun && options.TrackSaveFiles {
+		releaseMissingSaveFiles(ctx, logger, storageService, options.World, tracker)
+	}
+
+	for _, player := range players.Players {
+		change, recorded, err := ingestPlayer(
 			ctx,
 			logger.With("playerName", player.AccountName),
 			storageService,
+			playerService,
 			player,
+			options,
 		)
 		if err != nil {
+			summary.Failures = append(summary.Failures, services.IngestionFailure{
+				File:  player.Source.Path,
+				Error: err.Error(),
+			})
+
 			continue
 		}
+
+		if recorded {
+			summary.Changes = append(summary.Changes, change)
+		}
+	}
+
+	result.PlayersUpdated = len(summary.Changes)
+	result.Failures = summary.Failures
+
+	if options.DryRun || !options.TrackSaveFiles {
+		return summary, nil
 	}
+
+	// Saves that were rewritten without changing keep their new modification times so they can
+	// be skipped without hashing them next time
+	for _, state := range tracker.touched {
+		if err := storageService.RecordSaveFileState(ctx, state); err != nil {
+			logger.ErrorContext(ctx, "Unable to record save file state", logging.Err(err))
+		}
+	}
+
+	return summary, nil
 }
 
-func recordPlayerSkills(
+// quarantineSaveFile records a save that couldn't be read so it shows up on the admin page until
+// it's fixed.
+func quarantineSaveFile(
+	ctx context.Context,
+	logger *slog.Logger,
+	storageService services.StorageService,
+	world string,
+	failure services.PlayerSourceError,
+) {
+	logger.WarnContext(ctx, "Unable to read save file", logging.Err(failure.Err))
+
+	err := storageService.QuarantineSaveFile(ctx, services.QuarantineSaveFileParams{
+		World:  world,
+		Path:   failure.Source.Path,
+		Hash:   failure.Source.Hash,
+		Error:  failure.Err.Error(),
+		SeenOn: time.Now().UTC(),
+	})
+	if err != nil {
+		logger.ErrorContext(ctx, "Unable to quarantine save file", logging.Err(err))
+	}
+}
+
+// releaseMissingSaveFiles releases quarantined saves that have been removed from the world's data
+// directory. Saves that are fixed are released when they're ingested.
+func releaseMissingSaveFiles(
 	ctx context.Context,
 	logger *slog.Logger,
 	storageService services.StorageService,
+	world string,
+	tracker *saveFileTracker,
+) {
+	quarantined, err := storageService.GetQuarantinedSaveFiles(ctx)
+	if err != nil {
+		logger.ErrorContext(ctx, "Unable to get quarantined save files", logging.Err(err))
+
+		return
+	}
+
+	for _, saveFile := range quarantined {
+		if saveFile.World != world || tracker.wasSeen(saveFile.Path) {
+			continue
+		}
+
+		err := storageService.ReleaseSaveFile(ctx, services.ReleaseSaveFileParams{
+			World: world,
+			Path:  saveFile.Path,
+		})
+		if err != nil {
+			logger.ErrorContext(ctx, "Unable to release save file", logging.Err(err))
+		}
+	}
+}
+
+// ingestionRunRetention is how long the history of ingestion runs is kept for.
+const ingestionRunRetention = 30 * 24 * time.Hour
+
+func finishIngestionRun(
+	ctx context.Context,
+	logger *slog.Logger,
+	storageService services.StorageService,
+	result *services.FinishIngestionRunParams,
+) {
+	result.FinishedOn = time.Now().UTC()
+
+	logger.InfoContext(
+		ctx,
+		"Ingestion run finished",
+		slog.Int("filesScanned", result.FilesScanned),
+		slog.Int("playersUpdated", result.PlayersUpdated),
+		slog.Int("failures", len(result.Failures)),
+	)
+
+	if err := storageService.FinishIngestionRun(ctx, *result); err != nil {
+		logger.ErrorContext(ctx, "Unable to record end of ingestion run", logging.Err(err))
+	}
+
+	err := storageService.DeleteIngestionRunsBefore(ctx, result.FinishedOn.Add(-ingestionRunRetention))
+	if err != nil {
+		logger.ErrorContext(ctx, "Unable to delete old ingestion runs", logging.Err(err))
+	}
+}
+
+// ingestPlayer records a player's skills and then remembers which version of their save was
+// ingested. It reports whether the skills were recorded.
+func ingestPlayer(
+	ctx context.Context,
+	logger *slog.Logger,
+	storageService services.StorageService,
+	playerService services.VoidPlayerService,
 	player services.VoidPlayer,
-) error {
-	playerRecord, err := storageService.GetOrCreatePlayerByUsername(
+	options IngestOptions,
+) (PlayerChange, bool, error) {
+	ctx, span := tracing.Start(
 		ctx,
-		services.GetOrCreatePlayerByUsernameParams{
-			Username:  player.AccountName,
-			CreatedOn: player.CreatedOn,
-		},
+		"ingest player",
+		trace.WithAttributes(
+			attribute.String("player.name", player.AccountName),
+			attribute.String("save.path", player.Source.Path),
+		),
+	)
+
+	change, recorded, err := ingestPlayerSave(ctx, logger, storageService, playerService, player, options)
+
+	span.SetAttributes(attribute.Bool("ingestion.recorded", recorded))
+	tracing.End(span, err)
+
+	return change, recorded, err
+}
+
+func ingestPlayerSave(
+	ctx context.Context,
+	logger *slog.Logger,
+	storageService services.StorageService,
+	playerService services.VoidPlayerService,
+	player services.VoidPlayer,
+	options IngestOptions,
+) (PlayerChange, bool, error) {
+	change, recorded, err := recordPlayerSkills(
+		ctx,
+		logger,
+		storageService,
+		playerService,
+		player,
+		options,
 	)
 	if err != nil {
-		logger.ErrorContext(ctx, "Unable to get player", logging.Err(err))
+		return PlayerChange{}, false, err
+	}
 
-		return fmt.Errorf("unable to get player: %w", err)
+	if options.DryRun || !options.TrackSaveFiles || player.Source.Path == "" {
+		return change, recorded, nil
+	}
+
+	err = storageService.RecordSaveFileState(ctx, saveFileStateFromSource(options.World, player.Source))
+	if err != nil {
+		logger.ErrorContext(ctx, "Unable to record save file state", logging.Err(err))
+
+		return change, recorded, fmt.Errorf("unable to record save file state: %w", err)
+	}
+
+	return change, recorded, nil
+}
+
+func recordPlayerSkills(
+	ctx context.Context,
+	logger *slog.Logger,
+	storageService services.StorageService,
+	playerService services.VoidPlayerService,
+	player services.VoidPlayer,
+	options IngestOptions,
+) (PlayerChange, bool, error) {
+	username, renamedFrom, err := resolveUsername(
+		ctx,
+		logger,
+		storageService,
+		playerService,
+		player,
+		options,
+	)
+	if err != nil {
+		return PlayerChange{}, false, err
 	}
 
 	skillUpdate := make(map[string]services.PlayerSkillRecord)
@@ -74,22 +481,115 @@ func recordPlayerSkills(
 		}
 	}
 
-	today := time.Now().UTC()
+	latestSkills, err := storageService.GetPlayerSkills(ctx, services.GetPlayerSkillsParams{
+		World:    options.World,
+		Username: username,
+	})
+	if err != nil {
+		logger.ErrorContext(ctx, "Unable to get latest player skills", logging.Err(err))
+
+		return PlayerChange{}, false, fmt.Errorf("unable to get latest player skills: %w", err)
+	}
+
+	change := newPlayerChange(player, latestSkills, skillUpdate)
+	change.RenamedFrom = renamedFrom
+
+	if options.PreserveExisting && !options.Date.IsZero() {
+		existing, err := storageService.GetPlayerSkillHistory(ctx, services.GetPlayerSkillHistoryParams{
+			World:       options.World,
+			Username:    username,
+			Skill:       services.SkillOverall,
+			From:        options.Date,
+			To:          options.Date,
+			Granularity: services.HistoryGranularityDay,
+		})
+		if err != nil {
+			logger.ErrorContext(ctx, "Unable to get existing player skills", logging.Err(err))
+
+			return PlayerChange{}, false, fmt.Errorf("unable to get existing player skills: %w", err)
+		}
+
+		if len(existing) > 0 {
+			logger.DebugContext(ctx, "Player already has skills recorded for this day")
+
+			return change, false, nil
+		}
+	}
+
+	// Snapshots for a chosen day are always recorded since the latest skills could be from any
+	// other day
+	if options.Date.IsZero() && maps.Equal(latestSkills, skillUpdate) {
+		logger.DebugContext(ctx, "Player skills are unchanged")
+
+		// Renames are still worth reporting
+		return change, renamedFrom != "", nil
+	}
+
+	if options.DryRun {
+		return change, true, nil
+	}
+
+	playerRecord, err := storageService.GetOrCreatePlayerByUsername(
+		ctx,
+		services.GetOrCreatePlayerByUsernameParams{
+			World:     options.World,
+			Username:  username,
+			CreatedOn: player.CreatedOn,
+		},
+	)
+	if err != nil {
+		logger.ErrorContext(ctx, "Unable to get player", logging.Err(err))
+
+		return PlayerChange{}, false, fmt.Errorf("unable to get player: %w", err)
+	}
+
+	date := options.Date
+	if date.IsZero() {
+		date = time.Now().UTC()
+	}
+
 	err = storageService.RecordPlayerSkills(
 		ctx,
 		services.RecordPlayerSkillsParams{
 			PlayerID: playerRecord.ID,
-			Date:     today,
+			Date:     date,
 			Skills:   skillUpdate,
 		},
 	)
 	if err != nil {
 		logger.ErrorContext(ctx, "Unable to record player skills", logging.Err(err))
 
-		return fmt.Errorf("unable to record player skills: %w", err)
+		return PlayerChange{}, false, fmt.Errorf("unable to record player skills: %w", err)
+	}
+
+	return change, true, nil
+}
+
+func newPlayerChange(
+	player services.VoidPlayer,
+	before map[string]services.PlayerSkillRecord,
+	after map[string]services.PlayerSkillRecord,
+) PlayerChange {
+	change := PlayerChange{
+		Username:    player.AccountName,
+		File:        player.Source.Path,
+		NewPlayer:   len(before) == 0,
+		RenamedFrom: "",
+		Skills:      make(map[string]SkillChange),
+	}
+
+	for skill, record := range after {
+		if previous, ok := before[skill]; ok && previous == record {
+			continue
+		}
+
+		change.Skills[skill] = SkillChange{
+			Before: before[skill],
+			After:  record,
+		}
 	}
 
-	return nil
+	return change
 }
 
 //nolint:ireturn // This is a bug in the linter
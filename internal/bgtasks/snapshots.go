@@ -0,0 +1,61 @@
+package bgtasks
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/cadyyan/void-tool/internal/logging"
+	"github.com/cadyyan/void-tool/internal/retention"
+	"github.com/cadyyan/void-tool/internal/services"
+)
+
+// PruneIntradaySnapshots removes snapshots older than the retention period. The daily history
+// isn't affected since it's stored separately.
+func PruneIntradaySnapshots(
+	ctx context.Context,
+	logger *slog.Logger,
+	storageService services.StorageService,
+	retention time.Duration,
+) {
+	before := time.Now().UTC().Add(-retention)
+
+	deleted, err := storageService.DeleteIntradaySnapshotsBefore(ctx, before)
+	if err != nil {
+		logger.ErrorContext(ctx, "Unable to prune intraday snapshots", logging.Err(err))
+
+		return
+	}
+
+	logger.DebugContext(
+		ctx,
+		"Pruned intraday snapshots",
+		slog.Int("deleted", deleted),
+		slog.Time("before", before),
+	)
+}
+
+// DownsampleSkillHistory removes redundant and old days from every player's history according to
+// the retention policy.
+func DownsampleSkillHistory(
+	ctx context.Context,
+	logger *slog.Logger,
+	storageService services.StorageService,
+	policy retention.Policy,
+) {
+	summary, err := retention.Apply(ctx, logger, storageService, policy, false)
+	if err != nil {
+		logger.ErrorContext(ctx, "Unable to downsample skill history", logging.Err(err))
+
+		return
+	}
+
+	logger.InfoContext(
+		ctx,
+		"Downsampled skill history",
+		slog.Int("playersChecked", summary.PlayersChecked),
+		slog.Int("playersChanged", summary.PlayersChanged),
+		slog.Int("redundant", summary.Redundant),
+		slog.Int("downsampled", summary.Downsampled),
+	)
+}
@@ -0,0 +1,444 @@
+package bgtasks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/cadyyan/void-tool/internal/logging"
+	"github.com/cadyyan/void-tool/internal/services"
+	"github.com/cadyyan/void-tool/internal/tracing"
+	"github.com/fsnotify/fsnotify"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SaveFileReader reads a single save file by its path relative to the data directory.
+type SaveFileReader interface {
+	services.VoidPlayerService
+
+	GetPlayerFromFile(ctx context.Context, filePath string) (services.VoidPlayer, error)
+}
+
+const saveFilePattern = "*.toml"
+
+// SaveFileWatcher ingests individual save files as they change. Saves are usually written a few
+// times in quick succession when a player logs out so each file is only ingested once it has
+// stopped changing for the debounce period.
+type SaveFileWatcher struct {
+	logger         *slog.Logger
+	world          string
+	dataDir        string
+	debounce       time.Duration
+	pollFrequency  time.Duration
+	storageService services.StorageService
+	reader         SaveFileReader
+}
+
+func NewSaveFileWatcher(
+	logger *slog.Logger,
+	world string,
+	dataDir string,
+	debounce time.Duration,
+	pollFrequency time.Duration,
+	storageService services.StorageService,
+	reader SaveFileReader,
+) *SaveFileWatcher {
+	return &SaveFileWatcher{
+		logger:         logger,
+		world:          world,
+		dataDir:        dataDir,
+		debounce:       debounce,
+		pollFrequency:  pollFrequency,
+		storageService: storageService,
+		reader:         reader,
+	}
+}
+
+// Run watches the data directory until the context is cancelled. If the directory can't be
+// watched with inotify (e.g. it's on a network share) it falls back to polling.
+func (watcher *SaveFileWatcher) Run(ctx context.Context) error {
+	notifier, err := fsnotify.NewWatcher()
+	if err != nil {
+		watcher.logger.WarnContext(
+			ctx,
+			"Unable to create file watcher, falling back to polling",
+			logging.Err(err),
+		)
+
+		return watcher.poll(ctx)
+	}
+	defer notifier.Close()
+
+	if err := notifier.Add(watcher.dataDir); err != nil {
+		watcher.logger.WarnContext(
+			ctx,
+			"Unable to watch data directory, falling back to polling",
+			logging.Err(err),
+		)
+
+		return watcher.poll(ctx)
+	}
+
+	return watcher.watch(ctx, notifier)
+}
+
+func (watcher *SaveFileWatcher) watch(ctx context.Context, notifier *fsnotify.Watcher) error {
+	watcher.logger.InfoContext(ctx, "Watching data directory for changes")
+
+	debouncer := newFileDebouncer(watcher.debounce)
+	defer debouncer.stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case event, ok := <-notifier.Events:
+			if !ok {
+				return nil
+			}
+
+			if !event.Has(fsnotify.Create) && !event.Has(fsnotify.Write) {
+				continue
+			}
+
+			filePath, err := filepath.Rel(watcher.dataDir, event.Name)
+			if err != nil {
+				continue
+			}
+
+			filePath = filepath.ToSlash(filePath)
+			if matched, _ := path.Match(saveFilePattern, filePath); !matched {
+				continue
+			}
+
+			debouncer.schedule(filePath)
+
+		case err, ok := <-notifier.Errors:
+			if !ok {
+				return nil
+			}
+
+			watcher.logger.ErrorContext(ctx, "Error while watching data directory", logging.Err(err))
+
+		case filePath := <-debouncer.ready:
+			watcher.ingest(ctx, filePath)
+		}
+	}
+}
+
+type saveFileState struct {
+	modifiedOn time.Time
+	size       int64
+}
+
+func (watcher *SaveFileWatcher) poll(ctx context.Context) error {
+	watcher.logger.InfoContext(
+		ctx,
+		"Polling data directory for changes",
+		slog.Duration("frequency", watcher.pollFrequency),
+	)
+
+	dataFS := os.DirFS(watcher.dataDir)
+
+	// The first scan only records the current state of each file since everything gets ingested
+	// when the server starts
+	known, err := scanSaveFiles(dataFS)
+	if err != nil {
+		return err
+	}
+
+	debouncer := newFileDebouncer(watcher.debounce)
+	defer debouncer.stop()
+
+	ticker := time.NewTicker(watcher.pollFrequency)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case <-ticker.C:
+			current, err := scanSaveFiles(dataFS)
+			if err != nil {
+				watcher.logger.ErrorContext(ctx, "Unable to scan data directory", logging.Err(err))
+
+				continue
+			}
+
+			for filePath, state := range current {
+				if previous, ok := known[filePath]; !ok || previous != state {
+					debouncer.schedule(filePath)
+				}
+			}
+
+			known = current
+
+		case filePath := <-debouncer.ready:
+			watcher.ingest(ctx, filePath)
+		}
+	}
+}
+
+func scanSaveFiles(fileSystem fs.FS) (map[string]saveFileState, error) {
+	playerFiles, err := fs.Glob(fileSystem, saveFilePattern)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find player files: %w", err)
+	}
+
+	states := make(map[string]saveFileState, len(playerFiles))
+
+	for _, playerFile := range playerFiles {
+		info, err := fs.Stat(fileSystem, playerFile)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("unable to check player file: %w", err)
+		}
+
+		states[playerFile] = saveFileState{
+			modifiedOn: info.ModTime(),
+			size:       info.Size(),
+		}
+	}
+
+	return states, nil
+}
+
+func (watcher *SaveFileWatcher) ingest(ctx context.Context, filePath string) {
+	start := time.Now()
+
+	// Each change is traced on its own rather than as part of the watcher's lifetime
+	ctx, span := tracing.Start(
+		ctx,
+		"ingest changed save file",
+		trace.WithNewRoot(),
+		trace.WithAttributes(
+			attribute.String("world", watcher.world),
+			attribute.String("save.path", filePath),
+		),
+	)
+
+	logger := watcher.logger.With(slog.String("file", filePath))
+
+	summary, err := watcher.ingestSaveFile(ctx, logger, filePath)
+
+	tracing.End(span, err)
+
+	// Nothing was ingested when the file was deleted before it settled
+	if summary.FilesScanned == 0 {
+		return
+	}
+
+	// In watch mode this is the only ingestion after startup so it has to keep the metrics up to
+	// date too
+	recordIngestionMetrics(
+		ctx,
+		logger,
+		watcher.storageService,
+		watcher.world,
+		summary,
+		err,
+		time.Since(start),
+	)
+}
+
+func (watcher *SaveFileWatcher) ingestSaveFile(
+	ctx context.Context,
+	logger *slog.Logger,
+	filePath string,
+) (IngestSummary, error) {
+	logger.DebugContext(ctx, "Ingesting changed save file")
+
+	summary := IngestSummary{
+		FilesScanned: 0,
+		Changes:      nil,
+		Failures:     nil,
+	}
+
+	player, err := watcher.reader.GetPlayerFromFile(ctx, filePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		logger.DebugContext(ctx, "Save file no longer exists", logging.Err(err))
+
+		return summary, nil
+	}
+
+	summary.FilesScanned = 1
+
+	// Each change is recorded as a run of its own so the ingestion history shows that watch mode
+	// is keeping up
+	runID := must(uuid.NewV7()).String()
+	logger = logger.With(slog.String("runId", runID))
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("ingestion.run_id", runID))
+
+	result := services.FinishIngestionRunParams{
+		ID:             runID,
+		FinishedOn:     time.Time{},
+		FilesScanned:   summary.FilesScanned,
+		PlayersUpdated: 0,
+		Error:          "",
+		Failures:       nil,
+	}
+
+	// Ingestion is more important than keeping a history of it so failing to record the run
+	// doesn't stop it
+	startErr := watcher.storageService.StartIngestionRun(ctx, services.StartIngestionRunParams{
+		ID:        runID,
+		World:     watcher.world,
+		StartedOn: time.Now().UTC(),
+	})
+	if startErr != nil {
+		logger.ErrorContext(ctx, "Unable to record start of ingestion run", logging.Err(startErr))
+	}
+
+	defer func() {
+		result.PlayersUpdated = len(summary.Changes)
+		result.Failures = summary.Failures
+		finishIngestionRun(ctx, logger, watcher.storageService, &result)
+	}()
+
+	if err != nil {
+		// The next change to the file will trigger another attempt
+		var sourceErr services.PlayerSourceError
+		if !errors.As(err, &sourceErr) {
+			sourceErr = services.PlayerSourceError{
+				Source: services.PlayerSource{
+					Path:       filePath,
+					ModifiedOn: time.Time{},
+					Size:       0,
+					Hash:       "",
+				},
+				Err: err,
+			}
+		}
+
+		quarantineSaveFile(ctx, logger, watcher.storageService, watcher.world, sourceErr)
+
+		summary.Failures = append(summary.Failures, services.IngestionFailure{
+			File:  filePath,
+			Error: sourceErr.Err.Error(),
+		})
+
+		return summary, nil
+	}
+
+	change, recorded, err := ingestPlayer(
+		ctx,
+		logger.With("playerName", player.AccountName),
+		watcher.storageService,
+		watcher.reader,
+		player,
+		IngestOptions{
+			World:            watcher.world,
+			Date:             time.Time{},
+			DryRun:           false,
+			TrackSaveFiles:   true,
+			RecordRun:        false,
+			PreserveExisting: false,
+		},
+	)
+	if err != nil {
+		summary.Failures = append(summary.Failures, services.IngestionFailure{
+			File:  filePath,
+			Error: err.Error(),
+		})
+		result.Error = err.Error()
+
+		return summary, err
+	}
+
+	if recorded {
+		summary.Changes = append(summary.Changes, change)
+	}
+
+	return summary, nil
+}
+
+// fileDebouncer delays each file until it has stopped changing for a while. Files are sent to
+// ready once they've settled.
+type fileDebouncer struct {
+	delay      time.Duration
+	ready      chan string
+	lock       sync.Mutex
+	pending    map[string]pendingFile
+	generation uint64
+	done       chan struct{}
+
+	// afterFunc starts the timer for a file, it's time.AfterFunc outside of tests.
+	afterFunc func(time.Duration, func()) *time.Timer
+}
+
+// pendingFile is a file that's waiting to settle. Each time a file changes it gets a new
+// generation so a timer that had already fired before the change can tell it's stale.
+type pendingFile struct {
+	timer      *time.Timer
+	generation uint64
+}
+
+func newFileDebouncer(delay time.Duration) *fileDebouncer {
+	return &fileDebouncer{
+		delay:      delay,
+		ready:      make(chan string),
+		lock:       sync.Mutex{},
+		pending:    make(map[string]pendingFile),
+		generation: 0,
+		done:       make(chan struct{}),
+		afterFunc:  time.AfterFunc,
+	}
+}
+
+func (debouncer *fileDebouncer) schedule(filePath string) {
+	debouncer.lock.Lock()
+	defer debouncer.lock.Unlock()
+
+	// Stopping the timer doesn't stop a callback that's already running so the callback checks
+	// that it's still the latest one for the file instead
+	if pending, ok := debouncer.pending[filePath]; ok {
+		pending.timer.Stop()
+	}
+
+	debouncer.generation++
+	generation := debouncer.generation
+
+	debouncer.pending[filePath] = pendingFile{
+		timer: debouncer.afterFunc(debouncer.delay, func() {
+			debouncer.lock.Lock()
+
+			if debouncer.pending[filePath].generation != generation {
+				debouncer.lock.Unlock()
+
+				return
+			}
+
+			delete(debouncer.pending, filePath)
+			debouncer.lock.Unlock()
+
+			select {
+			case debouncer.ready <- filePath:
+			case <-debouncer.done:
+			}
+		}),
+		generation: generation,
+	}
+}
+
+func (debouncer *fileDebouncer) stop() {
+	debouncer.lock.Lock()
+	defer debouncer.lock.Unlock()
+
+	for _, pending := range debouncer.pending {
+		pending.timer.Stop()
+	}
+
+	close(debouncer.done)
+}
@@ -0,0 +1,65 @@
+package bgtasks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileDebouncerSendsSettledFilesOnce(t *testing.T) {
+	t.Parallel()
+
+	debouncer := newFileDebouncer(10 * time.Millisecond)
+	t.Cleanup(debouncer.stop)
+
+	for range 5 {
+		debouncer.schedule("zezima.toml")
+	}
+
+	require.Equal(t, "zezima.toml", <-debouncer.ready)
+
+	select {
+	case filePath := <-debouncer.ready:
+		require.Failf(t, "file sent again", "%s was sent more than once", filePath)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestFileDebouncerIgnoresCallbacksInFlight(t *testing.T) {
+	t.Parallel()
+
+	// The timers never fire by themselves, the test runs their callbacks in whatever order it
+	// needs instead
+	var callbacks []func()
+
+	debouncer := newFileDebouncer(time.Hour)
+	debouncer.afterFunc = func(delay time.Duration, callback func()) *time.Timer {
+		callbacks = append(callbacks, callback)
+
+		return time.AfterFunc(delay, func() {})
+	}
+	t.Cleanup(debouncer.stop)
+
+	// The first timer has fired but its callback hasn't run yet when the file changes again
+	debouncer.schedule("zezima.toml")
+	debouncer.schedule("zezima.toml")
+	require.Len(t, callbacks, 2)
+
+	stale := make(chan struct{})
+
+	go func() {
+		callbacks[0]()
+		close(stale)
+	}()
+
+	select {
+	case <-stale:
+	case filePath := <-debouncer.ready:
+		require.Failf(t, "stale callback sent a file", "%s was sent by a stale callback", filePath)
+	}
+
+	go callbacks[1]()
+
+	require.Equal(t, "zezima.toml", <-debouncer.ready)
+}
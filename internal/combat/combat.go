@@ -0,0 +1,59 @@
+package combat
+
+import "math"
+
+// Skills that contribute to a player's combat level.
+const (
+	Attack       = "Attack"
+	Defence      = "Defence"
+	Strength     = "Strength"
+	Constitution = "Constitution"
+	Ranged       = "Ranged"
+	Prayer       = "Prayer"
+	Magic        = "Magic"
+	Summoning    = "Summoning"
+)
+
+// Skills lists every skill that contributes to a player's combat level.
+var Skills = []string{
+	Attack,
+	Defence,
+	Strength,
+	Constitution,
+	Ranged,
+	Prayer,
+	Magic,
+	Summoning,
+}
+
+const (
+	minimumLevel             = 1
+	minimumConstitutionLevel = 10
+)
+
+// Level calculates a combat level from a map of skill names to levels using the 2011 formula,
+// where half of the Summoning level counts towards the base alongside Prayer. Skills missing from
+// the map are treated as untrained.
+func Level(levels map[string]int) int {
+	level := func(skill string, minimum int) int {
+		value, ok := levels[skill]
+		if !ok || value < minimum {
+			return minimum
+		}
+
+		return value
+	}
+
+	base := 0.25 * float64(
+		level(Defence, minimumLevel)+
+			level(Constitution, minimumConstitutionLevel)+
+			level(Prayer, minimumLevel)/2+
+			level(Summoning, minimumLevel)/2,
+	)
+
+	melee := 0.325 * float64(level(Attack, minimumLevel)+level(Strength, minimumLevel))
+	ranged := 0.325 * math.Floor(float64(level(Ranged, minimumLevel))*1.5)
+	magic := 0.325 * math.Floor(float64(level(Magic, minimumLevel))*1.5)
+
+	return int(math.Floor(base + max(melee, ranged, magic)))
+}
@@ -0,0 +1,90 @@
+package combat_test
+
+import (
+	"testing"
+
+	"github.com/cadyyan/void-tool/internal/combat"
+)
+
+func TestLevel(t *testing.T) {
+	t.Parallel()
+
+	maxed := func(overrides map[string]int) map[string]int {
+		levels := make(map[string]int, len(combat.Skills))
+		for _, skill := range combat.Skills {
+			levels[skill] = 99
+		}
+
+		for skill, level := range overrides {
+			levels[skill] = level
+		}
+
+		return levels
+	}
+
+	tests := []struct {
+		name     string
+		levels   map[string]int
+		expected int
+	}{
+		{
+			name: "fresh account",
+			levels: map[string]int{
+				combat.Attack:       1,
+				combat.Defence:      1,
+				combat.Strength:     1,
+				combat.Constitution: 10,
+				combat.Ranged:       1,
+				combat.Prayer:       1,
+				combat.Magic:        1,
+				combat.Summoning:    1,
+			},
+			expected: 3,
+		},
+		{
+			name:     "missing skills are untrained",
+			levels:   map[string]int{},
+			expected: 3,
+		},
+		{
+			name:     "levels below the minimum are raised to it",
+			levels:   map[string]int{combat.Attack: 0, combat.Constitution: 1},
+			expected: 3,
+		},
+		{
+			name:     "maxed account",
+			levels:   maxed(nil),
+			expected: 138,
+		},
+		{
+			name:     "maxed account without Summoning",
+			levels:   maxed(map[string]int{combat.Summoning: 1}),
+			expected: 126,
+		},
+		{
+			name:     "Summoning alone",
+			levels:   map[string]int{combat.Summoning: 99},
+			expected: 15,
+		},
+		{
+			name:     "pure ranger",
+			levels:   map[string]int{combat.Ranged: 99},
+			expected: 50,
+		},
+		{
+			name:     "pure mage",
+			levels:   map[string]int{combat.Magic: 99},
+			expected: 50,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			if level := combat.Level(test.levels); level != test.expected {
+				t.Errorf("expected combat level %d, got %d", test.expected, level)
+			}
+		})
+	}
+}
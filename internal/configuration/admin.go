@@ -0,0 +1,13 @@
+package configuration
+
+type AdminConfiguration struct {
+	// Username and Password are the basic auth credentials for the admin pages. The admin pages
+	// aren't served at all unless a password is set.
+	Username string `default:"admin"`
+	Password string
+}
+
+// Enabled is whether the admin pages are served.
+func (config AdminConfiguration) Enabled() bool {
+	return config.Password != ""
+}
@@ -7,10 +7,15 @@ import (
 )
 
 type Configuration struct {
-	Logging LoggingConfiguration
-	HTTP    HTTPConfiguration
-	RS      RunescapeConfiguration
-	SQLite  SQLiteConfiguration
+	Logging   LoggingConfiguration
+	HTTP      HTTPConfiguration
+	RS        RunescapeConfiguration
+	Storage   StorageConfiguration
+	SQLite    SQLiteConfiguration
+	Postgres  PostgresConfiguration
+	Retention RetentionConfiguration
+	Tracing   TracingConfiguration
+	Admin     AdminConfiguration
 }
 
 func NewConfigurationFromEnv() (Configuration, error) {
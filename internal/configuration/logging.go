@@ -6,6 +6,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/cadyyan/void-tool/internal/logging"
 	"github.com/go-chi/httplog/v2"
 	"github.com/kelseyhightower/envconfig"
 	"github.com/lmittmann/tint"
@@ -25,7 +26,7 @@ func (config LoggingConfiguration) BuildLogger() *slog.Logger {
 		NoColor:     !config.Color,
 	})
 
-	return slog.New(handler)
+	return slog.New(logging.NewTraceHandler(handler))
 }
 
 func (config LoggingConfiguration) BuildAccessLogger() *httplog.Logger {
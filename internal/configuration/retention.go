@@ -0,0 +1,35 @@
+package configuration
+
+import (
+	"time"
+
+	"github.com/cadyyan/void-tool/internal/retention"
+)
+
+type RetentionConfiguration struct {
+	// IntradaySnapshots is how long intraday snapshots, the hourly history, are kept for. After
+	// that they're deleted entirely and only the daily history, which is kept according to
+	// DailyHistory and WeeklyHistory, is left.
+	IntradaySnapshots time.Duration `default:"720h"`
+
+	// PruneFrequency is how often old snapshots are removed.
+	PruneFrequency time.Duration `default:"1h"`
+
+	// DailyHistory is how long every day of a player's history is kept for. After that only the
+	// last day of each week is kept.
+	DailyHistory time.Duration `default:"2160h"`
+
+	// WeeklyHistory is how long the last day of each week is kept for. After that only the last
+	// day of each month is kept.
+	WeeklyHistory time.Duration `default:"8760h"`
+
+	// DownsampleFrequency is how often old history is downsampled.
+	DownsampleFrequency time.Duration `default:"24h"`
+}
+
+func (config RetentionConfiguration) Policy() retention.Policy {
+	return retention.Policy{
+		DailyFor:  config.DailyHistory,
+		WeeklyFor: config.WeeklyHistory,
+	}
+}
@@ -1,16 +1,76 @@
 package configuration
 
 import (
+	"fmt"
 	"io/fs"
 	"os"
 	"time"
+
+	"github.com/kelseyhightower/envconfig"
 )
 
 type RunescapeConfiguration struct {
-	DataDir       string        `required:"true"`
+	// Source is where players are read from. Save files are read from DataDir and Void's
+	// database storage is read from DatabaseURL.
+	Source      PlayerSource `default:"files"`
+	DataDir     string
+	DatabaseURL string
+
+	// World names the world read from Source. Players are tagged with the world they were read
+	// from so renaming it after players have been ingested starts the world over.
+	World string `default:"main"`
+
+	// Worlds are more worlds, like a seasonal or leagues world, that are read from their own
+	// data directories. They're given as comma separated name:directory pairs, e.g.
+	// "leagues:/srv/void-leagues/data".
+	Worlds map[string]string
+
 	PollFrequency time.Duration `default:"5m"`
+
+	// Watch ingests save files as soon as they change instead of polling the whole data
+	// directory. Changes are picked up with inotify where possible and otherwise by checking the
+	// modification times of every save at WatchPollFrequency.
+	Watch              bool          `default:"false"`
+	WatchDebounce      time.Duration `default:"2s"`
+	WatchPollFrequency time.Duration `default:"10s"`
 }
 
 func (config RunescapeConfiguration) DataDirFS() fs.FS {
 	return os.DirFS(config.DataDir)
 }
+
+// WorldDataDir is the directory the named world's save files are read from. It's empty when the
+// world is read from Void's database.
+func (config RunescapeConfiguration) WorldDataDir(world string) (string, bool) {
+	if world == config.World {
+		if config.Source == PlayerSourceFiles {
+			return config.DataDir, true
+		}
+
+		return "", true
+	}
+
+	dataDir, ok := config.Worlds[world]
+
+	return dataDir, ok
+}
+
+type PlayerSource string
+
+const (
+	PlayerSourceFiles    PlayerSource = "files"
+	PlayerSourceDatabase PlayerSource = "database"
+)
+
+var _ envconfig.Decoder = (*PlayerSource)(nil)
+
+func (s *PlayerSource) Decode(value string) error {
+	switch source := PlayerSource(value); source {
+	case PlayerSourceFiles, PlayerSourceDatabase:
+		*s = source
+	default:
+		return fmt.Errorf("invalid player source %q", value)
+	}
+
+	return nil
+}
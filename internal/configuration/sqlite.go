@@ -6,7 +6,8 @@ import (
 )
 
 type SQLiteConfiguration struct {
-	Path string `required:"true"`
+	// Path is required when the storage driver is SQLite.
+	Path string
 }
 
 func (config SQLiteConfiguration) DSN() string {
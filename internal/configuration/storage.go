@@ -0,0 +1,32 @@
+package configuration
+
+import (
+	"fmt"
+
+	"github.com/kelseyhightower/envconfig"
+)
+
+type StorageConfiguration struct {
+	// Driver is the database void-tool keeps its data in.
+	Driver StorageDriver `default:"sqlite"`
+}
+
+type StorageDriver string
+
+const (
+	StorageDriverSQLite   StorageDriver = "sqlite"
+	StorageDriverPostgres StorageDriver = "postgres"
+)
+
+var _ envconfig.Decoder = (*StorageDriver)(nil)
+
+func (d *StorageDriver) Decode(value string) error {
+	switch driver := StorageDriver(value); driver {
+	case StorageDriverSQLite, StorageDriverPostgres:
+		*d = driver
+	default:
+		return fmt.Errorf("invalid storage driver %q", value)
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package configuration
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/kelseyhightower/envconfig"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+)
+
+type TracingConfiguration struct {
+	// Exporter is where traces are sent. The OTLP exporter is configured with the standard
+	// OTEL_EXPORTER_OTLP_* environment variables, e.g. OTEL_EXPORTER_OTLP_ENDPOINT.
+	Exporter TracingExporter `default:"none"`
+
+	// ServiceName is the name traces are reported under. OTEL_SERVICE_NAME takes precedence.
+	ServiceName string `default:"void-tool"`
+
+	// SampleRatio is the fraction of traces that are kept, from 0 to 1. Traces that were started
+	// by a caller that's already tracing follow the caller's decision.
+	SampleRatio float64 `default:"1"`
+}
+
+type TracingExporter string
+
+const (
+	TracingExporterNone   TracingExporter = "none"
+	TracingExporterOTLP   TracingExporter = "otlp"
+	TracingExporterStdout TracingExporter = "stdout"
+)
+
+var _ envconfig.Decoder = (*TracingExporter)(nil)
+
+func (e *TracingExporter) Decode(value string) error {
+	switch exporter := TracingExporter(value); exporter {
+	case TracingExporterNone, TracingExporterOTLP, TracingExporterStdout:
+		*e = exporter
+	default:
+		return fmt.Errorf("invalid tracing exporter %q", value)
+	}
+
+	return nil
+}
+
+// Enabled is whether traces are exported at all.
+func (config TracingConfiguration) Enabled() bool {
+	return config.Exporter != TracingExporterNone
+}
+
+// BuildTracerProvider sets up the configured exporter. The provider has to be shut down to send
+// the last of the traces.
+func (config TracingConfiguration) BuildTracerProvider(ctx context.Context) (*sdktrace.TracerProvider, error) {
+	var (
+		exporter sdktrace.SpanExporter
+		err      error
+	)
+
+	switch config.Exporter {
+	case TracingExporterOTLP:
+		exporter, err = otlptracehttp.New(ctx)
+	case TracingExporterStdout:
+		// Commands print their results to stdout so traces go along with the logs instead
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stderr))
+	case TracingExporterNone:
+		return sdktrace.NewTracerProvider(), nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("unable to create %s trace exporter: %w", config.Exporter, err)
+	}
+
+	serviceResource, err := resource.New(
+		ctx,
+		resource.WithAttributes(semconv.ServiceName(config.ServiceName)),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithHost(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to describe service for traces: %w", err)
+	}
+
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(serviceResource),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+	), nil
+}
@@ -0,0 +1,204 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: ingestion_runs.sql
+
+package postgresdb
+
+import (
+	"context"
+	"time"
+)
+
+const createIngestionRun = `-- name: CreateIngestionRun :exec
+INSERT INTO ingestion_runs (
+    id,
+    world,
+    started_on
+) VALUES (
+    $1,
+    $2,
+    $3
+)
+`
+
+type CreateIngestionRunParams struct {
+	ID        string
+	World     string
+	StartedOn time.Time
+}
+
+func (q *Queries) CreateIngestionRun(ctx context.Context, arg CreateIngestionRunParams) error {
+	_, err := q.db.Exec(ctx, createIngestionRun, arg.ID, arg.World, arg.StartedOn)
+	return err
+}
+
+const deleteIngestionRunsBefore = `-- name: DeleteIngestionRunsBefore :exec
+DELETE FROM ingestion_runs
+WHERE
+    started_on < $1
+`
+
+func (q *Queries) DeleteIngestionRunsBefore(ctx context.Context, startedOn time.Time) error {
+	_, err := q.db.Exec(ctx, deleteIngestionRunsBefore, startedOn)
+	return err
+}
+
+const finishIngestionRun = `-- name: FinishIngestionRun :exec
+UPDATE ingestion_runs
+SET
+    finished_on = $1,
+    files_scanned = $2,
+    players_updated = $3,
+    error = $4
+WHERE
+    id = $5
+`
+
+type FinishIngestionRunParams struct {
+	FinishedOn     *time.Time
+	FilesScanned   int32
+	PlayersUpdated int32
+	Error          *string
+	ID             string
+}
+
+func (q *Queries) FinishIngestionRun(ctx context.Context, arg FinishIngestionRunParams) error {
+	_, err := q.db.Exec(ctx, finishIngestionRun,
+		arg.FinishedOn,
+		arg.FilesScanned,
+		arg.PlayersUpdated,
+		arg.Error,
+		arg.ID,
+	)
+	return err
+}
+
+const getIngestionRunFailures = `-- name: GetIngestionRunFailures :many
+SELECT
+    run_id,
+    file,
+    error
+FROM ingestion_run_failures
+WHERE
+    run_id = ANY($1::VARCHAR [])
+ORDER BY run_id, file
+`
+
+func (q *Queries) GetIngestionRunFailures(ctx context.Context, runIds []string) ([]IngestionRunFailure, error) {
+	rows, err := q.db.Query(ctx, getIngestionRunFailures, runIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []IngestionRunFailure
+	for rows.Next() {
+		var i IngestionRunFailure
+		if err := rows.Scan(&i.RunID, &i.File, &i.Error); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getLastSuccessfulIngestionRun = `-- name: GetLastSuccessfulIngestionRun :one
+SELECT
+    id,
+    started_on,
+    finished_on,
+    files_scanned,
+    players_updated,
+    error,
+    world
+FROM ingestion_runs
+WHERE
+    finished_on IS NOT NULL
+    AND
+    error IS NULL
+ORDER BY started_on DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLastSuccessfulIngestionRun(ctx context.Context) (IngestionRun, error) {
+	row := q.db.QueryRow(ctx, getLastSuccessfulIngestionRun)
+	var i IngestionRun
+	err := row.Scan(
+		&i.ID,
+		&i.StartedOn,
+		&i.FinishedOn,
+		&i.FilesScanned,
+		&i.PlayersUpdated,
+		&i.Error,
+		&i.World,
+	)
+	return i, err
+}
+
+const getRecentIngestionRuns = `-- name: GetRecentIngestionRuns :many
+SELECT
+    id,
+    started_on,
+    finished_on,
+    files_scanned,
+    players_updated,
+    error,
+    world
+FROM ingestion_runs
+ORDER BY started_on DESC
+LIMIT $1
+`
+
+func (q *Queries) GetRecentIngestionRuns(ctx context.Context, limit int32) ([]IngestionRun, error) {
+	rows, err := q.db.Query(ctx, getRecentIngestionRuns, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []IngestionRun
+	for rows.Next() {
+		var i IngestionRun
+		if err := rows.Scan(
+			&i.ID,
+			&i.StartedOn,
+			&i.FinishedOn,
+			&i.FilesScanned,
+			&i.PlayersUpdated,
+			&i.Error,
+			&i.World,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const recordIngestionRunFailure = `-- name: RecordIngestionRunFailure :exec
+INSERT INTO ingestion_run_failures (
+    run_id,
+    file,
+    error
+) VALUES (
+    $1,
+    $2,
+    $3
+)
+`
+
+type RecordIngestionRunFailureParams struct {
+	RunID string
+	File  string
+	Error string
+}
+
+func (q *Queries) RecordIngestionRunFailure(ctx context.Context, arg RecordIngestionRunFailureParams) error {
+	_, err := q.db.Exec(ctx, recordIngestionRunFailure, arg.RunID, arg.File, arg.Error)
+	return err
+}
@@ -0,0 +1,79 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+
+package postgresdb
+
+import (
+	"time"
+)
+
+type IngestionRun struct {
+	ID             string
+	StartedOn      time.Time
+	FinishedOn     *time.Time
+	FilesScanned   int32
+	PlayersUpdated int32
+	Error          *string
+	World          string
+}
+
+type IngestionRunFailure struct {
+	RunID string
+	File  string
+	Error string
+}
+
+type Player struct {
+	ID                 string
+	Username           string
+	CreatedOn          time.Time
+	World              string
+	NormalizedUsername string
+}
+
+type PlayerCombatLevel struct {
+	PlayerID string
+	Day      time.Time
+	Level    int32
+}
+
+type PlayerPreviousName struct {
+	PlayerID           string
+	Username           string
+	RenamedOn          time.Time
+	NormalizedUsername string
+}
+
+type PlayerSkill struct {
+	PlayerID   string
+	Name       string
+	Day        time.Time
+	Level      int32
+	Experience float64
+}
+
+type PlayerSkillSnapshot struct {
+	PlayerID   string
+	Name       string
+	RecordedOn time.Time
+	Level      int32
+	Experience float64
+}
+
+type QuarantinedSaveFile struct {
+	Path        string
+	Hash        string
+	Error       string
+	FirstSeenOn time.Time
+	LastSeenOn  time.Time
+	World       string
+}
+
+type SaveFile struct {
+	Path       string
+	ModifiedOn time.Time
+	Size       int64
+	Hash       string
+	World      string
+}
@@ -0,0 +1,132 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: player_names.sql
+
+package postgresdb
+
+import (
+	"context"
+	"time"
+)
+
+const deletePlayerPreviousName = `-- name: DeletePlayerPreviousName :exec
+DELETE FROM player_previous_names
+WHERE
+    player_id = $1
+    AND
+    normalized_username = $2
+`
+
+type DeletePlayerPreviousNameParams struct {
+	PlayerID           string
+	NormalizedUsername string
+}
+
+func (q *Queries) DeletePlayerPreviousName(ctx context.Context, arg DeletePlayerPreviousNameParams) error {
+	_, err := q.db.Exec(ctx, deletePlayerPreviousName, arg.PlayerID, arg.NormalizedUsername)
+	return err
+}
+
+const getPlayerByPreviousName = `-- name: GetPlayerByPreviousName :one
+SELECT
+    players.id,
+    players.username,
+    players.created_on,
+    players.world,
+    players.normalized_username
+FROM player_previous_names
+INNER JOIN players
+    ON
+        player_previous_names.player_id = players.id
+        AND
+        players.world = $1
+WHERE
+    player_previous_names.normalized_username = $2
+ORDER BY player_previous_names.renamed_on DESC
+LIMIT 1
+`
+
+type GetPlayerByPreviousNameParams struct {
+	World              string
+	NormalizedUsername string
+}
+
+func (q *Queries) GetPlayerByPreviousName(ctx context.Context, arg GetPlayerByPreviousNameParams) (Player, error) {
+	row := q.db.QueryRow(ctx, getPlayerByPreviousName, arg.World, arg.NormalizedUsername)
+	var i Player
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.CreatedOn,
+		&i.World,
+		&i.NormalizedUsername,
+	)
+	return i, err
+}
+
+const getPlayerPreviousNames = `-- name: GetPlayerPreviousNames :many
+SELECT
+    username,
+    renamed_on
+FROM player_previous_names
+WHERE player_id = $1
+ORDER BY renamed_on DESC
+`
+
+type GetPlayerPreviousNamesRow struct {
+	Username  string
+	RenamedOn time.Time
+}
+
+func (q *Queries) GetPlayerPreviousNames(ctx context.Context, playerID string) ([]GetPlayerPreviousNamesRow, error) {
+	rows, err := q.db.Query(ctx, getPlayerPreviousNames, playerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPlayerPreviousNamesRow
+	for rows.Next() {
+		var i GetPlayerPreviousNamesRow
+		if err := rows.Scan(&i.Username, &i.RenamedOn); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const recordPlayerPreviousName = `-- name: RecordPlayerPreviousName :exec
+INSERT INTO player_previous_names (
+    player_id,
+    username,
+    normalized_username,
+    renamed_on
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4
+) ON CONFLICT (player_id, username)
+DO UPDATE SET renamed_on = excluded.renamed_on
+`
+
+type RecordPlayerPreviousNameParams struct {
+	PlayerID           string
+	Username           string
+	NormalizedUsername string
+	RenamedOn          time.Time
+}
+
+func (q *Queries) RecordPlayerPreviousName(ctx context.Context, arg RecordPlayerPreviousNameParams) error {
+	_, err := q.db.Exec(ctx, recordPlayerPreviousName,
+		arg.PlayerID,
+		arg.Username,
+		arg.NormalizedUsername,
+		arg.RenamedOn,
+	)
+	return err
+}
@@ -0,0 +1,741 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: players.sql
+
+package postgresdb
+
+import (
+	"context"
+	"time"
+)
+
+const countHighscoresForSkill = `-- name: CountHighscoresForSkill :one
+SELECT COUNT(DISTINCT player_skills.player_id) AS player_count
+FROM player_skills
+INNER JOIN players
+    ON
+        player_skills.player_id = players.id
+        AND
+        players.world = $1
+WHERE
+    player_skills.name = $2
+`
+
+type CountHighscoresForSkillParams struct {
+	World string
+	Name  string
+}
+
+func (q *Queries) CountHighscoresForSkill(ctx context.Context, arg CountHighscoresForSkillParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countHighscoresForSkill, arg.World, arg.Name)
+	var player_count int64
+	err := row.Scan(&player_count)
+	return player_count, err
+}
+
+const countOverallHighscores = `-- name: CountOverallHighscores :one
+SELECT COUNT(DISTINCT player_skills.player_id) AS player_count
+FROM player_skills
+INNER JOIN players
+    ON
+        player_skills.player_id = players.id
+        AND
+        players.world = $1
+`
+
+func (q *Queries) CountOverallHighscores(ctx context.Context, world string) (int64, error) {
+	row := q.db.QueryRow(ctx, countOverallHighscores, world)
+	var player_count int64
+	err := row.Scan(&player_count)
+	return player_count, err
+}
+
+const createPlayer = `-- name: CreatePlayer :one
+INSERT INTO players (
+    id,
+    world,
+    username,
+    normalized_username,
+    created_on
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+) RETURNING id, username, created_on, world, normalized_username
+`
+
+type CreatePlayerParams struct {
+	ID                 string
+	World              string
+	Username           string
+	NormalizedUsername string
+	CreatedOn          time.Time
+}
+
+func (q *Queries) CreatePlayer(ctx context.Context, arg CreatePlayerParams) (Player, error) {
+	row := q.db.QueryRow(ctx, createPlayer,
+		arg.ID,
+		arg.World,
+		arg.Username,
+		arg.NormalizedUsername,
+		arg.CreatedOn,
+	)
+	var i Player
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.CreatedOn,
+		&i.World,
+		&i.NormalizedUsername,
+	)
+	return i, err
+}
+
+const createPlayerIfNotExist = `-- name: CreatePlayerIfNotExist :exec
+INSERT INTO players (
+    id,
+    world,
+    username,
+    normalized_username,
+    created_on
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+) ON CONFLICT (world, normalized_username) DO NOTHING
+`
+
+type CreatePlayerIfNotExistParams struct {
+	ID                 string
+	World              string
+	Username           string
+	NormalizedUsername string
+	CreatedOn          time.Time
+}
+
+func (q *Queries) CreatePlayerIfNotExist(ctx context.Context, arg CreatePlayerIfNotExistParams) error {
+	_, err := q.db.Exec(ctx, createPlayerIfNotExist,
+		arg.ID,
+		arg.World,
+		arg.Username,
+		arg.NormalizedUsername,
+		arg.CreatedOn,
+	)
+	return err
+}
+
+const getAllPlayerSkillsByPlayerName = `-- name: GetAllPlayerSkillsByPlayerName :many
+SELECT DISTINCT ON (player_skills.name)
+    player_skills.player_id,
+    player_skills.name,
+    player_skills.day,
+    player_skills.experience,
+    player_skills.level
+FROM player_skills
+INNER JOIN players
+    ON
+        player_skills.player_id = players.id
+        AND
+        players.world = $1
+        AND
+        players.normalized_username = $2
+ORDER BY player_skills.name ASC, player_skills.day DESC
+`
+
+type GetAllPlayerSkillsByPlayerNameParams struct {
+	World              string
+	NormalizedUsername string
+}
+
+type GetAllPlayerSkillsByPlayerNameRow struct {
+	PlayerID   string
+	Name       string
+	Day        time.Time
+	Experience float64
+	Level      int32
+}
+
+func (q *Queries) GetAllPlayerSkillsByPlayerName(ctx context.Context, arg GetAllPlayerSkillsByPlayerNameParams) ([]GetAllPlayerSkillsByPlayerNameRow, error) {
+	rows, err := q.db.Query(ctx, getAllPlayerSkillsByPlayerName, arg.World, arg.NormalizedUsername)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetAllPlayerSkillsByPlayerNameRow
+	for rows.Next() {
+		var i GetAllPlayerSkillsByPlayerNameRow
+		if err := rows.Scan(
+			&i.PlayerID,
+			&i.Name,
+			&i.Day,
+			&i.Experience,
+			&i.Level,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAllPlayers = `-- name: GetAllPlayers :many
+SELECT
+    id,
+    username,
+    created_on,
+    world,
+    normalized_username
+FROM players
+`
+
+func (q *Queries) GetAllPlayers(ctx context.Context) ([]Player, error) {
+	rows, err := q.db.Query(ctx, getAllPlayers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Player
+	for rows.Next() {
+		var i Player
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.CreatedOn,
+			&i.World,
+			&i.NormalizedUsername,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getHighscoresForSkill = `-- name: GetHighscoresForSkill :many
+WITH latest_skills_by_player AS (
+    SELECT DISTINCT ON (player_skills.player_id)
+        player_skills.player_id,
+        player_skills.name,
+        player_skills.experience,
+        player_skills.level
+    FROM player_skills
+    WHERE
+        player_skills.name = $3
+    ORDER BY player_skills.player_id ASC, player_skills.day DESC
+),
+
+ranked_skills AS (
+    SELECT
+        players.id,
+        players.username,
+        skills.name,
+        skills.experience,
+        skills.level,
+        RANK() OVER (
+            ORDER BY skills.experience DESC
+        ) AS player_rank
+    FROM latest_skills_by_player AS skills
+    INNER JOIN players
+        ON
+            skills.player_id = players.id
+            AND
+            players.world = $4
+)
+
+SELECT
+    ranked_skills.id,
+    ranked_skills.username,
+    ranked_skills.name,
+    ranked_skills.experience,
+    ranked_skills.level,
+    CAST(ranked_skills.player_rank AS INTEGER) AS player_rank
+FROM ranked_skills
+ORDER BY ranked_skills.player_rank ASC, ranked_skills.username ASC
+LIMIT $2
+OFFSET $1
+`
+
+type GetHighscoresForSkillParams struct {
+	PageOffset int32
+	PageSize   int32
+	Name       string
+	World      string
+}
+
+type GetHighscoresForSkillRow struct {
+	ID         string
+	Username   string
+	Name       string
+	Experience float64
+	Level      int32
+	PlayerRank int32
+}
+
+func (q *Queries) GetHighscoresForSkill(ctx context.Context, arg GetHighscoresForSkillParams) ([]GetHighscoresForSkillRow, error) {
+	rows, err := q.db.Query(ctx, getHighscoresForSkill,
+		arg.PageOffset,
+		arg.PageSize,
+		arg.Name,
+		arg.World,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetHighscoresForSkillRow
+	for rows.Next() {
+		var i GetHighscoresForSkillRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.Name,
+			&i.Experience,
+			&i.Level,
+			&i.PlayerRank,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getOverallHighscores = `-- name: GetOverallHighscores :many
+WITH latest_skills AS (
+    SELECT DISTINCT ON (player_skills.player_id, player_skills.name)
+        player_skills.player_id,
+        player_skills.experience,
+        player_skills.level
+    FROM player_skills
+    ORDER BY player_skills.player_id ASC, player_skills.name ASC, player_skills.day DESC
+),
+
+totals_by_player AS (
+    SELECT
+        latest_skills.player_id,
+        SUM(latest_skills.experience) AS total_experience,
+        SUM(latest_skills.level) AS total_level
+    FROM latest_skills
+    GROUP BY latest_skills.player_id
+),
+
+ranked_totals AS (
+    SELECT
+        players.id,
+        players.username,
+        totals.total_experience,
+        totals.total_level,
+        RANK() OVER (
+            ORDER BY totals.total_level DESC, totals.total_experience DESC
+        ) AS player_rank
+    FROM totals_by_player AS totals
+    INNER JOIN players
+        ON
+            totals.player_id = players.id
+            AND
+            players.world = $3
+)
+
+SELECT
+    ranked_totals.id,
+    ranked_totals.username,
+    CAST(ranked_totals.total_experience AS DOUBLE PRECISION) AS total_experience,
+    CAST(ranked_totals.total_level AS INTEGER) AS total_level,
+    CAST(ranked_totals.player_rank AS INTEGER) AS player_rank
+FROM ranked_totals
+ORDER BY ranked_totals.player_rank ASC, ranked_totals.username ASC
+LIMIT $2
+OFFSET $1
+`
+
+type GetOverallHighscoresParams struct {
+	PageOffset int32
+	PageSize   int32
+	World      string
+}
+
+type GetOverallHighscoresRow struct {
+	ID              string
+	Username        string
+	TotalExperience float64
+	TotalLevel      int32
+	PlayerRank      int32
+}
+
+func (q *Queries) GetOverallHighscores(ctx context.Context, arg GetOverallHighscoresParams) ([]GetOverallHighscoresRow, error) {
+	rows, err := q.db.Query(ctx, getOverallHighscores, arg.PageOffset, arg.PageSize, arg.World)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetOverallHighscoresRow
+	for rows.Next() {
+		var i GetOverallHighscoresRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.TotalExperience,
+			&i.TotalLevel,
+			&i.PlayerRank,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPlayerByID = `-- name: GetPlayerByID :one
+SELECT
+    id,
+    username,
+    created_on,
+    world,
+    normalized_username
+FROM players
+WHERE id = $1
+`
+
+func (q *Queries) GetPlayerByID(ctx context.Context, id string) (Player, error) {
+	row := q.db.QueryRow(ctx, getPlayerByID, id)
+	var i Player
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.CreatedOn,
+		&i.World,
+		&i.NormalizedUsername,
+	)
+	return i, err
+}
+
+const getPlayerByName = `-- name: GetPlayerByName :one
+SELECT
+    id,
+    username,
+    created_on,
+    world,
+    normalized_username
+FROM players
+WHERE
+    world = $1
+    AND
+    normalized_username = $2
+`
+
+type GetPlayerByNameParams struct {
+	World              string
+	NormalizedUsername string
+}
+
+func (q *Queries) GetPlayerByName(ctx context.Context, arg GetPlayerByNameParams) (Player, error) {
+	row := q.db.QueryRow(ctx, getPlayerByName, arg.World, arg.NormalizedUsername)
+	var i Player
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.CreatedOn,
+		&i.World,
+		&i.NormalizedUsername,
+	)
+	return i, err
+}
+
+const getPlayerOverallOverTimeByPlayerName = `-- name: GetPlayerOverallOverTimeByPlayerName :many
+SELECT
+    player_skills.day,
+    CAST(SUM(player_skills.experience) AS DOUBLE PRECISION) AS total_experience,
+    CAST(SUM(player_skills.level) AS INTEGER) AS total_level
+FROM player_skills
+INNER JOIN players
+    ON
+        player_skills.player_id = players.id
+        AND
+        players.world = $1
+        AND
+        players.normalized_username = $2
+WHERE
+    player_skills.day >= $3
+    AND
+    player_skills.day <= $4
+GROUP BY player_skills.day
+ORDER BY player_skills.day ASC
+`
+
+type GetPlayerOverallOverTimeByPlayerNameParams struct {
+	World              string
+	NormalizedUsername string
+	FromDay            time.Time
+	ToDay              time.Time
+}
+
+type GetPlayerOverallOverTimeByPlayerNameRow struct {
+	Day             time.Time
+	TotalExperience float64
+	TotalLevel      int32
+}
+
+func (q *Queries) GetPlayerOverallOverTimeByPlayerName(ctx context.Context, arg GetPlayerOverallOverTimeByPlayerNameParams) ([]GetPlayerOverallOverTimeByPlayerNameRow, error) {
+	rows, err := q.db.Query(ctx, getPlayerOverallOverTimeByPlayerName,
+		arg.World,
+		arg.NormalizedUsername,
+		arg.FromDay,
+		arg.ToDay,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPlayerOverallOverTimeByPlayerNameRow
+	for rows.Next() {
+		var i GetPlayerOverallOverTimeByPlayerNameRow
+		if err := rows.Scan(&i.Day, &i.TotalExperience, &i.TotalLevel); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPlayerSkillOverTimeByPlayerName = `-- name: GetPlayerSkillOverTimeByPlayerName :many
+SELECT
+    player_skills.player_id,
+    player_skills.name,
+    player_skills.day,
+    player_skills.experience,
+    player_skills.level
+FROM player_skills
+INNER JOIN players
+    ON
+        player_skills.player_id = players.id
+        AND
+        players.world = $1
+        AND
+        players.normalized_username = $2
+WHERE
+    player_skills.name = $3
+    AND
+    player_skills.day >= $4
+    AND
+    player_skills.day <= $5
+ORDER BY player_skills.day ASC
+`
+
+type GetPlayerSkillOverTimeByPlayerNameParams struct {
+	World              string
+	NormalizedUsername string
+	Name               string
+	FromDay            time.Time
+	ToDay              time.Time
+}
+
+type GetPlayerSkillOverTimeByPlayerNameRow struct {
+	PlayerID   string
+	Name       string
+	Day        time.Time
+	Experience float64
+	Level      int32
+}
+
+func (q *Queries) GetPlayerSkillOverTimeByPlayerName(ctx context.Context, arg GetPlayerSkillOverTimeByPlayerNameParams) ([]GetPlayerSkillOverTimeByPlayerNameRow, error) {
+	rows, err := q.db.Query(ctx, getPlayerSkillOverTimeByPlayerName,
+		arg.World,
+		arg.NormalizedUsername,
+		arg.Name,
+		arg.FromDay,
+		arg.ToDay,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPlayerSkillOverTimeByPlayerNameRow
+	for rows.Next() {
+		var i GetPlayerSkillOverTimeByPlayerNameRow
+		if err := rows.Scan(
+			&i.PlayerID,
+			&i.Name,
+			&i.Day,
+			&i.Experience,
+			&i.Level,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPlayersByCreatedOn = `-- name: GetPlayersByCreatedOn :many
+SELECT
+    id,
+    username,
+    created_on,
+    world,
+    normalized_username
+FROM players
+WHERE
+    world = $1
+    AND
+    created_on = $2
+ORDER BY username ASC
+`
+
+type GetPlayersByCreatedOnParams struct {
+	World     string
+	CreatedOn time.Time
+}
+
+func (q *Queries) GetPlayersByCreatedOn(ctx context.Context, arg GetPlayersByCreatedOnParams) ([]Player, error) {
+	rows, err := q.db.Query(ctx, getPlayersByCreatedOn, arg.World, arg.CreatedOn)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Player
+	for rows.Next() {
+		var i Player
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.CreatedOn,
+			&i.World,
+			&i.NormalizedUsername,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const recordPlayerSkill = `-- name: RecordPlayerSkill :exec
+INSERT INTO player_skills (
+    player_id,
+    name,
+    day,
+    experience,
+    level
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+) ON CONFLICT (player_id, name, day)
+DO UPDATE SET experience = excluded.experience, level = excluded.level
+`
+
+type RecordPlayerSkillParams struct {
+	PlayerID   string
+	Name       string
+	Day        time.Time
+	Experience float64
+	Level      int32
+}
+
+func (q *Queries) RecordPlayerSkill(ctx context.Context, arg RecordPlayerSkillParams) error {
+	_, err := q.db.Exec(ctx, recordPlayerSkill,
+		arg.PlayerID,
+		arg.Name,
+		arg.Day,
+		arg.Experience,
+		arg.Level,
+	)
+	return err
+}
+
+const renamePlayer = `-- name: RenamePlayer :exec
+UPDATE players
+SET
+    username = $1,
+    normalized_username = $2
+WHERE id = $3
+`
+
+type RenamePlayerParams struct {
+	Username           string
+	NormalizedUsername string
+	ID                 string
+}
+
+func (q *Queries) RenamePlayer(ctx context.Context, arg RenamePlayerParams) error {
+	_, err := q.db.Exec(ctx, renamePlayer, arg.Username, arg.NormalizedUsername, arg.ID)
+	return err
+}
+
+const searchPlayers = `-- name: SearchPlayers :many
+SELECT
+    id,
+    username,
+    created_on,
+    world,
+    normalized_username
+FROM players
+WHERE
+    world = $1
+    AND
+    normalized_username LIKE $2::TEXT ESCAPE '\'
+ORDER BY
+    LENGTH(normalized_username) ASC,
+    normalized_username ASC
+LIMIT $3
+`
+
+type SearchPlayersParams struct {
+	World      string
+	Pattern    string
+	MaxResults int32
+}
+
+func (q *Queries) SearchPlayers(ctx context.Context, arg SearchPlayersParams) ([]Player, error) {
+	rows, err := q.db.Query(ctx, searchPlayers, arg.World, arg.Pattern, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Player
+	for rows.Next() {
+		var i Player
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.CreatedOn,
+			&i.World,
+			&i.NormalizedUsername,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,200 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: save_files.sql
+
+package postgresdb
+
+import (
+	"context"
+	"time"
+)
+
+const getAllSaveFiles = `-- name: GetAllSaveFiles :many
+SELECT
+    path,
+    modified_on,
+    size,
+    hash
+FROM save_files
+WHERE
+    world = $1
+`
+
+type GetAllSaveFilesRow struct {
+	Path       string
+	ModifiedOn time.Time
+	Size       int64
+	Hash       string
+}
+
+func (q *Queries) GetAllSaveFiles(ctx context.Context, world string) ([]GetAllSaveFilesRow, error) {
+	rows, err := q.db.Query(ctx, getAllSaveFiles, world)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetAllSaveFilesRow
+	for rows.Next() {
+		var i GetAllSaveFilesRow
+		if err := rows.Scan(
+			&i.Path,
+			&i.ModifiedOn,
+			&i.Size,
+			&i.Hash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getQuarantinedSaveFiles = `-- name: GetQuarantinedSaveFiles :many
+SELECT
+    world,
+    path,
+    hash,
+    error,
+    first_seen_on,
+    last_seen_on
+FROM quarantined_save_files
+ORDER BY world, path
+`
+
+type GetQuarantinedSaveFilesRow struct {
+	World       string
+	Path        string
+	Hash        string
+	Error       string
+	FirstSeenOn time.Time
+	LastSeenOn  time.Time
+}
+
+func (q *Queries) GetQuarantinedSaveFiles(ctx context.Context) ([]GetQuarantinedSaveFilesRow, error) {
+	rows, err := q.db.Query(ctx, getQuarantinedSaveFiles)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetQuarantinedSaveFilesRow
+	for rows.Next() {
+		var i GetQuarantinedSaveFilesRow
+		if err := rows.Scan(
+			&i.World,
+			&i.Path,
+			&i.Hash,
+			&i.Error,
+			&i.FirstSeenOn,
+			&i.LastSeenOn,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const quarantineSaveFile = `-- name: QuarantineSaveFile :exec
+INSERT INTO quarantined_save_files (
+    world,
+    path,
+    hash,
+    error,
+    first_seen_on,
+    last_seen_on
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $5
+) ON CONFLICT (world, path)
+DO UPDATE SET
+    hash = excluded.hash,
+    error = excluded.error,
+    last_seen_on = excluded.last_seen_on
+`
+
+type QuarantineSaveFileParams struct {
+	World  string
+	Path   string
+	Hash   string
+	Error  string
+	SeenOn time.Time
+}
+
+func (q *Queries) QuarantineSaveFile(ctx context.Context, arg QuarantineSaveFileParams) error {
+	_, err := q.db.Exec(ctx, quarantineSaveFile,
+		arg.World,
+		arg.Path,
+		arg.Hash,
+		arg.Error,
+		arg.SeenOn,
+	)
+	return err
+}
+
+const recordSaveFile = `-- name: RecordSaveFile :exec
+INSERT INTO save_files (
+    world,
+    path,
+    modified_on,
+    size,
+    hash
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+) ON CONFLICT (world, path)
+DO UPDATE SET
+    modified_on = excluded.modified_on,
+    size = excluded.size,
+    hash = excluded.hash
+`
+
+type RecordSaveFileParams struct {
+	World      string
+	Path       string
+	ModifiedOn time.Time
+	Size       int64
+	Hash       string
+}
+
+func (q *Queries) RecordSaveFile(ctx context.Context, arg RecordSaveFileParams) error {
+	_, err := q.db.Exec(ctx, recordSaveFile,
+		arg.World,
+		arg.Path,
+		arg.ModifiedOn,
+		arg.Size,
+		arg.Hash,
+	)
+	return err
+}
+
+const releaseSaveFile = `-- name: ReleaseSaveFile :exec
+DELETE FROM quarantined_save_files
+WHERE
+    world = $1
+    AND
+    path = $2
+`
+
+type ReleaseSaveFileParams struct {
+	World string
+	Path  string
+}
+
+func (q *Queries) ReleaseSaveFile(ctx context.Context, arg ReleaseSaveFileParams) error {
+	_, err := q.db.Exec(ctx, releaseSaveFile, arg.World, arg.Path)
+	return err
+}
@@ -0,0 +1,191 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: snapshots.sql
+
+package postgresdb
+
+import (
+	"context"
+	"time"
+)
+
+const deletePlayerSkillSnapshotsBefore = `-- name: DeletePlayerSkillSnapshotsBefore :execrows
+DELETE FROM player_skill_snapshots
+WHERE recorded_on < $1
+`
+
+func (q *Queries) DeletePlayerSkillSnapshotsBefore(ctx context.Context, recordedOn time.Time) (int64, error) {
+	result, err := q.db.Exec(ctx, deletePlayerSkillSnapshotsBefore, recordedOn)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getPlayerOverallHourlyByPlayerName = `-- name: GetPlayerOverallHourlyByPlayerName :many
+WITH totals AS (
+    SELECT
+        player_skill_snapshots.recorded_on,
+        SUM(player_skill_snapshots.level) AS total_level,
+        SUM(player_skill_snapshots.experience) AS total_experience
+    FROM player_skill_snapshots
+    INNER JOIN players
+        ON
+            player_skill_snapshots.player_id = players.id
+            AND
+            players.world = $1
+            AND
+            players.normalized_username = $2
+    WHERE
+        player_skill_snapshots.recorded_on >= $3
+        AND
+        player_skill_snapshots.recorded_on <= $4
+    GROUP BY player_skill_snapshots.recorded_on
+)
+
+SELECT DISTINCT ON (hour)
+    CAST(DATE_TRUNC('hour', totals.recorded_on) AS TIMESTAMPTZ) AS hour,
+    CAST(totals.total_level AS INTEGER) AS total_level,
+    CAST(totals.total_experience AS DOUBLE PRECISION) AS total_experience
+FROM totals
+ORDER BY hour ASC, totals.recorded_on DESC
+`
+
+type GetPlayerOverallHourlyByPlayerNameParams struct {
+	World              string
+	NormalizedUsername string
+	FromTime           time.Time
+	ToTime             time.Time
+}
+
+type GetPlayerOverallHourlyByPlayerNameRow struct {
+	Hour            time.Time
+	TotalLevel      int32
+	TotalExperience float64
+}
+
+func (q *Queries) GetPlayerOverallHourlyByPlayerName(ctx context.Context, arg GetPlayerOverallHourlyByPlayerNameParams) ([]GetPlayerOverallHourlyByPlayerNameRow, error) {
+	rows, err := q.db.Query(ctx, getPlayerOverallHourlyByPlayerName,
+		arg.World,
+		arg.NormalizedUsername,
+		arg.FromTime,
+		arg.ToTime,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPlayerOverallHourlyByPlayerNameRow
+	for rows.Next() {
+		var i GetPlayerOverallHourlyByPlayerNameRow
+		if err := rows.Scan(&i.Hour, &i.TotalLevel, &i.TotalExperience); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPlayerSkillHourlyByPlayerName = `-- name: GetPlayerSkillHourlyByPlayerName :many
+SELECT DISTINCT ON (hour)
+    CAST(DATE_TRUNC('hour', player_skill_snapshots.recorded_on) AS TIMESTAMPTZ) AS hour,
+    player_skill_snapshots.level,
+    player_skill_snapshots.experience
+FROM player_skill_snapshots
+INNER JOIN players
+    ON
+        player_skill_snapshots.player_id = players.id
+        AND
+        players.world = $1
+        AND
+        players.normalized_username = $2
+WHERE
+    player_skill_snapshots.name = $3
+    AND
+    player_skill_snapshots.recorded_on >= $4
+    AND
+    player_skill_snapshots.recorded_on <= $5
+ORDER BY hour ASC, player_skill_snapshots.recorded_on DESC
+`
+
+type GetPlayerSkillHourlyByPlayerNameParams struct {
+	World              string
+	NormalizedUsername string
+	Name               string
+	FromTime           time.Time
+	ToTime             time.Time
+}
+
+type GetPlayerSkillHourlyByPlayerNameRow struct {
+	Hour       time.Time
+	Level      int32
+	Experience float64
+}
+
+func (q *Queries) GetPlayerSkillHourlyByPlayerName(ctx context.Context, arg GetPlayerSkillHourlyByPlayerNameParams) ([]GetPlayerSkillHourlyByPlayerNameRow, error) {
+	rows, err := q.db.Query(ctx, getPlayerSkillHourlyByPlayerName,
+		arg.World,
+		arg.NormalizedUsername,
+		arg.Name,
+		arg.FromTime,
+		arg.ToTime,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPlayerSkillHourlyByPlayerNameRow
+	for rows.Next() {
+		var i GetPlayerSkillHourlyByPlayerNameRow
+		if err := rows.Scan(&i.Hour, &i.Level, &i.Experience); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const recordPlayerSkillSnapshot = `-- name: RecordPlayerSkillSnapshot :exec
+INSERT INTO player_skill_snapshots (
+    player_id,
+    name,
+    recorded_on,
+    level,
+    experience
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+) ON CONFLICT (player_id, name, recorded_on)
+DO UPDATE SET
+    level = excluded.level,
+    experience = excluded.experience
+`
+
+type RecordPlayerSkillSnapshotParams struct {
+	PlayerID   string
+	Name       string
+	RecordedOn time.Time
+	Level      int32
+	Experience float64
+}
+
+func (q *Queries) RecordPlayerSkillSnapshot(ctx context.Context, arg RecordPlayerSkillSnapshotParams) error {
+	_, err := q.db.Exec(ctx, recordPlayerSkillSnapshot,
+		arg.PlayerID,
+		arg.Name,
+		arg.RecordedOn,
+		arg.Level,
+		arg.Experience,
+	)
+	return err
+}
@@ -4,10 +4,45 @@
 
 package sqlitedb
 
+import (
+	"database/sql"
+)
+
+type IngestionRun struct {
+	ID             string
+	StartedOn      string
+	FinishedOn     sql.NullString
+	FilesScanned   int64
+	PlayersUpdated int64
+	Error          sql.NullString
+	World          string
+}
+
+type IngestionRunFailure struct {
+	RunID string
+	File  string
+	Error string
+}
+
 type Player struct {
-	ID        string
-	Username  string
-	CreatedOn string
+	ID                 string
+	World              string
+	Username           string
+	CreatedOn          string
+	NormalizedUsername string
+}
+
+type PlayerCombatLevel struct {
+	PlayerID string
+	Day      string
+	Level    int64
+}
+
+type PlayerPreviousName struct {
+	PlayerID           string
+	Username           string
+	RenamedOn          string
+	NormalizedUsername string
 }
 
 type PlayerSkill struct {
@@ -17,3 +52,28 @@ type PlayerSkill struct {
 	Level      int64
 	Experience float64
 }
+
+type PlayerSkillSnapshot struct {
+	PlayerID   string
+	Name       string
+	RecordedOn string
+	Level      int64
+	Experience float64
+}
+
+type QuarantinedSaveFile struct {
+	World       string
+	Path        string
+	Hash        string
+	Error       string
+	FirstSeenOn string
+	LastSeenOn  string
+}
+
+type SaveFile struct {
+	World      string
+	Path       string
+	ModifiedOn string
+	Size       int64
+	Hash       string
+}
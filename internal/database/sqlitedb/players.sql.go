@@ -9,37 +9,100 @@ import (
 	"context"
 )
 
+const countHighscoresForSkill = `-- name: CountHighscoresForSkill :one
+SELECT COUNT(DISTINCT player_skills.player_id) AS player_count
+FROM player_skills
+INNER JOIN players
+    ON
+        player_skills.player_id = players.id
+        AND
+        players.world = ?1
+WHERE
+    player_skills.name = ?2
+`
+
+type CountHighscoresForSkillParams struct {
+	World string
+	Name  string
+}
+
+func (q *Queries) CountHighscoresForSkill(ctx context.Context, arg CountHighscoresForSkillParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countHighscoresForSkill, arg.World, arg.Name)
+	var player_count int64
+	err := row.Scan(&player_count)
+	return player_count, err
+}
+
+const countOverallHighscores = `-- name: CountOverallHighscores :one
+SELECT COUNT(DISTINCT player_skills.player_id) AS player_count
+FROM player_skills
+INNER JOIN players
+    ON
+        player_skills.player_id = players.id
+        AND
+        players.world = ?1
+`
+
+func (q *Queries) CountOverallHighscores(ctx context.Context, world string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countOverallHighscores, world)
+	var player_count int64
+	err := row.Scan(&player_count)
+	return player_count, err
+}
+
 const createPlayer = `-- name: CreatePlayer :one
 INSERT INTO players (
     id,
+    world,
     username,
+    normalized_username,
     created_on
 ) VALUES (
+    ?,
+    ?,
     ?,
     ?,
     ?
-) RETURNING id, username, created_on
+) RETURNING id, world, username, created_on, normalized_username
 `
 
 type CreatePlayerParams struct {
-	ID        string
-	Username  string
-	CreatedOn string
+	ID                 string
+	World              string
+	Username           string
+	NormalizedUsername string
+	CreatedOn          string
 }
 
 func (q *Queries) CreatePlayer(ctx context.Context, arg CreatePlayerParams) (Player, error) {
-	row := q.db.QueryRowContext(ctx, createPlayer, arg.ID, arg.Username, arg.CreatedOn)
+	row := q.db.QueryRowContext(ctx, createPlayer,
+		arg.ID,
+		arg.World,
+		arg.Username,
+		arg.NormalizedUsername,
+		arg.CreatedOn,
+	)
 	var i Player
-	err := row.Scan(&i.ID, &i.Username, &i.CreatedOn)
+	err := row.Scan(
+		&i.ID,
+		&i.World,
+		&i.Username,
+		&i.CreatedOn,
+		&i.NormalizedUsername,
+	)
 	return i, err
 }
 
 const createPlayerIfNotExist = `-- name: CreatePlayerIfNotExist :exec
 INSERT OR IGNORE INTO players (
     id,
+    world,
     username,
+    normalized_username,
     created_on
 ) VALUES (
+    ?,
+    ?,
     ?,
     ?,
     ?
@@ -47,13 +110,21 @@ INSERT OR IGNORE INTO players (
 `
 
 type CreatePlayerIfNotExistParams struct {
-	ID        string
-	Username  string
-	CreatedOn string
+	ID                 string
+	World              string
+	Username           string
+	NormalizedUsername string
+	CreatedOn          string
 }
 
 func (q *Queries) CreatePlayerIfNotExist(ctx context.Context, arg CreatePlayerIfNotExistParams) error {
-	_, err := q.db.ExecContext(ctx, createPlayerIfNotExist, arg.ID, arg.Username, arg.CreatedOn)
+	_, err := q.db.ExecContext(ctx, createPlayerIfNotExist,
+		arg.ID,
+		arg.World,
+		arg.Username,
+		arg.NormalizedUsername,
+		arg.CreatedOn,
+	)
 	return err
 }
 
@@ -74,7 +145,9 @@ WITH ordered_skill_entries AS (
         ON
             player_skills.player_id = players.id
             AND
-            players.username = ?
+            players.world = ?1
+            AND
+            players.normalized_username = ?2
 )
 
 SELECT
@@ -88,6 +161,11 @@ WHERE
     ordered_skill_entries.row_num = 1
 `
 
+type GetAllPlayerSkillsByPlayerNameParams struct {
+	World              string
+	NormalizedUsername string
+}
+
 type GetAllPlayerSkillsByPlayerNameRow struct {
 	PlayerID   string
 	Name       string
@@ -96,8 +174,8 @@ type GetAllPlayerSkillsByPlayerNameRow struct {
 	Level      int64
 }
 
-func (q *Queries) GetAllPlayerSkillsByPlayerName(ctx context.Context, username string) ([]GetAllPlayerSkillsByPlayerNameRow, error) {
-	rows, err := q.db.QueryContext(ctx, getAllPlayerSkillsByPlayerName, username)
+func (q *Queries) GetAllPlayerSkillsByPlayerName(ctx context.Context, arg GetAllPlayerSkillsByPlayerNameParams) ([]GetAllPlayerSkillsByPlayerNameRow, error) {
+	rows, err := q.db.QueryContext(ctx, getAllPlayerSkillsByPlayerName, arg.World, arg.NormalizedUsername)
 	if err != nil {
 		return nil, err
 	}
@@ -128,8 +206,10 @@ func (q *Queries) GetAllPlayerSkillsByPlayerName(ctx context.Context, username s
 const getAllPlayers = `-- name: GetAllPlayers :many
 SELECT
     id,
+    world,
     username,
-    created_on
+    created_on,
+    normalized_username
 FROM players
 `
 
@@ -142,7 +222,13 @@ func (q *Queries) GetAllPlayers(ctx context.Context) ([]Player, error) {
 	var items []Player
 	for rows.Next() {
 		var i Player
-		if err := rows.Scan(&i.ID, &i.Username, &i.CreatedOn); err != nil {
+		if err := rows.Scan(
+			&i.ID,
+			&i.World,
+			&i.Username,
+			&i.CreatedOn,
+			&i.NormalizedUsername,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -181,32 +267,63 @@ latest_skills_by_player AS (
             AND
             player_skills.day = latest_row.latest_day
     WHERE
-        player_skills.name = ?
+        player_skills.name = ?3
+),
+
+ranked_skills AS (
+    SELECT
+        players.id,
+        players.username,
+        skills.name,
+        skills.experience,
+        skills.level,
+        RANK() OVER (
+            ORDER BY skills.experience DESC
+        ) AS player_rank
+    FROM latest_skills_by_player AS skills
+    INNER JOIN players
+        ON
+            skills.player_id = players.id
+            AND
+            players.world = ?4
 )
 
 SELECT
-    players.id,
-    players.username,
-    skills.name,
-    skills.experience,
-    skills.level
-FROM latest_skills_by_player AS skills
-INNER JOIN players
-    ON
-        skills.player_id = players.id
-ORDER BY skills.experience DESC
+    ranked_skills.id,
+    ranked_skills.username,
+    ranked_skills.name,
+    ranked_skills.experience,
+    ranked_skills.level,
+    CAST(ranked_skills.player_rank AS INTEGER) AS player_rank
+FROM ranked_skills
+ORDER BY ranked_skills.player_rank ASC, ranked_skills.username ASC
+LIMIT ?2
+OFFSET ?1
 `
 
+type GetHighscoresForSkillParams struct {
+	PageOffset int64
+	PageSize   int64
+	Name       string
+	World      string
+}
+
 type GetHighscoresForSkillRow struct {
 	ID         string
 	Username   string
 	Name       string
 	Experience float64
 	Level      int64
+	PlayerRank int64
 }
 
-func (q *Queries) GetHighscoresForSkill(ctx context.Context, name string) ([]GetHighscoresForSkillRow, error) {
-	rows, err := q.db.QueryContext(ctx, getHighscoresForSkill, name)
+func (q *Queries) GetHighscoresForSkill(ctx context.Context, arg GetHighscoresForSkillParams) ([]GetHighscoresForSkillRow, error) {
+	rows, err := q.db.QueryContext(ctx, getHighscoresForSkill,
+		arg.PageOffset,
+		arg.PageSize,
+		arg.Name,
+		arg.World,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -220,6 +337,7 @@ func (q *Queries) GetHighscoresForSkill(ctx context.Context, name string) ([]Get
 			&i.Name,
 			&i.Experience,
 			&i.Level,
+			&i.PlayerRank,
 		); err != nil {
 			return nil, err
 		}
@@ -234,23 +352,222 @@ func (q *Queries) GetHighscoresForSkill(ctx context.Context, name string) ([]Get
 	return items, nil
 }
 
+const getOverallHighscores = `-- name: GetOverallHighscores :many
+WITH latest_row AS (
+    SELECT
+        player_id,
+        name,
+        MAX(day) AS latest_day
+    FROM player_skills
+    GROUP BY player_id, name
+),
+
+totals_by_player AS (
+    SELECT
+        player_skills.player_id,
+        SUM(player_skills.experience) AS total_experience,
+        SUM(player_skills.level) AS total_level
+    FROM player_skills
+    INNER JOIN latest_row
+        ON
+            player_skills.player_id = latest_row.player_id
+            AND
+            player_skills.name = latest_row.name
+            AND
+            player_skills.day = latest_row.latest_day
+    GROUP BY player_skills.player_id
+),
+
+ranked_totals AS (
+    SELECT
+        players.id,
+        players.username,
+        totals.total_experience,
+        totals.total_level,
+        RANK() OVER (
+            ORDER BY totals.total_level DESC, totals.total_experience DESC
+        ) AS player_rank
+    FROM totals_by_player AS totals
+    INNER JOIN players
+        ON
+            totals.player_id = players.id
+            AND
+            players.world = ?3
+)
+
+SELECT
+    ranked_totals.id,
+    ranked_totals.username,
+    CAST(ranked_totals.total_experience AS REAL) AS total_experience,
+    CAST(ranked_totals.total_level AS INTEGER) AS total_level,
+    CAST(ranked_totals.player_rank AS INTEGER) AS player_rank
+FROM ranked_totals
+ORDER BY ranked_totals.player_rank ASC, ranked_totals.username ASC
+LIMIT ?2
+OFFSET ?1
+`
+
+type GetOverallHighscoresParams struct {
+	PageOffset int64
+	PageSize   int64
+	World      string
+}
+
+type GetOverallHighscoresRow struct {
+	ID              string
+	Username        string
+	TotalExperience float64
+	TotalLevel      int64
+	PlayerRank      int64
+}
+
+func (q *Queries) GetOverallHighscores(ctx context.Context, arg GetOverallHighscoresParams) ([]GetOverallHighscoresRow, error) {
+	rows, err := q.db.QueryContext(ctx, getOverallHighscores, arg.PageOffset, arg.PageSize, arg.World)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetOverallHighscoresRow
+	for rows.Next() {
+		var i GetOverallHighscoresRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Username,
+			&i.TotalExperience,
+			&i.TotalLevel,
+			&i.PlayerRank,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPlayerByID = `-- name: GetPlayerByID :one
+SELECT
+    id,
+    world,
+    username,
+    created_on,
+    normalized_username
+FROM players
+WHERE id = ?1
+`
+
+func (q *Queries) GetPlayerByID(ctx context.Context, id string) (Player, error) {
+	row := q.db.QueryRowContext(ctx, getPlayerByID, id)
+	var i Player
+	err := row.Scan(
+		&i.ID,
+		&i.World,
+		&i.Username,
+		&i.CreatedOn,
+		&i.NormalizedUsername,
+	)
+	return i, err
+}
+
 const getPlayerByName = `-- name: GetPlayerByName :one
 SELECT
     id,
+    world,
     username,
-    created_on
+    created_on,
+    normalized_username
 FROM players
 WHERE
-    username = ?
+    world = ?1
+    AND
+    normalized_username = ?2
 `
 
-func (q *Queries) GetPlayerByName(ctx context.Context, username string) (Player, error) {
-	row := q.db.QueryRowContext(ctx, getPlayerByName, username)
+type GetPlayerByNameParams struct {
+	World              string
+	NormalizedUsername string
+}
+
+func (q *Queries) GetPlayerByName(ctx context.Context, arg GetPlayerByNameParams) (Player, error) {
+	row := q.db.QueryRowContext(ctx, getPlayerByName, arg.World, arg.NormalizedUsername)
 	var i Player
-	err := row.Scan(&i.ID, &i.Username, &i.CreatedOn)
+	err := row.Scan(
+		&i.ID,
+		&i.World,
+		&i.Username,
+		&i.CreatedOn,
+		&i.NormalizedUsername,
+	)
 	return i, err
 }
 
+const getPlayerOverallOverTimeByPlayerName = `-- name: GetPlayerOverallOverTimeByPlayerName :many
+SELECT
+    player_skills.day,
+    CAST(SUM(player_skills.experience) AS REAL) AS total_experience,
+    CAST(SUM(player_skills.level) AS INTEGER) AS total_level
+FROM player_skills
+INNER JOIN players
+    ON
+        player_skills.player_id = players.id
+        AND
+        players.world = ?1
+        AND
+        players.normalized_username = ?2
+WHERE
+    player_skills.day >= ?3
+    AND
+    player_skills.day <= ?4
+GROUP BY player_skills.day
+ORDER BY player_skills.day ASC
+`
+
+type GetPlayerOverallOverTimeByPlayerNameParams struct {
+	World              string
+	NormalizedUsername string
+	FromDay            string
+	ToDay              string
+}
+
+type GetPlayerOverallOverTimeByPlayerNameRow struct {
+	Day             string
+	TotalExperience float64
+	TotalLevel      int64
+}
+
+func (q *Queries) GetPlayerOverallOverTimeByPlayerName(ctx context.Context, arg GetPlayerOverallOverTimeByPlayerNameParams) ([]GetPlayerOverallOverTimeByPlayerNameRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPlayerOverallOverTimeByPlayerName,
+		arg.World,
+		arg.NormalizedUsername,
+		arg.FromDay,
+		arg.ToDay,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPlayerOverallOverTimeByPlayerNameRow
+	for rows.Next() {
+		var i GetPlayerOverallOverTimeByPlayerNameRow
+		if err := rows.Scan(&i.Day, &i.TotalExperience, &i.TotalLevel); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getPlayerSkillOverTimeByPlayerName = `-- name: GetPlayerSkillOverTimeByPlayerName :many
 SELECT
     player_skills.player_id,
@@ -263,15 +580,24 @@ INNER JOIN players
     ON
         player_skills.player_id = players.id
         AND
-        players.username = ?
+        players.world = ?1
+        AND
+        players.normalized_username = ?2
 WHERE
-    player_skills.name = ?
+    player_skills.name = ?3
+    AND
+    player_skills.day >= ?4
+    AND
+    player_skills.day <= ?5
 ORDER BY player_skills.day ASC
 `
 
 type GetPlayerSkillOverTimeByPlayerNameParams struct {
-	Username string
-	Name     string
+	World              string
+	NormalizedUsername string
+	Name               string
+	FromDay            string
+	ToDay              string
 }
 
 type GetPlayerSkillOverTimeByPlayerNameRow struct {
@@ -283,7 +609,13 @@ type GetPlayerSkillOverTimeByPlayerNameRow struct {
 }
 
 func (q *Queries) GetPlayerSkillOverTimeByPlayerName(ctx context.Context, arg GetPlayerSkillOverTimeByPlayerNameParams) ([]GetPlayerSkillOverTimeByPlayerNameRow, error) {
-	rows, err := q.db.QueryContext(ctx, getPlayerSkillOverTimeByPlayerName, arg.Username, arg.Name)
+	rows, err := q.db.QueryContext(ctx, getPlayerSkillOverTimeByPlayerName,
+		arg.World,
+		arg.NormalizedUsername,
+		arg.Name,
+		arg.FromDay,
+		arg.ToDay,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -311,6 +643,55 @@ func (q *Queries) GetPlayerSkillOverTimeByPlayerName(ctx context.Context, arg Ge
 	return items, nil
 }
 
+const getPlayersByCreatedOn = `-- name: GetPlayersByCreatedOn :many
+SELECT
+    id,
+    world,
+    username,
+    created_on,
+    normalized_username
+FROM players
+WHERE
+    world = ?1
+    AND
+    created_on = ?2
+ORDER BY username ASC
+`
+
+type GetPlayersByCreatedOnParams struct {
+	World     string
+	CreatedOn string
+}
+
+func (q *Queries) GetPlayersByCreatedOn(ctx context.Context, arg GetPlayersByCreatedOnParams) ([]Player, error) {
+	rows, err := q.db.QueryContext(ctx, getPlayersByCreatedOn, arg.World, arg.CreatedOn)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Player
+	for rows.Next() {
+		var i Player
+		if err := rows.Scan(
+			&i.ID,
+			&i.World,
+			&i.Username,
+			&i.CreatedOn,
+			&i.NormalizedUsername,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const recordPlayerSkill = `-- name: RecordPlayerSkill :exec
 INSERT INTO player_skills (
     player_id,
@@ -346,3 +727,75 @@ func (q *Queries) RecordPlayerSkill(ctx context.Context, arg RecordPlayerSkillPa
 	)
 	return err
 }
+
+const renamePlayer = `-- name: RenamePlayer :exec
+UPDATE players
+SET
+    username = ?1,
+    normalized_username = ?2
+WHERE id = ?3
+`
+
+type RenamePlayerParams struct {
+	Username           string
+	NormalizedUsername string
+	ID                 string
+}
+
+func (q *Queries) RenamePlayer(ctx context.Context, arg RenamePlayerParams) error {
+	_, err := q.db.ExecContext(ctx, renamePlayer, arg.Username, arg.NormalizedUsername, arg.ID)
+	return err
+}
+
+const searchPlayers = `-- name: SearchPlayers :many
+SELECT
+    id,
+    world,
+    username,
+    created_on,
+    normalized_username
+FROM players
+WHERE
+    world = ?1
+    AND
+    LIKE(?2, normalized_username, '\')
+ORDER BY
+    LENGTH(normalized_username) ASC,
+    normalized_username ASC
+LIMIT ?3
+`
+
+type SearchPlayersParams struct {
+	World      string
+	Pattern    string
+	MaxResults int64
+}
+
+func (q *Queries) SearchPlayers(ctx context.Context, arg SearchPlayersParams) ([]Player, error) {
+	rows, err := q.db.QueryContext(ctx, searchPlayers, arg.World, arg.Pattern, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Player
+	for rows.Next() {
+		var i Player
+		if err := rows.Scan(
+			&i.ID,
+			&i.World,
+			&i.Username,
+			&i.CreatedOn,
+			&i.NormalizedUsername,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
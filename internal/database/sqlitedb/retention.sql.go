@@ -0,0 +1,92 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: retention.sql
+
+package sqlitedb
+
+import (
+	"context"
+)
+
+const deletePlayerCombatLevelOnDay = `-- name: DeletePlayerCombatLevelOnDay :exec
+DELETE FROM player_combat_levels
+WHERE
+    player_id = ?
+    AND
+    day = ?
+`
+
+type DeletePlayerCombatLevelOnDayParams struct {
+	PlayerID string
+	Day      string
+}
+
+func (q *Queries) DeletePlayerCombatLevelOnDay(ctx context.Context, arg DeletePlayerCombatLevelOnDayParams) error {
+	_, err := q.db.ExecContext(ctx, deletePlayerCombatLevelOnDay, arg.PlayerID, arg.Day)
+	return err
+}
+
+const deletePlayerSkillsOnDay = `-- name: DeletePlayerSkillsOnDay :exec
+DELETE FROM player_skills
+WHERE
+    player_id = ?
+    AND
+    day = ?
+`
+
+type DeletePlayerSkillsOnDayParams struct {
+	PlayerID string
+	Day      string
+}
+
+func (q *Queries) DeletePlayerSkillsOnDay(ctx context.Context, arg DeletePlayerSkillsOnDayParams) error {
+	_, err := q.db.ExecContext(ctx, deletePlayerSkillsOnDay, arg.PlayerID, arg.Day)
+	return err
+}
+
+const getPlayerSkillDaysByPlayerID = `-- name: GetPlayerSkillDaysByPlayerID :many
+SELECT
+    day,
+    name,
+    level,
+    experience
+FROM player_skills
+WHERE player_id = ?
+ORDER BY day ASC, name ASC
+`
+
+type GetPlayerSkillDaysByPlayerIDRow struct {
+	Day        string
+	Name       string
+	Level      int64
+	Experience float64
+}
+
+func (q *Queries) GetPlayerSkillDaysByPlayerID(ctx context.Context, playerID string) ([]GetPlayerSkillDaysByPlayerIDRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPlayerSkillDaysByPlayerID, playerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPlayerSkillDaysByPlayerIDRow
+	for rows.Next() {
+		var i GetPlayerSkillDaysByPlayerIDRow
+		if err := rows.Scan(
+			&i.Day,
+			&i.Name,
+			&i.Level,
+			&i.Experience,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
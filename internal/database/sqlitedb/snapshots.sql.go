@@ -0,0 +1,222 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: snapshots.sql
+
+package sqlitedb
+
+import (
+	"context"
+)
+
+const deletePlayerSkillSnapshotsBefore = `-- name: DeletePlayerSkillSnapshotsBefore :execrows
+DELETE FROM player_skill_snapshots
+WHERE recorded_on < ?
+`
+
+func (q *Queries) DeletePlayerSkillSnapshotsBefore(ctx context.Context, recordedOn string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deletePlayerSkillSnapshotsBefore, recordedOn)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getPlayerOverallHourlyByPlayerName = `-- name: GetPlayerOverallHourlyByPlayerName :many
+WITH totals AS (
+    SELECT
+        player_skill_snapshots.recorded_on,
+        SUM(player_skill_snapshots.level) AS total_level,
+        SUM(player_skill_snapshots.experience) AS total_experience
+    FROM player_skill_snapshots
+    INNER JOIN players
+        ON
+            player_skill_snapshots.player_id = players.id
+            AND
+            players.world = ?1
+            AND
+            players.normalized_username = ?2
+    WHERE
+        player_skill_snapshots.recorded_on >= ?3
+        AND
+        player_skill_snapshots.recorded_on <= ?4
+    GROUP BY player_skill_snapshots.recorded_on
+),
+
+hourly_totals AS (
+    SELECT
+        recorded_on,
+        total_level,
+        total_experience,
+        ROW_NUMBER() OVER (
+            PARTITION BY strftime('%Y-%m-%dT%H', recorded_on)
+            ORDER BY recorded_on DESC
+        ) AS position
+    FROM totals
+)
+
+SELECT
+    CAST(strftime('%Y-%m-%dT%H:00:00Z', recorded_on) AS TEXT) AS hour,
+    CAST(total_level AS INTEGER) AS total_level,
+    CAST(total_experience AS REAL) AS total_experience
+FROM hourly_totals
+WHERE position = 1
+ORDER BY hour ASC
+`
+
+type GetPlayerOverallHourlyByPlayerNameParams struct {
+	World              string
+	NormalizedUsername string
+	FromTime           string
+	ToTime             string
+}
+
+type GetPlayerOverallHourlyByPlayerNameRow struct {
+	Hour            string
+	TotalLevel      int64
+	TotalExperience float64
+}
+
+func (q *Queries) GetPlayerOverallHourlyByPlayerName(ctx context.Context, arg GetPlayerOverallHourlyByPlayerNameParams) ([]GetPlayerOverallHourlyByPlayerNameRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPlayerOverallHourlyByPlayerName,
+		arg.World,
+		arg.NormalizedUsername,
+		arg.FromTime,
+		arg.ToTime,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPlayerOverallHourlyByPlayerNameRow
+	for rows.Next() {
+		var i GetPlayerOverallHourlyByPlayerNameRow
+		if err := rows.Scan(&i.Hour, &i.TotalLevel, &i.TotalExperience); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPlayerSkillHourlyByPlayerName = `-- name: GetPlayerSkillHourlyByPlayerName :many
+WITH snapshots_in_range AS (
+    SELECT
+        player_skill_snapshots.recorded_on,
+        player_skill_snapshots.level,
+        player_skill_snapshots.experience,
+        ROW_NUMBER() OVER (
+            PARTITION BY strftime('%Y-%m-%dT%H', player_skill_snapshots.recorded_on)
+            ORDER BY player_skill_snapshots.recorded_on DESC
+        ) AS position
+    FROM player_skill_snapshots
+    INNER JOIN players
+        ON
+            player_skill_snapshots.player_id = players.id
+            AND
+            players.world = ?1
+            AND
+            players.normalized_username = ?2
+    WHERE
+        player_skill_snapshots.name = ?3
+        AND
+        player_skill_snapshots.recorded_on >= ?4
+        AND
+        player_skill_snapshots.recorded_on <= ?5
+)
+
+SELECT
+    CAST(strftime('%Y-%m-%dT%H:00:00Z', recorded_on) AS TEXT) AS hour,
+    level,
+    experience
+FROM snapshots_in_range
+WHERE position = 1
+ORDER BY hour ASC
+`
+
+type GetPlayerSkillHourlyByPlayerNameParams struct {
+	World              string
+	NormalizedUsername string
+	Name               string
+	FromTime           string
+	ToTime             string
+}
+
+type GetPlayerSkillHourlyByPlayerNameRow struct {
+	Hour       string
+	Level      int64
+	Experience float64
+}
+
+func (q *Queries) GetPlayerSkillHourlyByPlayerName(ctx context.Context, arg GetPlayerSkillHourlyByPlayerNameParams) ([]GetPlayerSkillHourlyByPlayerNameRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPlayerSkillHourlyByPlayerName,
+		arg.World,
+		arg.NormalizedUsername,
+		arg.Name,
+		arg.FromTime,
+		arg.ToTime,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPlayerSkillHourlyByPlayerNameRow
+	for rows.Next() {
+		var i GetPlayerSkillHourlyByPlayerNameRow
+		if err := rows.Scan(&i.Hour, &i.Level, &i.Experience); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const recordPlayerSkillSnapshot = `-- name: RecordPlayerSkillSnapshot :exec
+INSERT INTO player_skill_snapshots (
+    player_id,
+    name,
+    recorded_on,
+    level,
+    experience
+) VALUES (
+    ?,
+    ?,
+    ?,
+    ?,
+    ?
+) ON CONFLICT (player_id, name, recorded_on)
+DO UPDATE SET
+    level = excluded.level,
+    experience = excluded.experience
+`
+
+type RecordPlayerSkillSnapshotParams struct {
+	PlayerID   string
+	Name       string
+	RecordedOn string
+	Level      int64
+	Experience float64
+}
+
+func (q *Queries) RecordPlayerSkillSnapshot(ctx context.Context, arg RecordPlayerSkillSnapshotParams) error {
+	_, err := q.db.ExecContext(ctx, recordPlayerSkillSnapshot,
+		arg.PlayerID,
+		arg.Name,
+		arg.RecordedOn,
+		arg.Level,
+		arg.Experience,
+	)
+	return err
+}
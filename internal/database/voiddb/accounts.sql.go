@@ -0,0 +1,200 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: accounts.sql
+
+package voiddb
+
+import (
+	"context"
+)
+
+const getAccountByName = `-- name: GetAccountByName :one
+SELECT
+    accounts.name,
+    experience.player_id, experience.attack, experience.defence, experience.strength, experience.constitution, experience.ranged, experience.prayer, experience.magic, experience.cooking, experience.woodcutting, experience.fletching, experience.fishing, experience.firemaking, experience.crafting, experience.smithing, experience.mining, experience.herblore, experience.agility, experience.thieving, experience.slayer, experience.farming, experience.runecrafting, experience.hunter, experience.construction, experience.summoning, experience.dungeoneering,
+    levels.player_id, levels.attack, levels.defence, levels.strength, levels.constitution, levels.ranged, levels.prayer, levels.magic, levels.cooking, levels.woodcutting, levels.fletching, levels.fishing, levels.firemaking, levels.crafting, levels.smithing, levels.mining, levels.herblore, levels.agility, levels.thieving, levels.slayer, levels.farming, levels.runecrafting, levels.hunter, levels.construction, levels.summoning, levels.dungeoneering,
+    COALESCE(variables.long_value, 0)::BIGINT AS creation
+FROM accounts
+INNER JOIN experience
+    ON accounts.id = experience.player_id
+INNER JOIN levels
+    ON accounts.id = levels.player_id
+LEFT JOIN variables
+    ON
+        accounts.id = variables.player_id
+        AND
+        variables.name = 'creation'
+WHERE
+    LOWER(accounts.name) = LOWER($1)
+`
+
+type GetAccountByNameRow struct {
+	Name       string
+	Experience Experience
+	Level      Level
+	Creation   int64
+}
+
+func (q *Queries) GetAccountByName(ctx context.Context, name string) (GetAccountByNameRow, error) {
+	row := q.db.QueryRow(ctx, getAccountByName, name)
+	var i GetAccountByNameRow
+	err := row.Scan(
+		&i.Name,
+		&i.Experience.PlayerID,
+		&i.Experience.Attack,
+		&i.Experience.Defence,
+		&i.Experience.Strength,
+		&i.Experience.Constitution,
+		&i.Experience.Ranged,
+		&i.Experience.Prayer,
+		&i.Experience.Magic,
+		&i.Experience.Cooking,
+		&i.Experience.Woodcutting,
+		&i.Experience.Fletching,
+		&i.Experience.Fishing,
+		&i.Experience.Firemaking,
+		&i.Experience.Crafting,
+		&i.Experience.Smithing,
+		&i.Experience.Mining,
+		&i.Experience.Herblore,
+		&i.Experience.Agility,
+		&i.Experience.Thieving,
+		&i.Experience.Slayer,
+		&i.Experience.Farming,
+		&i.Experience.Runecrafting,
+		&i.Experience.Hunter,
+		&i.Experience.Construction,
+		&i.Experience.Summoning,
+		&i.Experience.Dungeoneering,
+		&i.Level.PlayerID,
+		&i.Level.Attack,
+		&i.Level.Defence,
+		&i.Level.Strength,
+		&i.Level.Constitution,
+		&i.Level.Ranged,
+		&i.Level.Prayer,
+		&i.Level.Magic,
+		&i.Level.Cooking,
+		&i.Level.Woodcutting,
+		&i.Level.Fletching,
+		&i.Level.Fishing,
+		&i.Level.Firemaking,
+		&i.Level.Crafting,
+		&i.Level.Smithing,
+		&i.Level.Mining,
+		&i.Level.Herblore,
+		&i.Level.Agility,
+		&i.Level.Thieving,
+		&i.Level.Slayer,
+		&i.Level.Farming,
+		&i.Level.Runecrafting,
+		&i.Level.Hunter,
+		&i.Level.Construction,
+		&i.Level.Summoning,
+		&i.Level.Dungeoneering,
+		&i.Creation,
+	)
+	return i, err
+}
+
+const getAllAccounts = `-- name: GetAllAccounts :many
+SELECT
+    accounts.name,
+    experience.player_id, experience.attack, experience.defence, experience.strength, experience.constitution, experience.ranged, experience.prayer, experience.magic, experience.cooking, experience.woodcutting, experience.fletching, experience.fishing, experience.firemaking, experience.crafting, experience.smithing, experience.mining, experience.herblore, experience.agility, experience.thieving, experience.slayer, experience.farming, experience.runecrafting, experience.hunter, experience.construction, experience.summoning, experience.dungeoneering,
+    levels.player_id, levels.attack, levels.defence, levels.strength, levels.constitution, levels.ranged, levels.prayer, levels.magic, levels.cooking, levels.woodcutting, levels.fletching, levels.fishing, levels.firemaking, levels.crafting, levels.smithing, levels.mining, levels.herblore, levels.agility, levels.thieving, levels.slayer, levels.farming, levels.runecrafting, levels.hunter, levels.construction, levels.summoning, levels.dungeoneering,
+    COALESCE(variables.long_value, 0)::BIGINT AS creation
+FROM accounts
+INNER JOIN experience
+    ON accounts.id = experience.player_id
+INNER JOIN levels
+    ON accounts.id = levels.player_id
+LEFT JOIN variables
+    ON
+        accounts.id = variables.player_id
+        AND
+        variables.name = 'creation'
+ORDER BY accounts.name
+`
+
+type GetAllAccountsRow struct {
+	Name       string
+	Experience Experience
+	Level      Level
+	Creation   int64
+}
+
+func (q *Queries) GetAllAccounts(ctx context.Context) ([]GetAllAccountsRow, error) {
+	rows, err := q.db.Query(ctx, getAllAccounts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetAllAccountsRow
+	for rows.Next() {
+		var i GetAllAccountsRow
+		if err := rows.Scan(
+			&i.Name,
+			&i.Experience.PlayerID,
+			&i.Experience.Attack,
+			&i.Experience.Defence,
+			&i.Experience.Strength,
+			&i.Experience.Constitution,
+			&i.Experience.Ranged,
+			&i.Experience.Prayer,
+			&i.Experience.Magic,
+			&i.Experience.Cooking,
+			&i.Experience.Woodcutting,
+			&i.Experience.Fletching,
+			&i.Experience.Fishing,
+			&i.Experience.Firemaking,
+			&i.Experience.Crafting,
+			&i.Experience.Smithing,
+			&i.Experience.Mining,
+			&i.Experience.Herblore,
+			&i.Experience.Agility,
+			&i.Experience.Thieving,
+			&i.Experience.Slayer,
+			&i.Experience.Farming,
+			&i.Experience.Runecrafting,
+			&i.Experience.Hunter,
+			&i.Experience.Construction,
+			&i.Experience.Summoning,
+			&i.Experience.Dungeoneering,
+			&i.Level.PlayerID,
+			&i.Level.Attack,
+			&i.Level.Defence,
+			&i.Level.Strength,
+			&i.Level.Constitution,
+			&i.Level.Ranged,
+			&i.Level.Prayer,
+			&i.Level.Magic,
+			&i.Level.Cooking,
+			&i.Level.Woodcutting,
+			&i.Level.Fletching,
+			&i.Level.Fishing,
+			&i.Level.Firemaking,
+			&i.Level.Crafting,
+			&i.Level.Smithing,
+			&i.Level.Mining,
+			&i.Level.Herblore,
+			&i.Level.Agility,
+			&i.Level.Thieving,
+			&i.Level.Slayer,
+			&i.Level.Farming,
+			&i.Level.Runecrafting,
+			&i.Level.Hunter,
+			&i.Level.Construction,
+			&i.Level.Summoning,
+			&i.Level.Dungeoneering,
+			&i.Creation,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
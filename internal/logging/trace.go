@@ -0,0 +1,89 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// traceHandler adds the trace and span a record was logged in so logs can be found from traces
+// and the other way around. Only records logged with a context are traced.
+//
+// The IDs are always added at the top level, even for loggers with groups, so they can be
+// searched for the same way everywhere. Attributes can only be added outside of a group before
+// it's opened so the handler remembers how it was built and rebuilds itself for traced records.
+type traceHandler struct {
+	root    slog.Handler
+	handler slog.Handler
+	steps   []func(slog.Handler) slog.Handler
+	grouped bool
+}
+
+func NewTraceHandler(handler slog.Handler) slog.Handler {
+	return traceHandler{
+		root:    handler,
+		handler: handler,
+		steps:   nil,
+		grouped: false,
+	}
+}
+
+func (handler traceHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return handler.handler.Enabled(ctx, level)
+}
+
+func (handler traceHandler) Handle(ctx context.Context, record slog.Record) error {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return handler.handler.Handle(ctx, record)
+	}
+
+	traceAttrs := []slog.Attr{
+		slog.String("traceId", spanContext.TraceID().String()),
+		slog.String("spanId", spanContext.SpanID().String()),
+	}
+
+	if !handler.grouped {
+		record.AddAttrs(traceAttrs...)
+
+		return handler.handler.Handle(ctx, record)
+	}
+
+	traced := handler.root.WithAttrs(traceAttrs)
+	for _, step := range handler.steps {
+		traced = step(traced)
+	}
+
+	return traced.Handle(ctx, record)
+}
+
+func (handler traceHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return handler.with(handler.handler.WithAttrs(attrs), false, func(next slog.Handler) slog.Handler {
+		return next.WithAttrs(attrs)
+	})
+}
+
+func (handler traceHandler) WithGroup(name string) slog.Handler {
+	return handler.with(handler.handler.WithGroup(name), true, func(next slog.Handler) slog.Handler {
+		return next.WithGroup(name)
+	})
+}
+
+func (handler traceHandler) with(
+	built slog.Handler,
+	group bool,
+	step func(slog.Handler) slog.Handler,
+) traceHandler {
+	// Copied so loggers derived from the same parent don't share steps
+	steps := make([]func(slog.Handler) slog.Handler, 0, len(handler.steps)+1)
+	steps = append(steps, handler.steps...)
+	steps = append(steps, step)
+
+	return traceHandler{
+		root:    handler.root,
+		handler: built,
+		steps:   steps,
+		grouped: handler.grouped || group,
+	}
+}
@@ -0,0 +1,126 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+// DatabaseSQLite is how SQLite is labelled in metrics.
+const DatabaseSQLite = "sqlite"
+
+// DBTX is what sqlc's generated queries run against, either a database or a transaction.
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// ObservedDBTX times every query run against a database or transaction.
+type ObservedDBTX struct {
+	db       DBTX
+	database string
+}
+
+var _ DBTX = (*ObservedDBTX)(nil)
+
+// ObserveQueries times the queries run against db. Queries are labelled with the name sqlc
+// gives them so there's a series per query rather than per statement.
+func ObserveQueries(db DBTX, database string) *ObservedDBTX {
+	return &ObservedDBTX{
+		db:       db,
+		database: database,
+	}
+}
+
+// RegisterDBStats exposes the connection pool stats of a database.
+func RegisterDBStats(db *sql.DB, database string) error {
+	if err := Registry.Register(collectors.NewDBStatsCollector(db, database)); err != nil {
+		return fmt.Errorf("unable to register database stats: %w", err)
+	}
+
+	return nil
+}
+
+func (observed *ObservedDBTX) ExecContext(
+	ctx context.Context,
+	query string,
+	args ...any,
+) (sql.Result, error) {
+	timer := observed.start(query)
+
+	result, err := observed.db.ExecContext(ctx, query, args...)
+	timer.observe(err)
+
+	return result, err
+}
+
+func (observed *ObservedDBTX) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	// Preparing isn't timed since the statement is run later without going through here
+	return observed.db.PrepareContext(ctx, query)
+}
+
+func (observed *ObservedDBTX) QueryContext(
+	ctx context.Context,
+	query string,
+	args ...any,
+) (*sql.Rows, error) {
+	timer := observed.start(query)
+
+	rows, err := observed.db.QueryContext(ctx, query, args...)
+	timer.observe(err)
+
+	return rows, err
+}
+
+func (observed *ObservedDBTX) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	timer := observed.start(query)
+
+	row := observed.db.QueryRowContext(ctx, query, args...)
+	timer.observe(row.Err())
+
+	return row
+}
+
+type queryTimer struct {
+	database string
+	query    string
+	start    time.Time
+}
+
+func (observed *ObservedDBTX) start(query string) queryTimer {
+	return queryTimer{
+		database: observed.database,
+		query:    queryName(query),
+		start:    time.Now(),
+	}
+}
+
+func (timer queryTimer) observe(err error) {
+	outcome := OutcomeSuccess
+	if err != nil {
+		outcome = OutcomeFailure
+	}
+
+	DatabaseQueryDuration.
+		WithLabelValues(timer.database, timer.query, outcome).
+		Observe(time.Since(timer.start).Seconds())
+}
+
+// queryName is the name sqlc gives a query in the comment it starts every query with, like
+// "-- name: GetPlayer :one".
+func queryName(query string) string {
+	name, ok := strings.CutPrefix(query, "-- name: ")
+	if !ok {
+		return "unknown"
+	}
+
+	name, _, _ = strings.Cut(name, " ")
+
+	return name
+}
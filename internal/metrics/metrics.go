@@ -0,0 +1,116 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "void"
+
+// Registry has every metric the service exposes. It's kept apart from the default registry so
+// only metrics that are meant to be exposed are.
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// Handler serves the metrics in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+var (
+	// HTTPRequests counts requests by the route that served them rather than their path so
+	// there's one series per page instead of one per player.
+	HTTPRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "HTTP requests served, by route pattern and status.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "How long HTTP requests took to serve, by route pattern.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+)
+
+// Ingestion outcomes.
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+var (
+	IngestionRuns = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "ingestion",
+		Name:      "runs_total",
+		Help:      "Ingestion runs, by world and outcome. In watch mode every changed save file is a run.",
+	}, []string{"world", "outcome"})
+
+	IngestionDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "ingestion",
+		Name:      "duration_seconds",
+		Help:      "How long ingestion runs took, by world and outcome.",
+		Buckets:   []float64{0.1, 0.5, 1, 5, 10, 30, 60, 120, 300, 600},
+	}, []string{"world", "outcome"})
+
+	IngestionFilesScanned = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "ingestion",
+		Name:      "files_scanned_total",
+		Help:      "Save files scanned by ingestion runs, by world.",
+	}, []string{"world"})
+
+	IngestionPlayersUpdated = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "ingestion",
+		Name:      "players_updated_total",
+		Help:      "Players whose skills were recorded by ingestion runs, by world.",
+	}, []string{"world"})
+
+	IngestionFailures = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "ingestion",
+		Name:      "failures_total",
+		Help:      "Save files ingestion runs couldn't ingest, by world.",
+	}, []string{"world"})
+
+	// IngestionLastSuccess is what to alert on when ingestion stalls, it stops moving when runs
+	// fail or stop being scheduled.
+	IngestionLastSuccess = factory.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "ingestion",
+		Name:      "last_success_timestamp_seconds",
+		Help:      "When the last successful ingestion run finished, by world.",
+	}, []string{"world"})
+
+	TrackedPlayers = factory.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "tracked_players",
+		Help:      "Players being tracked, by world. Updated after every ingestion run.",
+	}, []string{"world"})
+)
+
+var DatabaseQueryDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: namespace,
+	Subsystem: "database",
+	Name:      "query_duration_seconds",
+	Help:      "How long database queries took, by database, query and whether they failed.",
+	Buckets:   []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1},
+}, []string{"database", "query", "outcome"})
@@ -0,0 +1,170 @@
+// Package retention thins out old skill history so the database doesn't grow forever.
+package retention
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"time"
+
+	"github.com/cadyyan/void-tool/internal/logging"
+	"github.com/cadyyan/void-tool/internal/services"
+)
+
+// Policy is how much of a player's daily history is kept. Recent history keeps every day, older
+// history keeps the last day of each week and anything older than that keeps the last day of
+// each month.
+type Policy struct {
+	// DailyFor is how long every day is kept for
+	DailyFor time.Duration
+
+	// WeeklyFor is how long the last day of each week is kept for
+	WeeklyFor time.Duration
+}
+
+// Reason is why a day can be removed.
+type Reason string
+
+const (
+	// ReasonRedundant days are identical to the day before them.
+	ReasonRedundant Reason = "redundant"
+
+	// ReasonDownsampled days aren't the last day in their week or month.
+	ReasonDownsampled Reason = "downsampled"
+)
+
+// Removal is a day of a player's history that can be removed.
+type Removal struct {
+	Date   time.Time
+	Reason Reason
+}
+
+// Plan works out which days of a player's history can be removed. History must be ordered oldest
+// first. The latest day is always kept so it's still clear when the player last changed.
+func Plan(history []services.PlayerSkillDay, policy Policy, now time.Time) []Removal {
+	var (
+		removals []Removal
+		kept     []services.PlayerSkillDay
+	)
+
+	for index, day := range history {
+		isLatest := index == len(history)-1
+
+		if !isLatest && len(kept) > 0 && maps.Equal(kept[len(kept)-1].Skills, day.Skills) {
+			removals = append(removals, Removal{Date: day.Date, Reason: ReasonRedundant})
+
+			continue
+		}
+
+		kept = append(kept, day)
+	}
+
+	dailyCutoff := now.Add(-policy.DailyFor)
+	weeklyCutoff := now.Add(-policy.WeeklyFor)
+
+	for index, day := range kept {
+		if !day.Date.Before(dailyCutoff) || index == len(kept)-1 {
+			continue
+		}
+
+		// Only the last day in each period is kept
+		if bucket(day.Date, weeklyCutoff) == bucket(kept[index+1].Date, weeklyCutoff) {
+			removals = append(removals, Removal{Date: day.Date, Reason: ReasonDownsampled})
+		}
+	}
+
+	return removals
+}
+
+// bucket names the period a day is downsampled into.
+func bucket(date time.Time, weeklyCutoff time.Time) string {
+	if date.Before(weeklyCutoff) {
+		return date.Format("2006-01")
+	}
+
+	year, week := date.ISOWeek()
+
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+// Summary is how much history was, or for a dry run would be, removed.
+type Summary struct {
+	PlayersChecked int
+	PlayersChanged int
+	Redundant      int
+	Downsampled    int
+}
+
+// Apply removes old history for every player according to the policy. A dry run works out what
+// would be removed without removing anything.
+func Apply(
+	ctx context.Context,
+	logger *slog.Logger,
+	storageService services.StorageService,
+	policy Policy,
+	dryRun bool,
+) (Summary, error) {
+	summary := Summary{
+		PlayersChecked: 0,
+		PlayersChanged: 0,
+		Redundant:      0,
+		Downsampled:    0,
+	}
+
+	players, err := storageService.GetAllPlayers(ctx)
+	if err != nil {
+		return summary, fmt.Errorf("unable to get players: %w", err)
+	}
+
+	now := time.Now().UTC()
+
+	for _, player := range players {
+		history, err := storageService.GetPlayerSkillDays(ctx, player.ID)
+		if err != nil {
+			return summary, fmt.Errorf("unable to get history for %s: %w", player.Username, err)
+		}
+
+		summary.PlayersChecked++
+
+		removals := Plan(history, policy, now)
+		if len(removals) == 0 {
+			continue
+		}
+
+		summary.PlayersChanged++
+
+		days := make([]time.Time, len(removals))
+		for index, removal := range removals {
+			days[index] = removal.Date
+
+			switch removal.Reason {
+			case ReasonRedundant:
+				summary.Redundant++
+			case ReasonDownsampled:
+				summary.Downsampled++
+			}
+		}
+
+		if dryRun {
+			continue
+		}
+
+		err = storageService.DeletePlayerSkillDays(ctx, services.DeletePlayerSkillDaysParams{
+			PlayerID: player.ID,
+			Days:     days,
+		})
+		if err != nil {
+			logger.ErrorContext(
+				ctx,
+				"Unable to remove old history",
+				slog.String("playerName", player.Username),
+				logging.Err(err),
+			)
+
+			return summary, fmt.Errorf("unable to remove history for %s: %w", player.Username, err)
+		}
+	}
+
+	return summary, nil
+}
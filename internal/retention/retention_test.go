@@ -0,0 +1,207 @@
+package retention_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cadyyan/void-tool/internal/retention"
+	"github.com/cadyyan/void-tool/internal/services"
+	"github.com/stretchr/testify/require"
+)
+
+// historyDay is a day of history where only the experience in a single skill matters, days with
+// the same experience are identical.
+type historyDay struct {
+	date       string
+	experience float64
+}
+
+func TestPlan(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, time.June, 15, 12, 0, 0, 0, time.UTC)
+	policy := retention.Policy{
+		DailyFor:  7 * 24 * time.Hour,
+		WeeklyFor: 30 * 24 * time.Hour,
+	}
+
+	tests := []struct {
+		name        string
+		now         time.Time
+		history     []historyDay
+		redundant   []string
+		downsampled []string
+	}{
+		{
+			name:        "empty history",
+			now:         now,
+			history:     nil,
+			redundant:   nil,
+			downsampled: nil,
+		},
+		{
+			name: "recent days are all kept",
+			now:  now,
+			history: []historyDay{
+				{date: "2026-06-12", experience: 1},
+				{date: "2026-06-13", experience: 2},
+				{date: "2026-06-14", experience: 3},
+			},
+			redundant:   nil,
+			downsampled: nil,
+		},
+		{
+			name: "days that are the same as the last day kept are redundant",
+			now:  now,
+			history: []historyDay{
+				{date: "2026-06-11", experience: 1},
+				{date: "2026-06-12", experience: 1},
+				{date: "2026-06-13", experience: 1},
+				{date: "2026-06-14", experience: 2},
+			},
+			redundant:   []string{"2026-06-12", "2026-06-13"},
+			downsampled: nil,
+		},
+		{
+			name: "the latest day is kept even when it's redundant",
+			now:  now,
+			history: []historyDay{
+				{date: "2026-06-13", experience: 1},
+				{date: "2026-06-14", experience: 1},
+			},
+			redundant:   nil,
+			downsampled: nil,
+		},
+		{
+			name: "old days keep the last day of each week",
+			now:  now,
+			history: []historyDay{
+				{date: "2026-05-25", experience: 1},
+				{date: "2026-05-27", experience: 2},
+				{date: "2026-05-31", experience: 3},
+				{date: "2026-06-01", experience: 4},
+				{date: "2026-06-14", experience: 5},
+			},
+			redundant:   nil,
+			downsampled: []string{"2026-05-25", "2026-05-27"},
+		},
+		{
+			name: "the last day of a week can be newer than the daily cutoff",
+			now:  now,
+			history: []historyDay{
+				{date: "2026-06-04", experience: 1},
+				{date: "2026-06-08", experience: 2},
+				{date: "2026-06-11", experience: 3},
+				{date: "2026-06-15", experience: 4},
+			},
+			redundant:   nil,
+			downsampled: []string{"2026-06-08"},
+		},
+		{
+			name: "older days keep the last day of each month",
+			now:  now,
+			history: []historyDay{
+				{date: "2026-01-05", experience: 1},
+				{date: "2026-01-20", experience: 2},
+				{date: "2026-01-31", experience: 3},
+				{date: "2026-02-10", experience: 4},
+				{date: "2026-06-14", experience: 5},
+			},
+			redundant:   nil,
+			downsampled: []string{"2026-01-05", "2026-01-20"},
+		},
+		{
+			name: "the last day before the weekly cutoff is kept when its month continues after it",
+			now:  now,
+			history: []historyDay{
+				{date: "2026-05-10", experience: 1},
+				{date: "2026-05-20", experience: 2},
+				{date: "2026-06-14", experience: 3},
+			},
+			redundant:   nil,
+			downsampled: nil,
+		},
+		{
+			name: "weeks are ISO weeks, even across the new year",
+			now:  time.Date(2026, time.January, 20, 12, 0, 0, 0, time.UTC),
+			history: []historyDay{
+				// 2025-W52
+				{date: "2025-12-28", experience: 1},
+				// 2026-W01
+				{date: "2025-12-29", experience: 2},
+				{date: "2026-01-01", experience: 3},
+				// 2026-W02
+				{date: "2026-01-05", experience: 4},
+				{date: "2026-01-19", experience: 5},
+			},
+			redundant:   nil,
+			downsampled: []string{"2025-12-29"},
+		},
+		{
+			name: "the latest day is never downsampled",
+			now:  now,
+			history: []historyDay{
+				{date: "2026-05-25", experience: 1},
+				{date: "2026-05-27", experience: 2},
+				{date: "2026-05-31", experience: 3},
+			},
+			redundant:   nil,
+			downsampled: []string{"2026-05-25", "2026-05-27"},
+		},
+		{
+			name: "only days that aren't redundant are downsampled",
+			now:  now,
+			history: []historyDay{
+				{date: "2026-05-25", experience: 1},
+				{date: "2026-05-26", experience: 1},
+				{date: "2026-05-27", experience: 2},
+				{date: "2026-06-14", experience: 3},
+			},
+			redundant:   []string{"2026-05-26"},
+			downsampled: []string{"2026-05-25"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			history := make([]services.PlayerSkillDay, len(test.history))
+			for index, day := range test.history {
+				history[index] = services.PlayerSkillDay{
+					Date: parseDate(t, day.date),
+					Skills: map[string]services.PlayerSkillRecord{
+						"Attack": {Level: 1, Experience: day.experience},
+					},
+				}
+			}
+
+			var expected []retention.Removal
+
+			for _, date := range test.redundant {
+				expected = append(expected, retention.Removal{
+					Date:   parseDate(t, date),
+					Reason: retention.ReasonRedundant,
+				})
+			}
+
+			for _, date := range test.downsampled {
+				expected = append(expected, retention.Removal{
+					Date:   parseDate(t, date),
+					Reason: retention.ReasonDownsampled,
+				})
+			}
+
+			require.ElementsMatch(t, expected, retention.Plan(history, policy, test.now))
+		})
+	}
+}
+
+func parseDate(t *testing.T, date string) time.Time {
+	t.Helper()
+
+	parsed, err := time.Parse(time.DateOnly, date)
+	require.NoError(t, err)
+
+	return parsed
+}
@@ -2,66 +2,148 @@ package internal
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/cadyyan/void-tool/internal/bgtasks"
 	"github.com/cadyyan/void-tool/internal/configuration"
-	"github.com/cadyyan/void-tool/internal/database/sqlitedb"
+	"github.com/cadyyan/void-tool/internal/logging"
 	"github.com/cadyyan/void-tool/internal/services"
 	"github.com/cadyyan/void-tool/internal/web"
 	"github.com/go-co-op/gocron/v2"
 )
 
 type Server struct {
-	http *http.Server
-	cron gocron.Scheduler
+	http     *http.Server
+	cron     gocron.Scheduler
+	watchers []*bgtasks.SaveFileWatcher
+	logger   *slog.Logger
+
+	stopWatcher context.CancelFunc
+	watcherDone chan struct{}
 }
 
 func NewServer(
 	logger *slog.Logger,
 	config configuration.Configuration,
-	sqliteDB *sql.DB,
-	playerQueries *sqlitedb.Queries,
 	storageService services.StorageService,
-	voidPlayerService services.VoidPlayerService,
+	worlds []services.VoidWorld,
 ) (*Server, error) {
 	cron, err := gocron.NewScheduler()
 	if err != nil {
 		return nil, fmt.Errorf("unable to create background task scheduler: %w", err)
 	}
 
+	var watchers []*bgtasks.SaveFileWatcher
+
+	for _, world := range worlds {
+		// In watch mode everything is ingested once on startup and then only the saves that
+		// change
+		reader, canWatch := world.Players.(bgtasks.SaveFileReader)
+		canWatch = canWatch && world.DataDir != ""
+		if config.RS.Watch && !canWatch {
+			logger.Warn(
+				"The player service for this world doesn't support watch mode, falling back to polling",
+				slog.String("world", world.Name),
+			)
+		}
+
+		jobDefinition := gocron.DurationJob(config.RS.PollFrequency)
+		if config.RS.Watch && canWatch {
+			jobDefinition = gocron.OneTimeJob(gocron.OneTimeJobStartImmediately())
+			watchers = append(watchers, bgtasks.NewSaveFileWatcher(
+				logger.WithGroup("background--watchSaves"),
+				world.Name,
+				world.DataDir,
+				config.RS.WatchDebounce,
+				config.RS.WatchPollFrequency,
+				storageService,
+				reader,
+			))
+		}
+
+		_, err = cron.NewJob(
+			jobDefinition,
+			gocron.NewTask(
+				bgtasks.ScrapePlayerSkills,
+				logger.WithGroup("background--ingestSkills"),
+				storageService,
+				world,
+			),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to schedule highscores polling task: %w", err)
+		}
+	}
+
+	_, err = cron.NewJob(
+		gocron.DurationJob(config.Retention.PruneFrequency),
+		gocron.NewTask(
+			bgtasks.PruneIntradaySnapshots,
+			logger.WithGroup("background--pruneSnapshots"),
+			storageService,
+			config.Retention.IntradaySnapshots,
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to schedule snapshot pruning task: %w", err)
+	}
+
 	_, err = cron.NewJob(
-		gocron.DurationJob(config.RS.PollFrequency),
+		gocron.DurationJob(config.Retention.DownsampleFrequency),
 		gocron.NewTask(
-			bgtasks.ScrapePlayerSkills,
-			logger.WithGroup("background--ingestSkills"),
+			bgtasks.DownsampleSkillHistory,
+			logger.WithGroup("background--downsampleHistory"),
 			storageService,
-			voidPlayerService,
+			config.Retention.Policy(),
 		),
 	)
 	if err != nil {
-		return nil, fmt.Errorf("unable to schedule highscores polling task: %w", err)
+		return nil, fmt.Errorf("unable to schedule history downsampling task: %w", err)
 	}
 
 	return &Server{
 		http: &http.Server{
 			Addr:              config.HTTP.BindAddress(),
-			Handler:           web.NewRouter(logger, config, storageService),
+			Handler:           web.NewRouter(logger, config, storageService, worlds),
 			ReadHeaderTimeout: readHeaderTimeout,
 			IdleTimeout:       idleTimeout,
 			// TODO: error logger
 		},
-		cron: cron,
+		cron:        cron,
+		watchers:    watchers,
+		logger:      logger,
+		stopWatcher: func() {},
+		watcherDone: nil,
 	}, nil
 }
 
 func (server *Server) Start(ctx context.Context) error {
 	server.cron.Start()
 
+	if len(server.watchers) > 0 {
+		watcherCtx, stopWatcher := context.WithCancel(ctx)
+		server.stopWatcher = stopWatcher
+		server.watcherDone = make(chan struct{})
+
+		var running sync.WaitGroup
+		for _, watcher := range server.watchers {
+			running.Go(func() {
+				if err := watcher.Run(watcherCtx); err != nil {
+					server.logger.ErrorContext(ctx, "Save file watcher stopped", logging.Err(err))
+				}
+			})
+		}
+
+		go func() {
+			running.Wait()
+			close(server.watcherDone)
+		}()
+	}
+
 	err := server.http.ListenAndServe()
 	if err != nil && err != http.ErrServerClosed {
 		return fmt.Errorf("unable to start HTTP server: %w", err)
@@ -75,6 +157,11 @@ func (server *Server) Stop(ctx context.Context) error {
 		return fmt.Errorf("unable to cleanly shutdown HTTP server: %w", err)
 	}
 
+	server.stopWatcher()
+	if server.watcherDone != nil {
+		<-server.watcherDone
+	}
+
 	// TODO: run these in parallel?
 	err := server.cron.Shutdown()
 	if err != nil {
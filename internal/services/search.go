@@ -0,0 +1,213 @@
+package services
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// DefaultSearchLimit is how many players a search returns when it isn't given a limit.
+const DefaultSearchLimit = 10
+
+const (
+	searchRankExact = iota
+	searchRankPrefix
+	searchRankContains
+	searchRankFuzzy
+)
+
+// fuzzySearchCandidates is how many fuzzy matches are ranked for each result a search returns.
+// Short queries fuzzily match most of a world so the matches are never all loaded.
+const fuzzySearchCandidates = 10
+
+// playerSearch is one of the steps of a search, see searchPlayers.
+type playerSearch struct {
+	// Query is the normalized query.
+	Query string
+
+	// Pattern is a LIKE pattern matching the usernames to find. Backslash is the escape
+	// character.
+	Pattern string
+
+	// Rank is the furthest searchRank a username can have and still be found. The pattern has to
+	// match every username up to that rank.
+	Rank int
+
+	// Limit is how many players to find. The shortest usernames are found first.
+	Limit int
+}
+
+// searchPlayers searches in steps so short queries don't load most of a world. Usernames that
+// start with the query are the closest matches so they're found first, then usernames that
+// contain it, and the fuzzy search only runs when there still aren't enough of them.
+func searchPlayers(
+	params SearchPlayersParams,
+	find func(search playerSearch) ([]Player, error),
+) ([]Player, error) {
+	query := NormalizeUsername(params.Query)
+	if query == "" {
+		return nil, nil
+	}
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+
+	steps := []playerSearch{
+		{
+			Query:   query,
+			Pattern: prefixSearchPattern(query),
+			Rank:    searchRankPrefix,
+			Limit:   limit,
+		},
+		{
+			Query:   query,
+			Pattern: containsSearchPattern(query),
+			Rank:    searchRankContains,
+			Limit:   limit,
+		},
+		{
+			Query:   query,
+			Pattern: fuzzySearchPattern(query),
+			Rank:    searchRankFuzzy,
+			Limit:   limit * fuzzySearchCandidates,
+		},
+	}
+
+	var players []Player
+
+	for _, step := range steps {
+		if len(players) >= limit {
+			break
+		}
+
+		candidates, err := find(step)
+		if err != nil {
+			return nil, err
+		}
+
+		// Each step finds the shortest usernames first so long usernames that were found by an
+		// earlier step can be left out of a later one
+		for _, candidate := range candidates {
+			if !slices.ContainsFunc(players, func(player Player) bool { return player.ID == candidate.ID }) {
+				players = append(players, candidate)
+			}
+		}
+	}
+
+	return rankSearchResults(players, query, limit), nil
+}
+
+// prefixSearchPattern is a LIKE pattern that matches every username starting with the normalized
+// query. Backslash is the escape character.
+func prefixSearchPattern(query string) string {
+	var pattern strings.Builder
+
+	for _, char := range query {
+		writeLikeRune(&pattern, char)
+	}
+
+	pattern.WriteByte('%')
+
+	return pattern.String()
+}
+
+// containsSearchPattern is a LIKE pattern that matches every username containing the normalized
+// query. Backslash is the escape character.
+func containsSearchPattern(query string) string {
+	return "%" + prefixSearchPattern(query)
+}
+
+// fuzzySearchPattern is a LIKE pattern that matches every username containing the characters of
+// the normalized query in order. Backslash is the escape character.
+func fuzzySearchPattern(query string) string {
+	var pattern strings.Builder
+
+	pattern.WriteByte('%')
+
+	for _, char := range query {
+		writeLikeRune(&pattern, char)
+		pattern.WriteByte('%')
+	}
+
+	return pattern.String()
+}
+
+func writeLikeRune(pattern *strings.Builder, char rune) {
+	switch char {
+	case '\\', '%', '_':
+		pattern.WriteByte('\\')
+	}
+
+	pattern.WriteRune(char)
+}
+
+// searchRank is how closely a username matches a query, lower is closer. Both have to be
+// normalized already. It's false when the username doesn't match at all.
+func searchRank(username string, query string) (int, bool) {
+	switch {
+	case username == query:
+		return searchRankExact, true
+	case strings.HasPrefix(username, query):
+		return searchRankPrefix, true
+	case strings.Contains(username, query):
+		return searchRankContains, true
+	}
+
+	remaining := query
+	for _, char := range username {
+		if rest, ok := strings.CutPrefix(remaining, string(char)); ok {
+			remaining = rest
+		}
+	}
+
+	return searchRankFuzzy, remaining == ""
+}
+
+// rankSearchResults orders players matching a query from the closest match to the furthest and
+// keeps the first limit of them. Exact matches come first, then names starting with the query,
+// then names containing it, then everything else. Shorter names come first within each of those.
+func rankSearchResults(players []Player, query string, limit int) []Player {
+	type rankedPlayer struct {
+		player   Player
+		username string
+		rank     int
+	}
+
+	ranked := make([]rankedPlayer, 0, len(players))
+
+	for _, player := range players {
+		username := NormalizeUsername(player.Username)
+
+		rank, ok := searchRank(username, query)
+		if !ok {
+			continue
+		}
+
+		ranked = append(ranked, rankedPlayer{
+			player:   player,
+			username: username,
+			rank:     rank,
+		})
+	}
+
+	slices.SortFunc(ranked, func(a, b rankedPlayer) int {
+		return cmp.Or(
+			cmp.Compare(a.rank, b.rank),
+			cmp.Compare(len(a.username), len(b.username)),
+			cmp.Compare(a.username, b.username),
+		)
+	})
+
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+
+	results := make([]Player, 0, min(limit, len(ranked)))
+	for _, player := range ranked[:min(limit, len(ranked))] {
+		results = append(results, player.player)
+	}
+
+	return results
+}
@@ -2,34 +2,196 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 )
 
 type StorageService interface {
 	CreatePlayer(ctx context.Context, params CreatePlayerParams) (Player, error)
 	GetAllPlayers(ctx context.Context) ([]Player, error)
-	GetPlayerByUsername(ctx context.Context, username string) (Player, error)
+	GetPlayerByUsername(ctx context.Context, params GetPlayerByUsernameParams) (Player, error)
+	GetPlayerByPreviousUsername(
+		ctx context.Context,
+		params GetPlayerByUsernameParams,
+	) (Player, error)
+	GetPlayersCreatedOn(ctx context.Context, params GetPlayersCreatedOnParams) ([]Player, error)
+	RenamePlayer(ctx context.Context, params RenamePlayerParams) (Player, error)
+	GetPlayerPreviousNames(ctx context.Context, playerID string) ([]PlayerPreviousName, error)
+	SearchPlayers(ctx context.Context, params SearchPlayersParams) ([]Player, error)
+	GetPlayerIndex(ctx context.Context, params GetPlayerIndexParams) ([]PlayerIndexRecord, error)
+	CountPlayers(ctx context.Context, world string) (int, error)
 	GetOrCreatePlayerByUsername(
 		ctx context.Context,
 		params GetOrCreatePlayerByUsernameParams,
 	) (Player, error)
 	RecordPlayerSkills(ctx context.Context, params RecordPlayerSkillsParams) error
-	GetPlayerSkills(ctx context.Context, username string) (map[string]PlayerSkillRecord, error)
-	GetHighscoresForSkill(ctx context.Context, skill string) ([]HighscoreSkillRecord, error)
+	GetPlayerSkills(
+		ctx context.Context,
+		params GetPlayerSkillsParams,
+	) (map[string]PlayerSkillRecord, error)
+	GetPlayerSkillHistory(
+		ctx context.Context,
+		params GetPlayerSkillHistoryParams,
+	) ([]PlayerSkillSnapshot, error)
+	GetHighscoresForSkill(
+		ctx context.Context,
+		params GetHighscoresForSkillParams,
+	) ([]HighscoreSkillRecord, error)
+	CountHighscoresForSkill(ctx context.Context, params CountHighscoresForSkillParams) (int, error)
+	GetPlayerGains(ctx context.Context, params GetPlayerGainsParams) (map[string]SkillGain, error)
+	GetTopGainers(ctx context.Context, params GetTopGainersParams) ([]GainerRecord, error)
+	DeleteIntradaySnapshotsBefore(ctx context.Context, before time.Time) (int, error)
+	GetPlayerSkillDays(ctx context.Context, playerID string) ([]PlayerSkillDay, error)
+	DeletePlayerSkillDays(ctx context.Context, params DeletePlayerSkillDaysParams) error
+	GetSaveFileStates(ctx context.Context, world string) (map[string]SaveFileState, error)
+	RecordSaveFileState(ctx context.Context, state SaveFileState) error
+	GetQuarantinedSaveFiles(ctx context.Context) ([]QuarantinedSaveFile, error)
+	QuarantineSaveFile(ctx context.Context, params QuarantineSaveFileParams) error
+	ReleaseSaveFile(ctx context.Context, params ReleaseSaveFileParams) error
+	StartIngestionRun(ctx context.Context, params StartIngestionRunParams) error
+	FinishIngestionRun(ctx context.Context, params FinishIngestionRunParams) error
+	DeleteIngestionRunsBefore(ctx context.Context, before time.Time) error
+	GetRecentIngestionRuns(ctx context.Context, limit int) ([]IngestionRun, error)
+	GetLastSuccessfulIngestionRun(ctx context.Context) (IngestionRun, error)
 }
 
+// ErrNotFound is wrapped by every error storage services return for things that don't exist,
+// so callers can tell them apart from the storage itself failing.
+var ErrNotFound = errors.New("not found")
+
+var (
+	ErrPlayerNotFound       = fmt.Errorf("player %w", ErrNotFound)
+	ErrIngestionRunNotFound = fmt.Errorf("ingestion run %w", ErrNotFound)
+)
+
+const (
+	// SkillOverall is a pseudo-skill that ranks players by their total level and then by their
+	// total experience.
+	SkillOverall = "Overall"
+
+	// SkillCombat is a highscore category that ranks players by their combat level and then by
+	// the experience in their combat skills.
+	SkillCombat = "Combat"
+)
+
+// DefaultWorld is the world players belong to when a deployment only reads from a single data
+// directory. Usernames are unique within a world but the same name can be used in several.
+const DefaultWorld = "main"
+
 // TODO: parameter validation
 
 type CreatePlayerParams struct {
+	World     string
 	Username  string
 	CreatedOn time.Time
 }
 
 type GetOrCreatePlayerByUsernameParams struct {
+	World     string
 	Username  string
 	CreatedOn time.Time
 }
 
+type GetPlayerByUsernameParams struct {
+	World    string
+	Username string
+}
+
+// GetPlayersCreatedOnParams selects the players in a world whose accounts were created at the given
+// time. Accounts keep their creation time when they're renamed.
+type GetPlayersCreatedOnParams struct {
+	World     string
+	CreatedOn time.Time
+}
+
+// RenamePlayerParams changes a player's username. Their old username is kept as one of their
+// previous names.
+type RenamePlayerParams struct {
+	PlayerID  string
+	Username  string
+	RenamedOn time.Time
+}
+
+// SearchPlayersParams finds the players in a world whose usernames match a query. Usernames
+// match when they contain the characters of the query in order, so "zez" finds "Zezima" and
+// "zzma" does too. The closest matches come first.
+type SearchPlayersParams struct {
+	World string
+	Query string
+	Limit int
+}
+
+// PlayerIndexSort is the column the player index is sorted by. Players with the same value are
+// sorted by their username.
+type PlayerIndexSort string
+
+const (
+	PlayerIndexSortUsername        PlayerIndexSort = "username"
+	PlayerIndexSortTotalLevel      PlayerIndexSort = "total-level"
+	PlayerIndexSortTotalExperience PlayerIndexSort = "total-experience"
+	PlayerIndexSortCombatLevel     PlayerIndexSort = "combat-level"
+	PlayerIndexSortLastChanged     PlayerIndexSort = "last-changed"
+)
+
+// PlayerIndexSorts are all the ways the player index can be sorted.
+var PlayerIndexSorts = []PlayerIndexSort{
+	PlayerIndexSortUsername,
+	PlayerIndexSortTotalLevel,
+	PlayerIndexSortTotalExperience,
+	PlayerIndexSortCombatLevel,
+	PlayerIndexSortLastChanged,
+}
+
+// GetPlayerIndexParams selects a page of every player in a world.
+type GetPlayerIndexParams struct {
+	World      string
+	Sort       PlayerIndexSort
+	Descending bool
+	Limit      int
+	Offset     int
+}
+
+// PlayerIndexRecord summarises a player's latest skills. Players that haven't had any skills
+// recorded have zeros and a zero LastChanged, which sort before everyone else.
+type PlayerIndexRecord struct {
+	PlayerID        string
+	Username        string
+	TotalLevel      int
+	TotalExperience float64
+	CombatLevel     int
+
+	// LastChanged is the last day any of the player's skills changed. Skills are only recorded
+	// when they change so a player that's logged in without training anything isn't counted.
+	LastChanged time.Time
+}
+
+// parseLastChanged reads the day a player last changed from the player index. It's empty for
+// players without any skills.
+func parseLastChanged(day string) (time.Time, error) {
+	if day == "" {
+		return time.Time{}, nil
+	}
+
+	lastChanged, err := time.Parse(time.DateOnly, day)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unable to parse when player last changed: %w", err)
+	}
+
+	return lastChanged, nil
+}
+
+// PlayerPreviousName is a username a player used to have and when they stopped using it.
+type PlayerPreviousName struct {
+	Username  string
+	RenamedOn time.Time
+}
+
+type GetPlayerSkillsParams struct {
+	World    string
+	Username string
+}
+
 type RecordPlayerSkillsParams struct {
 	PlayerID string
 	Skills   map[string]PlayerSkillRecord
@@ -41,15 +203,194 @@ type PlayerSkillRecord struct {
 	Experience float64
 }
 
+// GetPlayerSkillHistoryParams selects the snapshots of a single skill (or SkillOverall) between
+// two times, inclusive. Daily history compares whole days so the time of day is ignored.
+type GetPlayerSkillHistoryParams struct {
+	World       string
+	Username    string
+	Skill       string
+	From        time.Time
+	To          time.Time
+	Granularity HistoryGranularity
+}
+
+// HistoryGranularity is how far apart snapshots in a player's history are. Each snapshot is the
+// last one recorded in its period.
+type HistoryGranularity string
+
+const (
+	HistoryGranularityDay  HistoryGranularity = "day"
+	HistoryGranularityHour HistoryGranularity = "hour"
+)
+
+type PlayerSkillSnapshot struct {
+	Date       time.Time
+	Level      int
+	Experience float64
+}
+
+type GetHighscoresForSkillParams struct {
+	World  string
+	Skill  string
+	Limit  int
+	Offset int
+}
+
+type CountHighscoresForSkillParams struct {
+	World string
+	Skill string
+}
+
 type HighscoreSkillRecord struct {
+	Rank       int
 	PlayerID   string
 	Username   string
 	Level      int
 	Experience float64
 }
 
+// PlayerSkillDay is every skill recorded for a player on a single day.
+type PlayerSkillDay struct {
+	Date   time.Time
+	Skills map[string]PlayerSkillRecord
+}
+
+// DeletePlayerSkillDaysParams removes everything recorded for a player on the given days.
+type DeletePlayerSkillDaysParams struct {
+	PlayerID string
+	Days     []time.Time
+}
+
 type Player struct {
 	ID        string
+	World     string
 	Username  string
 	CreatedOn time.Time
 }
+
+// GetPlayerGainsParams selects the period to compare a player's skills over. The result
+// includes an entry for SkillOverall with the player's totals.
+type GetPlayerGainsParams struct {
+	World    string
+	Username string
+	From     time.Time
+	To       time.Time
+}
+
+type GetTopGainersParams struct {
+	World  string
+	Skill  string
+	From   time.Time
+	To     time.Time
+	Limit  int
+	Offset int
+}
+
+// SkillGain compares a skill at the start of a period with the end of it. The start is the last
+// snapshot on or before the first day of the period, or the first snapshot during the period if
+// the player was first seen after it started.
+type SkillGain struct {
+	StartLevel      int
+	StartExperience float64
+	EndLevel        int
+	EndExperience   float64
+}
+
+func (gain SkillGain) LevelsGained() int {
+	return gain.EndLevel - gain.StartLevel
+}
+
+func (gain SkillGain) ExperienceGained() float64 {
+	return gain.EndExperience - gain.StartExperience
+}
+
+type GainerRecord struct {
+	Rank     int
+	PlayerID string
+	Username string
+	Gain     SkillGain
+}
+
+// SaveFileState is the version of a save file that was last ingested. Paths are relative to the
+// data directory of the world the save belongs to.
+type SaveFileState struct {
+	World      string
+	Path       string
+	ModifiedOn time.Time
+	Size       int64
+	Hash       string
+}
+
+// QuarantinedSaveFile is a save that couldn't be read. It stays quarantined until a version of
+// it is ingested successfully or it's removed from the data directory.
+type QuarantinedSaveFile struct {
+	World       string
+	Path        string
+	Hash        string
+	Error       string
+	FirstSeenOn time.Time
+	LastSeenOn  time.Time
+}
+
+type QuarantineSaveFileParams struct {
+	World  string
+	Path   string
+	Hash   string
+	Error  string
+	SeenOn time.Time
+}
+
+type ReleaseSaveFileParams struct {
+	World string
+	Path  string
+}
+
+type StartIngestionRunParams struct {
+	ID        string
+	World     string
+	StartedOn time.Time
+}
+
+// FinishIngestionRunParams records the outcome of a run. Error is only set when the whole run
+// failed, problems with individual saves are listed in Failures instead.
+type FinishIngestionRunParams struct {
+	ID             string
+	FinishedOn     time.Time
+	FilesScanned   int
+	PlayersUpdated int
+	Error          string
+	Failures       []IngestionFailure
+}
+
+type IngestionRun struct {
+	ID             string
+	World          string
+	StartedOn      time.Time
+	FinishedOn     time.Time
+	FilesScanned   int
+	PlayersUpdated int
+	Error          string
+	Failures       []IngestionFailure
+}
+
+// Finished is false while the run is in progress or if it never completed.
+func (run IngestionRun) Finished() bool {
+	return !run.FinishedOn.IsZero()
+}
+
+func (run IngestionRun) Succeeded() bool {
+	return run.Finished() && run.Error == ""
+}
+
+func (run IngestionRun) Duration() time.Duration {
+	if !run.Finished() {
+		return 0
+	}
+
+	return run.FinishedOn.Sub(run.StartedOn)
+}
+
+type IngestionFailure struct {
+	File  string
+	Error string
+}
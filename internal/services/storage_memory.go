@@ -0,0 +1,1068 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/cadyyan/void-tool/internal/combat"
+	"github.com/google/uuid"
+)
+
+var (
+	errMemoryPlayerExists = errors.New("player already exists")
+)
+
+// StorageMemoryService keeps everything in memory and follows the same rules as the database
+// backed services. Nothing is persisted so it's only meant for tests and trying things out.
+type StorageMemoryService struct {
+	mutex sync.RWMutex
+
+	players map[string]Player
+
+	// Previous names are keyed by player ID and then normalized username
+	previousNames map[string]map[string]PlayerPreviousName
+
+	// Skills are keyed by player ID, then day and then skill name
+	skills map[string]map[string]map[string]PlayerSkillRecord
+
+	// Combat levels are keyed by player ID and then day
+	combatLevels map[string]map[string]int
+
+	// Snapshots are keyed by player ID, then the formatted time they were recorded and then
+	// skill name
+	snapshots map[string]map[string]map[string]PlayerSkillRecord
+
+	// Save files are keyed by world and then path
+	saveFiles   map[string]map[string]SaveFileState
+	quarantined map[string]map[string]QuarantinedSaveFile
+
+	ingestionRuns map[string]IngestionRun
+}
+
+var _ StorageService = (*StorageMemoryService)(nil)
+
+func NewStorageMemoryService() *StorageMemoryService {
+	return &StorageMemoryService{
+		mutex:         sync.RWMutex{},
+		players:       make(map[string]Player),
+		previousNames: make(map[string]map[string]PlayerPreviousName),
+		skills:        make(map[string]map[string]map[string]PlayerSkillRecord),
+		combatLevels:  make(map[string]map[string]int),
+		snapshots:     make(map[string]map[string]map[string]PlayerSkillRecord),
+		saveFiles:     make(map[string]map[string]SaveFileState),
+		quarantined:   make(map[string]map[string]QuarantinedSaveFile),
+		ingestionRuns: make(map[string]IngestionRun),
+	}
+}
+
+func (service *StorageMemoryService) CreatePlayer(
+	_ context.Context,
+	params CreatePlayerParams,
+) (Player, error) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	if _, ok := service.findPlayer(params.World, params.Username); ok {
+		return Player{}, fmt.Errorf("unable to create player record in memory: %w", errMemoryPlayerExists)
+	}
+
+	return service.createPlayer(params.World, params.Username, params.CreatedOn), nil
+}
+
+func (service *StorageMemoryService) GetAllPlayers(_ context.Context) ([]Player, error) {
+	service.mutex.RLock()
+	defer service.mutex.RUnlock()
+
+	return slices.Collect(maps.Values(service.players)), nil
+}
+
+func (service *StorageMemoryService) GetPlayerByUsername(
+	_ context.Context,
+	params GetPlayerByUsernameParams,
+) (Player, error) {
+	service.mutex.RLock()
+	defer service.mutex.RUnlock()
+
+	player, ok := service.findPlayer(params.World, params.Username)
+	if !ok {
+		return Player{}, fmt.Errorf("unable to get player by username from memory: %w", ErrPlayerNotFound)
+	}
+
+	return player, nil
+}
+
+func (service *StorageMemoryService) GetPlayerByPreviousUsername(
+	_ context.Context,
+	params GetPlayerByUsernameParams,
+) (Player, error) {
+	service.mutex.RLock()
+	defer service.mutex.RUnlock()
+
+	var (
+		found     Player
+		renamedOn time.Time
+	)
+
+	// Several players could have used the name so the last one to stop using it wins
+	for playerID, names := range service.previousNames {
+		player := service.players[playerID]
+		if player.World != params.World {
+			continue
+		}
+
+		name, ok := names[NormalizeUsername(params.Username)]
+		if ok && name.RenamedOn.After(renamedOn) {
+			found, renamedOn = player, name.RenamedOn
+		}
+	}
+
+	if found.ID == "" {
+		return Player{}, fmt.Errorf("unable to get player by previous username from memory: %w", ErrPlayerNotFound)
+	}
+
+	return found, nil
+}
+
+func (service *StorageMemoryService) GetPlayersCreatedOn(
+	_ context.Context,
+	params GetPlayersCreatedOnParams,
+) ([]Player, error) {
+	service.mutex.RLock()
+	defer service.mutex.RUnlock()
+
+	createdOn := params.CreatedOn.Truncate(time.Second)
+
+	var players []Player
+
+	for _, player := range service.players {
+		if player.World == params.World && player.CreatedOn.Equal(createdOn) {
+			players = append(players, player)
+		}
+	}
+
+	slices.SortFunc(players, func(a, b Player) int {
+		return cmp.Compare(a.Username, b.Username)
+	})
+
+	return players, nil
+}
+
+func (service *StorageMemoryService) RenamePlayer(
+	_ context.Context,
+	params RenamePlayerParams,
+) (Player, error) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	player, ok := service.players[params.PlayerID]
+	if !ok {
+		return Player{}, fmt.Errorf("unable to rename player in memory: %w", ErrPlayerNotFound)
+	}
+
+	if player.Username == params.Username {
+		return player, nil
+	}
+
+	if existing, ok := service.findPlayer(player.World, params.Username); ok && existing.ID != player.ID {
+		return Player{}, fmt.Errorf("unable to rename player in memory: %w", errMemoryPlayerExists)
+	}
+
+	names, ok := service.previousNames[player.ID]
+	if !ok {
+		names = make(map[string]PlayerPreviousName)
+		service.previousNames[player.ID] = names
+	}
+
+	// A player that goes back to an old name isn't using it as a previous name anymore
+	names[NormalizeUsername(player.Username)] = PlayerPreviousName{
+		Username:  player.Username,
+		RenamedOn: params.RenamedOn.UTC().Truncate(time.Second),
+	}
+	delete(names, NormalizeUsername(params.Username))
+
+	player.Username = params.Username
+	service.players[player.ID] = player
+
+	return player, nil
+}
+
+func (service *StorageMemoryService) GetPlayerPreviousNames(
+	_ context.Context,
+	playerID string,
+) ([]PlayerPreviousName, error) {
+	service.mutex.RLock()
+	defer service.mutex.RUnlock()
+
+	names := slices.Collect(maps.Values(service.previousNames[playerID]))
+
+	// Newest first
+	slices.SortFunc(names, func(a, b PlayerPreviousName) int {
+		return b.RenamedOn.Compare(a.RenamedOn)
+	})
+
+	return names, nil
+}
+
+func (service *StorageMemoryService) SearchPlayers(
+	_ context.Context,
+	params SearchPlayersParams,
+) ([]Player, error) {
+	service.mutex.RLock()
+	defer service.mutex.RUnlock()
+
+	return searchPlayers(params, func(search playerSearch) ([]Player, error) {
+		var players []Player
+
+		for _, player := range service.players {
+			if player.World != params.World {
+				continue
+			}
+
+			username := NormalizeUsername(player.Username)
+
+			if rank, ok := searchRank(username, search.Query); ok && rank <= search.Rank {
+				players = append(players, player)
+			}
+		}
+
+		slices.SortFunc(players, func(a, b Player) int {
+			aUsername, bUsername := NormalizeUsername(a.Username), NormalizeUsername(b.Username)
+
+			return cmp.Or(
+				cmp.Compare(len(aUsername), len(bUsername)),
+				cmp.Compare(aUsername, bUsername),
+			)
+		})
+
+		return players[:min(search.Limit, len(players))], nil
+	})
+}
+
+func (service *StorageMemoryService) GetPlayerIndex(
+	_ context.Context,
+	params GetPlayerIndexParams,
+) ([]PlayerIndexRecord, error) {
+	service.mutex.RLock()
+	defer service.mutex.RUnlock()
+
+	var index []PlayerIndexRecord
+
+	for _, player := range service.players {
+		if player.World != params.World {
+			continue
+		}
+
+		record := PlayerIndexRecord{
+			PlayerID:        player.ID,
+			Username:        player.Username,
+			TotalLevel:      0,
+			TotalExperience: 0,
+			CombatLevel:     0,
+			LastChanged:     time.Time{},
+		}
+
+		if totals, ok := sumSkills(service.latestSkills(player.ID), SkillOverall); ok {
+			record.TotalLevel = totals.Level
+			record.TotalExperience = totals.Experience
+		}
+
+		if days := service.skills[player.ID]; len(days) > 0 {
+			lastChanged, err := time.Parse(time.DateOnly, slices.Max(slices.Collect(maps.Keys(days))))
+			if err != nil {
+				return nil, fmt.Errorf("unable to parse when player last changed: %w", err)
+			}
+
+			record.LastChanged = lastChanged
+		}
+
+		if days := service.combatLevels[player.ID]; len(days) > 0 {
+			record.CombatLevel = days[slices.Max(slices.Collect(maps.Keys(days)))]
+		}
+
+		index = append(index, record)
+	}
+
+	slices.SortFunc(index, func(a, b PlayerIndexRecord) int {
+		order := comparePlayerIndexRecords(a, b, params.Sort)
+		if params.Descending {
+			order = -order
+		}
+
+		return cmp.Or(
+			order,
+			cmp.Compare(NormalizeUsername(a.Username), NormalizeUsername(b.Username)),
+		)
+	})
+
+	return paginate(index, params.Limit, params.Offset), nil
+}
+
+func comparePlayerIndexRecords(a, b PlayerIndexRecord, sort PlayerIndexSort) int {
+	switch sort {
+	case PlayerIndexSortUsername:
+		return cmp.Compare(NormalizeUsername(a.Username), NormalizeUsername(b.Username))
+	case PlayerIndexSortTotalLevel:
+		return cmp.Compare(a.TotalLevel, b.TotalLevel)
+	case PlayerIndexSortTotalExperience:
+		return cmp.Compare(a.TotalExperience, b.TotalExperience)
+	case PlayerIndexSortCombatLevel:
+		return cmp.Compare(a.CombatLevel, b.CombatLevel)
+	case PlayerIndexSortLastChanged:
+		return a.LastChanged.Compare(b.LastChanged)
+	default:
+		return 0
+	}
+}
+
+func (service *StorageMemoryService) CountPlayers(_ context.Context, world string) (int, error) {
+	service.mutex.RLock()
+	defer service.mutex.RUnlock()
+
+	count := 0
+
+	for _, player := range service.players {
+		if player.World == world {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (service *StorageMemoryService) GetOrCreatePlayerByUsername(
+	_ context.Context,
+	params GetOrCreatePlayerByUsernameParams,
+) (Player, error) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	if player, ok := service.findPlayer(params.World, params.Username); ok {
+		return player, nil
+	}
+
+	return service.createPlayer(params.World, params.Username, params.CreatedOn), nil
+}
+
+func (service *StorageMemoryService) findPlayer(world, username string) (Player, bool) {
+	for _, player := range service.players {
+		if player.World == world && NormalizeUsername(player.Username) == NormalizeUsername(username) {
+			return player, true
+		}
+	}
+
+	return Player{}, false
+}
+
+func (service *StorageMemoryService) createPlayer(world, username string, createdOn time.Time) Player {
+	player := Player{
+		ID:        uuid.New().String(),
+		World:     world,
+		Username:  username,
+		CreatedOn: createdOn.Truncate(time.Second),
+	}
+
+	service.players[player.ID] = player
+
+	return player
+}
+
+func (service *StorageMemoryService) RecordPlayerSkills(
+	_ context.Context,
+	params RecordPlayerSkillsParams,
+) error {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	if _, ok := service.players[params.PlayerID]; !ok {
+		return fmt.Errorf("unable to record player skills in memory: %w", ErrPlayerNotFound)
+	}
+
+	date := params.Date.Format(time.DateOnly)
+	recordedOn := formatSnapshotTime(params.Date)
+
+	days := ensureMap(service.skills, params.PlayerID)
+	daySkills := ensureMap(days, date)
+	snapshots := ensureMap(ensureMap(service.snapshots, params.PlayerID), recordedOn)
+	levels := make(map[string]int, len(params.Skills))
+
+	for name, skill := range params.Skills {
+		daySkills[name] = skill
+		snapshots[name] = skill
+		levels[name] = skill.Level
+	}
+
+	ensureMap(service.combatLevels, params.PlayerID)[date] = combat.Level(levels)
+
+	return nil
+}
+
+func (service *StorageMemoryService) GetPlayerSkills(
+	_ context.Context,
+	params GetPlayerSkillsParams,
+) (map[string]PlayerSkillRecord, error) {
+	service.mutex.RLock()
+	defer service.mutex.RUnlock()
+
+	player, ok := service.findPlayer(params.World, params.Username)
+	if !ok {
+		return map[string]PlayerSkillRecord{}, nil
+	}
+
+	return service.latestSkills(player.ID), nil
+}
+
+// latestSkills returns the most recent record of every skill the player has.
+func (service *StorageMemoryService) latestSkills(playerID string) map[string]PlayerSkillRecord {
+	days := service.skills[playerID]
+	skills := make(map[string]PlayerSkillRecord)
+
+	for _, day := range slices.Sorted(maps.Keys(days)) {
+		maps.Copy(skills, days[day])
+	}
+
+	return skills
+}
+
+func (service *StorageMemoryService) GetPlayerSkillHistory(
+	_ context.Context,
+	params GetPlayerSkillHistoryParams,
+) ([]PlayerSkillSnapshot, error) {
+	service.mutex.RLock()
+	defer service.mutex.RUnlock()
+
+	player, ok := service.findPlayer(params.World, params.Username)
+	if !ok {
+		return []PlayerSkillSnapshot{}, nil
+	}
+
+	if params.Granularity == HistoryGranularityHour {
+		return service.getPlayerHourlyHistory(player.ID, params)
+	}
+
+	fromDay := params.From.Format(time.DateOnly)
+	toDay := params.To.Format(time.DateOnly)
+	days := service.skills[player.ID]
+	snapshots := []PlayerSkillSnapshot{}
+
+	for _, day := range slices.Sorted(maps.Keys(days)) {
+		if day < fromDay || day > toDay {
+			continue
+		}
+
+		record, ok := sumSkills(days[day], params.Skill)
+		if !ok {
+			continue
+		}
+
+		date, err := time.Parse(time.DateOnly, day)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse player skill day from memory: %w", err)
+		}
+
+		snapshots = append(snapshots, PlayerSkillSnapshot{
+			Date:       date,
+			Level:      record.Level,
+			Experience: record.Experience,
+		})
+	}
+
+	return snapshots, nil
+}
+
+func (service *StorageMemoryService) getPlayerHourlyHistory(
+	playerID string,
+	params GetPlayerSkillHistoryParams,
+) ([]PlayerSkillSnapshot, error) {
+	fromTime := formatSnapshotTime(params.From)
+	toTime := formatSnapshotTime(params.To)
+	recorded := service.snapshots[playerID]
+	snapshots := []PlayerSkillSnapshot{}
+
+	for _, recordedOn := range slices.Sorted(maps.Keys(recorded)) {
+		if recordedOn < fromTime || recordedOn > toTime {
+			continue
+		}
+
+		record, ok := sumSkills(recorded[recordedOn], params.Skill)
+		if !ok {
+			continue
+		}
+
+		hour, err := time.Parse(time.RFC3339, recordedOn)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse player skill snapshot time from memory: %w", err)
+		}
+
+		snapshot := PlayerSkillSnapshot{
+			Date:       hour.Truncate(time.Hour),
+			Level:      record.Level,
+			Experience: record.Experience,
+		}
+
+		// Snapshots are sorted so a later one in the same hour replaces the earlier one
+		if len(snapshots) > 0 && snapshots[len(snapshots)-1].Date.Equal(snapshot.Date) {
+			snapshots[len(snapshots)-1] = snapshot
+		} else {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+
+	return snapshots, nil
+}
+
+// sumSkills picks a single skill out of a set of skills, or totals them all for SkillOverall.
+func sumSkills(skills map[string]PlayerSkillRecord, skill string) (PlayerSkillRecord, bool) {
+	if skill != SkillOverall {
+		record, ok := skills[skill]
+
+		return record, ok
+	}
+
+	var total PlayerSkillRecord
+	for _, record := range skills {
+		total.Level += record.Level
+		total.Experience += record.Experience
+	}
+
+	return total, len(skills) > 0
+}
+
+func (service *StorageMemoryService) DeleteIntradaySnapshotsBefore(
+	_ context.Context,
+	before time.Time,
+) (int, error) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	cutoff := formatSnapshotTime(before)
+	deleted := 0
+
+	for _, recorded := range service.snapshots {
+		for recordedOn, skills := range recorded {
+			if recordedOn < cutoff {
+				deleted += len(skills)
+
+				delete(recorded, recordedOn)
+			}
+		}
+	}
+
+	return deleted, nil
+}
+
+func (service *StorageMemoryService) GetPlayerSkillDays(
+	_ context.Context,
+	playerID string,
+) ([]PlayerSkillDay, error) {
+	service.mutex.RLock()
+	defer service.mutex.RUnlock()
+
+	days := service.skills[playerID]
+	skillDays := make([]PlayerSkillDay, 0, len(days))
+
+	for _, day := range slices.Sorted(maps.Keys(days)) {
+		date, err := time.Parse(time.DateOnly, day)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse player skill day from memory: %w", err)
+		}
+
+		skillDays = append(skillDays, PlayerSkillDay{
+			Date:   date,
+			Skills: maps.Clone(days[day]),
+		})
+	}
+
+	return skillDays, nil
+}
+
+func (service *StorageMemoryService) DeletePlayerSkillDays(
+	_ context.Context,
+	params DeletePlayerSkillDaysParams,
+) error {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	for _, day := range params.Days {
+		date := day.Format(time.DateOnly)
+
+		delete(service.skills[params.PlayerID], date)
+		delete(service.combatLevels[params.PlayerID], date)
+	}
+
+	return nil
+}
+
+// memoryHighscoreEntry is a player's standing in a highscore category before it's ranked.
+type memoryHighscoreEntry struct {
+	player     Player
+	level      int
+	experience float64
+}
+
+func (service *StorageMemoryService) GetHighscoresForSkill(
+	_ context.Context,
+	params GetHighscoresForSkillParams,
+) ([]HighscoreSkillRecord, error) {
+	service.mutex.RLock()
+	defer service.mutex.RUnlock()
+
+	var (
+		entries []memoryHighscoreEntry
+		compare func(a, b memoryHighscoreEntry) int
+	)
+
+	switch params.Skill {
+	case SkillCombat:
+		entries = service.combatHighscoreEntries(params.World)
+		compare = compareLevelThenExperience
+	case SkillOverall:
+		entries = service.skillHighscoreEntries(params.World, SkillOverall)
+		compare = compareLevelThenExperience
+	default:
+		entries = service.skillHighscoreEntries(params.World, params.Skill)
+		compare = func(a, b memoryHighscoreEntry) int {
+			return cmp.Compare(b.experience, a.experience)
+		}
+	}
+
+	slices.SortFunc(entries, func(a, b memoryHighscoreEntry) int {
+		return cmp.Or(compare(a, b), cmp.Compare(a.player.Username, b.player.Username))
+	})
+
+	highscores := make([]HighscoreSkillRecord, len(entries))
+	for index, entry := range entries {
+		// Tied players share the rank of the first player they're tied with
+		rank := index + 1
+		if index > 0 && compare(entries[index-1], entry) == 0 {
+			rank = highscores[index-1].Rank
+		}
+
+		highscores[index] = HighscoreSkillRecord{
+			Rank:       rank,
+			PlayerID:   entry.player.ID,
+			Username:   entry.player.Username,
+			Level:      entry.level,
+			Experience: entry.experience,
+		}
+	}
+
+	return paginate(highscores, params.Limit, params.Offset), nil
+}
+
+func compareLevelThenExperience(a, b memoryHighscoreEntry) int {
+	return cmp.Or(cmp.Compare(b.level, a.level), cmp.Compare(b.experience, a.experience))
+}
+
+func (service *StorageMemoryService) skillHighscoreEntries(
+	world string,
+	skill string,
+) []memoryHighscoreEntry {
+	var entries []memoryHighscoreEntry
+
+	for playerID := range service.skills {
+		if service.players[playerID].World != world {
+			continue
+		}
+
+		record, ok := sumSkills(service.latestSkills(playerID), skill)
+		if !ok {
+			continue
+		}
+
+		entries = append(entries, memoryHighscoreEntry{
+			player:     service.players[playerID],
+			level:      record.Level,
+			experience: record.Experience,
+		})
+	}
+
+	return entries
+}
+
+func (service *StorageMemoryService) combatHighscoreEntries(world string) []memoryHighscoreEntry {
+	var entries []memoryHighscoreEntry
+
+	for playerID, days := range service.combatLevels {
+		if len(days) == 0 || service.players[playerID].World != world {
+			continue
+		}
+
+		latestSkills := service.latestSkills(playerID)
+
+		var experience float64
+		for _, skill := range combat.Skills {
+			experience += latestSkills[skill].Experience
+		}
+
+		entries = append(entries, memoryHighscoreEntry{
+			player:     service.players[playerID],
+			level:      days[slices.Max(slices.Collect(maps.Keys(days)))],
+			experience: experience,
+		})
+	}
+
+	return entries
+}
+
+func (service *StorageMemoryService) CountHighscoresForSkill(
+	_ context.Context,
+	params CountHighscoresForSkillParams,
+) (int, error) {
+	service.mutex.RLock()
+	defer service.mutex.RUnlock()
+
+	count := 0
+
+	switch params.Skill {
+	case SkillCombat:
+		for playerID, days := range service.combatLevels {
+			if len(days) > 0 && service.players[playerID].World == params.World {
+				count++
+			}
+		}
+	default:
+		for playerID, days := range service.skills {
+			if service.players[playerID].World != params.World {
+				continue
+			}
+
+			for _, skills := range days {
+				if _, ok := sumSkills(skills, params.Skill); ok {
+					count++
+
+					break
+				}
+			}
+		}
+	}
+
+	return count, nil
+}
+
+func (service *StorageMemoryService) GetPlayerGains(
+	_ context.Context,
+	params GetPlayerGainsParams,
+) (map[string]SkillGain, error) {
+	service.mutex.RLock()
+	defer service.mutex.RUnlock()
+
+	gains := make(map[string]SkillGain)
+
+	var total SkillGain
+
+	if player, ok := service.findPlayer(params.World, params.Username); ok {
+		gains = service.skillGains(player.ID, params.From, params.To)
+
+		for _, gain := range gains {
+			total.StartLevel += gain.StartLevel
+			total.StartExperience += gain.StartExperience
+			total.EndLevel += gain.EndLevel
+			total.EndExperience += gain.EndExperience
+		}
+	}
+
+	gains[SkillOverall] = total
+
+	return gains, nil
+}
+
+// skillGains compares each of a player's skills at the start and end of a period. The start is
+// the last record on or before the first day, or the first record in the period if there isn't
+// one.
+func (service *StorageMemoryService) skillGains(playerID string, from, to time.Time) map[string]SkillGain {
+	fromDay := from.Format(time.DateOnly)
+	toDay := to.Format(time.DateOnly)
+	days := service.skills[playerID]
+	starts := make(map[string]PlayerSkillRecord)
+	ends := make(map[string]PlayerSkillRecord)
+
+	for _, day := range slices.Sorted(maps.Keys(days)) {
+		if day > toDay {
+			break
+		}
+
+		for name, record := range days[day] {
+			if _, ok := starts[name]; !ok || day <= fromDay {
+				starts[name] = record
+			}
+
+			ends[name] = record
+		}
+	}
+
+	gains := make(map[string]SkillGain, len(ends))
+	for name, end := range ends {
+		gains[name] = SkillGain{
+			StartLevel:      starts[name].Level,
+			StartExperience: starts[name].Experience,
+			EndLevel:        end.Level,
+			EndExperience:   end.Experience,
+		}
+	}
+
+	return gains
+}
+
+func (service *StorageMemoryService) GetTopGainers(
+	_ context.Context,
+	params GetTopGainersParams,
+) ([]GainerRecord, error) {
+	service.mutex.RLock()
+	defer service.mutex.RUnlock()
+
+	var gainers []GainerRecord
+
+	for playerID := range service.skills {
+		if service.players[playerID].World != params.World {
+			continue
+		}
+
+		gains := service.skillGains(playerID, params.From, params.To)
+
+		gain, ok := gains[params.Skill]
+		if params.Skill == SkillOverall {
+			gain, ok = SkillGain{}, len(gains) > 0
+
+			for _, skillGain := range gains {
+				gain.StartLevel += skillGain.StartLevel
+				gain.StartExperience += skillGain.StartExperience
+				gain.EndLevel += skillGain.EndLevel
+				gain.EndExperience += skillGain.EndExperience
+			}
+		}
+
+		if !ok || gain.ExperienceGained() <= 0 {
+			continue
+		}
+
+		player := service.players[playerID]
+		gainers = append(gainers, GainerRecord{
+			Rank:     0,
+			PlayerID: player.ID,
+			Username: player.Username,
+			Gain:     gain,
+		})
+	}
+
+	compare := func(a, b GainerRecord) int {
+		return cmp.Compare(b.Gain.ExperienceGained(), a.Gain.ExperienceGained())
+	}
+
+	slices.SortFunc(gainers, func(a, b GainerRecord) int {
+		return cmp.Or(compare(a, b), cmp.Compare(a.Username, b.Username))
+	})
+
+	for index := range gainers {
+		gainers[index].Rank = index + 1
+		if index > 0 && compare(gainers[index-1], gainers[index]) == 0 {
+			gainers[index].Rank = gainers[index-1].Rank
+		}
+	}
+
+	return paginate(gainers, params.Limit, params.Offset), nil
+}
+
+func (service *StorageMemoryService) GetSaveFileStates(
+	_ context.Context,
+	world string,
+) (map[string]SaveFileState, error) {
+	service.mutex.RLock()
+	defer service.mutex.RUnlock()
+
+	states := maps.Clone(service.saveFiles[world])
+	if states == nil {
+		states = make(map[string]SaveFileState)
+	}
+
+	return states, nil
+}
+
+// RecordSaveFileState also releases the save from quarantine since it has now been read
+// successfully.
+func (service *StorageMemoryService) RecordSaveFileState(
+	_ context.Context,
+	state SaveFileState,
+) error {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	ensureMap(service.saveFiles, state.World)[state.Path] = state
+	delete(service.quarantined[state.World], state.Path)
+
+	return nil
+}
+
+func (service *StorageMemoryService) GetQuarantinedSaveFiles(
+	_ context.Context,
+) ([]QuarantinedSaveFile, error) {
+	service.mutex.RLock()
+	defer service.mutex.RUnlock()
+
+	files := []QuarantinedSaveFile{}
+	for _, worldFiles := range service.quarantined {
+		files = slices.AppendSeq(files, maps.Values(worldFiles))
+	}
+
+	slices.SortFunc(files, func(a, b QuarantinedSaveFile) int {
+		return cmp.Or(cmp.Compare(a.World, b.World), cmp.Compare(a.Path, b.Path))
+	})
+
+	return files, nil
+}
+
+func (service *StorageMemoryService) QuarantineSaveFile(
+	_ context.Context,
+	params QuarantineSaveFileParams,
+) error {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	quarantined := ensureMap(service.quarantined, params.World)
+
+	firstSeenOn := params.SeenOn
+	if existing, ok := quarantined[params.Path]; ok {
+		firstSeenOn = existing.FirstSeenOn
+	}
+
+	quarantined[params.Path] = QuarantinedSaveFile{
+		World:       params.World,
+		Path:        params.Path,
+		Hash:        params.Hash,
+		Error:       params.Error,
+		FirstSeenOn: firstSeenOn,
+		LastSeenOn:  params.SeenOn,
+	}
+
+	return nil
+}
+
+func (service *StorageMemoryService) ReleaseSaveFile(
+	_ context.Context,
+	params ReleaseSaveFileParams,
+) error {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	delete(service.quarantined[params.World], params.Path)
+
+	return nil
+}
+
+func (service *StorageMemoryService) StartIngestionRun(
+	_ context.Context,
+	params StartIngestionRunParams,
+) error {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	service.ingestionRuns[params.ID] = IngestionRun{
+		ID:             params.ID,
+		World:          params.World,
+		StartedOn:      params.StartedOn,
+		FinishedOn:     time.Time{},
+		FilesScanned:   0,
+		PlayersUpdated: 0,
+		Error:          "",
+		Failures:       nil,
+	}
+
+	return nil
+}
+
+func (service *StorageMemoryService) FinishIngestionRun(
+	_ context.Context,
+	params FinishIngestionRunParams,
+) error {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	run, ok := service.ingestionRuns[params.ID]
+	if !ok {
+		return nil
+	}
+
+	failures := slices.Clone(params.Failures)
+	slices.SortFunc(failures, func(a, b IngestionFailure) int {
+		return cmp.Compare(a.File, b.File)
+	})
+
+	run.FinishedOn = params.FinishedOn
+	run.FilesScanned = params.FilesScanned
+	run.PlayersUpdated = params.PlayersUpdated
+	run.Error = params.Error
+	run.Failures = append(run.Failures, failures...)
+	service.ingestionRuns[params.ID] = run
+
+	return nil
+}
+
+func (service *StorageMemoryService) DeleteIngestionRunsBefore(
+	_ context.Context,
+	before time.Time,
+) error {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	maps.DeleteFunc(service.ingestionRuns, func(_ string, run IngestionRun) bool {
+		return run.StartedOn.Before(before)
+	})
+
+	return nil
+}
+
+func (service *StorageMemoryService) GetRecentIngestionRuns(
+	_ context.Context,
+	limit int,
+) ([]IngestionRun, error) {
+	service.mutex.RLock()
+	defer service.mutex.RUnlock()
+
+	return paginate(service.sortedIngestionRuns(), limit, 0), nil
+}
+
+func (service *StorageMemoryService) GetLastSuccessfulIngestionRun(
+	_ context.Context,
+) (IngestionRun, error) {
+	service.mutex.RLock()
+	defer service.mutex.RUnlock()
+
+	for _, run := range service.sortedIngestionRuns() {
+		if run.Succeeded() {
+			return run, nil
+		}
+	}
+
+	return IngestionRun{}, ErrIngestionRunNotFound
+}
+
+// sortedIngestionRuns returns every ingestion run, newest first.
+func (service *StorageMemoryService) sortedIngestionRuns() []IngestionRun {
+	runs := slices.Collect(maps.Values(service.ingestionRuns))
+	slices.SortFunc(runs, func(a, b IngestionRun) int {
+		return b.StartedOn.Compare(a.StartedOn)
+	})
+
+	return runs
+}
+
+func ensureMap[K comparable, V any, M ~map[string]map[K]V](m M, key string) map[K]V {
+	if _, ok := m[key]; !ok {
+		m[key] = make(map[K]V)
+	}
+
+	return m[key]
+}
+
+func paginate[T any](values []T, limit, offset int) []T {
+	if offset >= len(values) {
+		return []T{}
+	}
+
+	return values[offset:min(offset+limit, len(values))]
+}
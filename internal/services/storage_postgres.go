@@ -0,0 +1,1114 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cadyyan/void-tool/internal/combat"
+	"github.com/cadyyan/void-tool/internal/database/postgresdb"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type StoragePostgresService struct {
+	pool    *pgxpool.Pool
+	queries *postgresdb.Queries
+}
+
+var _ StorageService = (*StoragePostgresService)(nil)
+
+func NewStoragePostgresService(
+	pool *pgxpool.Pool,
+	queries *postgresdb.Queries,
+) *StoragePostgresService {
+	return &StoragePostgresService{
+		pool:    pool,
+		queries: queries,
+	}
+}
+
+func (service *StoragePostgresService) CreatePlayer(
+	ctx context.Context,
+	params CreatePlayerParams,
+) (Player, error) {
+	record, err := service.queries.CreatePlayer(ctx, postgresdb.CreatePlayerParams{
+		ID:                 uuid.New().String(),
+		World:              params.World,
+		Username:           params.Username,
+		NormalizedUsername: NormalizeUsername(params.Username),
+		CreatedOn:          params.CreatedOn,
+	})
+	if err != nil {
+		return Player{}, fmt.Errorf("unable to create player record in PostgreSQL: %w", err)
+	}
+
+	return playerPostgresRecordToPlayer(record), nil
+}
+
+func (service *StoragePostgresService) GetAllPlayers(
+	ctx context.Context,
+) ([]Player, error) {
+	records, err := service.queries.GetAllPlayers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get all players from PostgreSQL: %w", err)
+	}
+
+	players := make([]Player, len(records))
+	for index, record := range records {
+		players[index] = playerPostgresRecordToPlayer(record)
+	}
+
+	return players, nil
+}
+
+func (service *StoragePostgresService) GetPlayerByUsername(
+	ctx context.Context,
+	params GetPlayerByUsernameParams,
+) (Player, error) {
+	record, err := service.queries.GetPlayerByName(ctx, postgresdb.GetPlayerByNameParams{
+		World:              params.World,
+		NormalizedUsername: NormalizeUsername(params.Username),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Player{}, ErrPlayerNotFound
+	} else if err != nil {
+		return Player{}, fmt.Errorf("unable to get player by username from PostgreSQL: %w", err)
+	}
+
+	return playerPostgresRecordToPlayer(record), nil
+}
+
+func (service *StoragePostgresService) GetPlayerByPreviousUsername(
+	ctx context.Context,
+	params GetPlayerByUsernameParams,
+) (Player, error) {
+	record, err := service.queries.GetPlayerByPreviousName(
+		ctx,
+		postgresdb.GetPlayerByPreviousNameParams{
+			World:              params.World,
+			NormalizedUsername: NormalizeUsername(params.Username),
+		},
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Player{}, ErrPlayerNotFound
+	} else if err != nil {
+		return Player{}, fmt.Errorf("unable to get player by previous username from PostgreSQL: %w", err)
+	}
+
+	return playerPostgresRecordToPlayer(record), nil
+}
+
+func (service *StoragePostgresService) GetPlayersCreatedOn(
+	ctx context.Context,
+	params GetPlayersCreatedOnParams,
+) ([]Player, error) {
+	records, err := service.queries.GetPlayersByCreatedOn(
+		ctx,
+		postgresdb.GetPlayersByCreatedOnParams{
+			World:     params.World,
+			CreatedOn: params.CreatedOn,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get players by creation time from PostgreSQL: %w", err)
+	}
+
+	players := make([]Player, len(records))
+	for index, record := range records {
+		players[index] = playerPostgresRecordToPlayer(record)
+	}
+
+	return players, nil
+}
+
+func (service *StoragePostgresService) RenamePlayer(
+	ctx context.Context,
+	params RenamePlayerParams,
+) (Player, error) {
+	tx, err := service.pool.Begin(ctx)
+	if err != nil {
+		return Player{}, fmt.Errorf("unable to start PostgreSQL transaction to rename player: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	queriesWithTx := service.queries.WithTx(tx)
+
+	record, err := queriesWithTx.GetPlayerByID(ctx, params.PlayerID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Player{}, ErrPlayerNotFound
+	} else if err != nil {
+		return Player{}, fmt.Errorf("unable to get player to rename from PostgreSQL: %w", err)
+	}
+
+	if record.Username == params.Username {
+		return playerPostgresRecordToPlayer(record), nil
+	}
+
+	err = queriesWithTx.RecordPlayerPreviousName(ctx, postgresdb.RecordPlayerPreviousNameParams{
+		PlayerID:           record.ID,
+		Username:           record.Username,
+		NormalizedUsername: NormalizeUsername(record.Username),
+		RenamedOn:          params.RenamedOn.UTC().Truncate(time.Second),
+	})
+	if err != nil {
+		return Player{}, fmt.Errorf("unable to record previous player name in PostgreSQL: %w", err)
+	}
+
+	// A player that goes back to an old name isn't using it as a previous name anymore
+	err = queriesWithTx.DeletePlayerPreviousName(ctx, postgresdb.DeletePlayerPreviousNameParams{
+		PlayerID:           record.ID,
+		NormalizedUsername: NormalizeUsername(params.Username),
+	})
+	if err != nil {
+		return Player{}, fmt.Errorf("unable to delete previous player name from PostgreSQL: %w", err)
+	}
+
+	err = queriesWithTx.RenamePlayer(ctx, postgresdb.RenamePlayerParams{
+		Username:           params.Username,
+		NormalizedUsername: NormalizeUsername(params.Username),
+		ID:                 record.ID,
+	})
+	if err != nil {
+		return Player{}, fmt.Errorf("unable to rename player in PostgreSQL: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return Player{}, fmt.Errorf("unable to commit PostgreSQL transaction to rename player: %w", err)
+	}
+
+	record.Username = params.Username
+
+	return playerPostgresRecordToPlayer(record), nil
+}
+
+func (service *StoragePostgresService) GetPlayerPreviousNames(
+	ctx context.Context,
+	playerID string,
+) ([]PlayerPreviousName, error) {
+	records, err := service.queries.GetPlayerPreviousNames(ctx, playerID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get previous player names from PostgreSQL: %w", err)
+	}
+
+	names := make([]PlayerPreviousName, len(records))
+	for index, record := range records {
+		names[index] = PlayerPreviousName{
+			Username:  record.Username,
+			RenamedOn: record.RenamedOn.UTC(),
+		}
+	}
+
+	return names, nil
+}
+
+func (service *StoragePostgresService) SearchPlayers(
+	ctx context.Context,
+	params SearchPlayersParams,
+) ([]Player, error) {
+	return searchPlayers(params, func(search playerSearch) ([]Player, error) {
+		records, err := service.queries.SearchPlayers(ctx, postgresdb.SearchPlayersParams{
+			World:      params.World,
+			Pattern:    search.Pattern,
+			MaxResults: int32(search.Limit),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to search players in PostgreSQL: %w", err)
+		}
+
+		players := make([]Player, len(records))
+		for index, record := range records {
+			players[index] = playerPostgresRecordToPlayer(record)
+		}
+
+		return players, nil
+	})
+}
+
+func (service *StoragePostgresService) GetPlayerIndex(
+	ctx context.Context,
+	params GetPlayerIndexParams,
+) ([]PlayerIndexRecord, error) {
+	records, err := service.queries.GetPlayerIndex(ctx, postgresdb.GetPlayerIndexParams{
+		World:      params.World,
+		SortBy:     string(params.Sort),
+		Descending: params.Descending,
+		PageSize:   int32(params.Limit),
+		PageOffset: int32(params.Offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get player index from PostgreSQL: %w", err)
+	}
+
+	index := make([]PlayerIndexRecord, len(records))
+	for position, record := range records {
+		lastChanged, err := parseLastChanged(record.LastChanged)
+		if err != nil {
+			return nil, err
+		}
+
+		index[position] = PlayerIndexRecord{
+			PlayerID:        record.ID,
+			Username:        record.Username,
+			TotalLevel:      int(record.TotalLevel),
+			TotalExperience: record.TotalExperience,
+			CombatLevel:     int(record.CombatLevel),
+			LastChanged:     lastChanged,
+		}
+	}
+
+	return index, nil
+}
+
+func (service *StoragePostgresService) CountPlayers(ctx context.Context, world string) (int, error) {
+	count, err := service.queries.CountPlayers(ctx, world)
+	if err != nil {
+		return 0, fmt.Errorf("unable to count players in PostgreSQL: %w", err)
+	}
+
+	return int(count), nil
+}
+
+func (service *StoragePostgresService) GetOrCreatePlayerByUsername(
+	ctx context.Context,
+	params GetOrCreatePlayerByUsernameParams,
+) (Player, error) {
+	err := service.queries.CreatePlayerIfNotExist(
+		ctx,
+		postgresdb.CreatePlayerIfNotExistParams{
+			ID:                 uuid.New().String(),
+			World:              params.World,
+			Username:           params.Username,
+			NormalizedUsername: NormalizeUsername(params.Username),
+			CreatedOn:          params.CreatedOn,
+		},
+	)
+	if err != nil {
+		return Player{}, fmt.Errorf("unable to ensure that player exists in PostgreSQL: %w", err)
+	}
+
+	return service.GetPlayerByUsername(ctx, GetPlayerByUsernameParams{
+		World:    params.World,
+		Username: params.Username,
+	})
+}
+
+func (service *StoragePostgresService) RecordPlayerSkills(
+	ctx context.Context,
+	params RecordPlayerSkillsParams,
+) error {
+	tx, err := service.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to start PostgreSQL transaction to record player skills: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Truncate to match the precision stored by SQLite so both backends report the same history
+	recordedOn := params.Date.UTC().Truncate(time.Second)
+
+	queriesWithTx := service.queries.WithTx(tx)
+	for name, skill := range params.Skills {
+		err := queriesWithTx.RecordPlayerSkill(ctx, postgresdb.RecordPlayerSkillParams{
+			PlayerID:   params.PlayerID,
+			Day:        params.Date,
+			Name:       name,
+			Experience: float64(skill.Experience),
+			Level:      int32(skill.Level),
+		})
+		if err != nil {
+			return fmt.Errorf("unable to record player skill update to PostgreSQL: %w", err)
+		}
+
+		err = queriesWithTx.RecordPlayerSkillSnapshot(ctx, postgresdb.RecordPlayerSkillSnapshotParams{
+			PlayerID:   params.PlayerID,
+			Name:       name,
+			RecordedOn: recordedOn,
+			Level:      int32(skill.Level),
+			Experience: float64(skill.Experience),
+		})
+		if err != nil {
+			return fmt.Errorf("unable to record player skill snapshot to PostgreSQL: %w", err)
+		}
+	}
+
+	levels := make(map[string]int, len(params.Skills))
+	for name, skill := range params.Skills {
+		levels[name] = skill.Level
+	}
+
+	err = queriesWithTx.RecordPlayerCombatLevel(ctx, postgresdb.RecordPlayerCombatLevelParams{
+		PlayerID: params.PlayerID,
+		Day:      params.Date,
+		Level:    int32(combat.Level(levels)),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to record player combat level to PostgreSQL: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("unable to commit player skills to PostgreSQL: %w", err)
+	}
+
+	return nil
+}
+
+func (service *StoragePostgresService) GetPlayerSkills(
+	ctx context.Context,
+	params GetPlayerSkillsParams,
+) (map[string]PlayerSkillRecord, error) {
+	records, err := service.queries.GetAllPlayerSkillsByPlayerName(
+		ctx,
+		postgresdb.GetAllPlayerSkillsByPlayerNameParams{
+			World:              params.World,
+			NormalizedUsername: NormalizeUsername(params.Username),
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get player skills from PostgreSQL: %w", err)
+	}
+
+	skills := make(map[string]PlayerSkillRecord)
+	for _, record := range records {
+		skills[record.Name] = PlayerSkillRecord{
+			Experience: record.Experience,
+			Level:      int(record.Level),
+		}
+	}
+
+	return skills, nil
+}
+
+func (service *StoragePostgresService) GetPlayerSkillHistory(
+	ctx context.Context,
+	params GetPlayerSkillHistoryParams,
+) ([]PlayerSkillSnapshot, error) {
+	switch {
+	case params.Granularity == HistoryGranularityHour && params.Skill == SkillOverall:
+		return service.getPlayerOverallHourlyHistory(ctx, params)
+	case params.Granularity == HistoryGranularityHour:
+		return service.getPlayerSkillHourlyHistory(ctx, params)
+	case params.Skill == SkillOverall:
+		return service.getPlayerOverallHistory(ctx, params)
+	}
+
+	records, err := service.queries.GetPlayerSkillOverTimeByPlayerName(
+		ctx,
+		postgresdb.GetPlayerSkillOverTimeByPlayerNameParams{
+			World:              params.World,
+			NormalizedUsername: NormalizeUsername(params.Username),
+			Name:               params.Skill,
+			FromDay:            params.From,
+			ToDay:              params.To,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get player skill history from PostgreSQL: %w", err)
+	}
+
+	snapshots := make([]PlayerSkillSnapshot, len(records))
+	for index, record := range records {
+		snapshots[index] = PlayerSkillSnapshot{
+			Date:       record.Day,
+			Level:      int(record.Level),
+			Experience: record.Experience,
+		}
+	}
+
+	return snapshots, nil
+}
+
+func (service *StoragePostgresService) getPlayerOverallHistory(
+	ctx context.Context,
+	params GetPlayerSkillHistoryParams,
+) ([]PlayerSkillSnapshot, error) {
+	records, err := service.queries.GetPlayerOverallOverTimeByPlayerName(
+		ctx,
+		postgresdb.GetPlayerOverallOverTimeByPlayerNameParams{
+			World:              params.World,
+			NormalizedUsername: NormalizeUsername(params.Username),
+			FromDay:            params.From,
+			ToDay:              params.To,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get player overall history from PostgreSQL: %w", err)
+	}
+
+	snapshots := make([]PlayerSkillSnapshot, len(records))
+	for index, record := range records {
+		snapshots[index] = PlayerSkillSnapshot{
+			Date:       record.Day,
+			Level:      int(record.TotalLevel),
+			Experience: record.TotalExperience,
+		}
+	}
+
+	return snapshots, nil
+}
+
+func (service *StoragePostgresService) getPlayerSkillHourlyHistory(
+	ctx context.Context,
+	params GetPlayerSkillHistoryParams,
+) ([]PlayerSkillSnapshot, error) {
+	records, err := service.queries.GetPlayerSkillHourlyByPlayerName(
+		ctx,
+		postgresdb.GetPlayerSkillHourlyByPlayerNameParams{
+			World:              params.World,
+			NormalizedUsername: NormalizeUsername(params.Username),
+			Name:               params.Skill,
+			FromTime:           params.From,
+			ToTime:             params.To,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get player hourly skill history from PostgreSQL: %w", err)
+	}
+
+	snapshots := make([]PlayerSkillSnapshot, len(records))
+	for index, record := range records {
+		snapshots[index] = PlayerSkillSnapshot{
+			Date:       record.Hour.UTC(),
+			Level:      int(record.Level),
+			Experience: record.Experience,
+		}
+	}
+
+	return snapshots, nil
+}
+
+func (service *StoragePostgresService) getPlayerOverallHourlyHistory(
+	ctx context.Context,
+	params GetPlayerSkillHistoryParams,
+) ([]PlayerSkillSnapshot, error) {
+	records, err := service.queries.GetPlayerOverallHourlyByPlayerName(
+		ctx,
+		postgresdb.GetPlayerOverallHourlyByPlayerNameParams{
+			World:              params.World,
+			NormalizedUsername: NormalizeUsername(params.Username),
+			FromTime:           params.From,
+			ToTime:             params.To,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get player hourly overall history from PostgreSQL: %w", err)
+	}
+
+	snapshots := make([]PlayerSkillSnapshot, len(records))
+	for index, record := range records {
+		snapshots[index] = PlayerSkillSnapshot{
+			Date:       record.Hour.UTC(),
+			Level:      int(record.TotalLevel),
+			Experience: record.TotalExperience,
+		}
+	}
+
+	return snapshots, nil
+}
+
+func (service *StoragePostgresService) DeleteIntradaySnapshotsBefore(
+	ctx context.Context,
+	before time.Time,
+) (int, error) {
+	deleted, err := service.queries.DeletePlayerSkillSnapshotsBefore(ctx, before)
+	if err != nil {
+		return 0, fmt.Errorf("unable to delete player skill snapshots from PostgreSQL: %w", err)
+	}
+
+	return int(deleted), nil
+}
+
+func (service *StoragePostgresService) GetPlayerSkillDays(
+	ctx context.Context,
+	playerID string,
+) ([]PlayerSkillDay, error) {
+	records, err := service.queries.GetPlayerSkillDaysByPlayerID(ctx, playerID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get player skill days from PostgreSQL: %w", err)
+	}
+
+	var days []PlayerSkillDay
+
+	for _, record := range records {
+		// Records are ordered by day so each day's skills are together
+		if len(days) == 0 || !days[len(days)-1].Date.Equal(record.Day) {
+			days = append(days, PlayerSkillDay{
+				Date:   record.Day,
+				Skills: make(map[string]PlayerSkillRecord),
+			})
+		}
+
+		days[len(days)-1].Skills[record.Name] = PlayerSkillRecord{
+			Level:      int(record.Level),
+			Experience: record.Experience,
+		}
+	}
+
+	return days, nil
+}
+
+func (service *StoragePostgresService) DeletePlayerSkillDays(
+	ctx context.Context,
+	params DeletePlayerSkillDaysParams,
+) error {
+	tx, err := service.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to start PostgreSQL transaction to delete player skill days: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	queriesWithTx := service.queries.WithTx(tx)
+
+	for _, day := range params.Days {
+		err := queriesWithTx.DeletePlayerSkillsOnDay(ctx, postgresdb.DeletePlayerSkillsOnDayParams{
+			PlayerID: params.PlayerID,
+			Day:      day,
+		})
+		if err != nil {
+			return fmt.Errorf("unable to delete player skills from PostgreSQL: %w", err)
+		}
+
+		err = queriesWithTx.DeletePlayerCombatLevelOnDay(ctx, postgresdb.DeletePlayerCombatLevelOnDayParams{
+			PlayerID: params.PlayerID,
+			Day:      day,
+		})
+		if err != nil {
+			return fmt.Errorf("unable to delete player combat level from PostgreSQL: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("unable to commit deleted player skill days to PostgreSQL: %w", err)
+	}
+
+	return nil
+}
+
+func (service *StoragePostgresService) GetHighscoresForSkill(
+	ctx context.Context,
+	params GetHighscoresForSkillParams,
+) ([]HighscoreSkillRecord, error) {
+	switch params.Skill {
+	case SkillOverall:
+		return service.getOverallHighscores(ctx, params)
+	case SkillCombat:
+		return service.getCombatHighscores(ctx, params)
+	}
+
+	records, err := service.queries.GetHighscoresForSkill(
+		ctx,
+		postgresdb.GetHighscoresForSkillParams{
+			World:      params.World,
+			Name:       params.Skill,
+			PageSize:   int32(params.Limit),
+			PageOffset: int32(params.Offset),
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get highscores from PostgreSQL: %w", err)
+	}
+
+	highscores := make([]HighscoreSkillRecord, len(records))
+	for index, record := range records {
+		highscores[index] = HighscoreSkillRecord{
+			Rank:       int(record.PlayerRank),
+			PlayerID:   record.ID,
+			Username:   record.Username,
+			Experience: record.Experience,
+			Level:      int(record.Level),
+		}
+	}
+
+	return highscores, nil
+}
+
+func (service *StoragePostgresService) getOverallHighscores(
+	ctx context.Context,
+	params GetHighscoresForSkillParams,
+) ([]HighscoreSkillRecord, error) {
+	records, err := service.queries.GetOverallHighscores(
+		ctx,
+		postgresdb.GetOverallHighscoresParams{
+			World:      params.World,
+			PageSize:   int32(params.Limit),
+			PageOffset: int32(params.Offset),
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get overall highscores from PostgreSQL: %w", err)
+	}
+
+	highscores := make([]HighscoreSkillRecord, len(records))
+	for index, record := range records {
+		highscores[index] = HighscoreSkillRecord{
+			Rank:       int(record.PlayerRank),
+			PlayerID:   record.ID,
+			Username:   record.Username,
+			Experience: record.TotalExperience,
+			Level:      int(record.TotalLevel),
+		}
+	}
+
+	return highscores, nil
+}
+
+func (service *StoragePostgresService) getCombatHighscores(
+	ctx context.Context,
+	params GetHighscoresForSkillParams,
+) ([]HighscoreSkillRecord, error) {
+	records, err := service.queries.GetCombatHighscores(
+		ctx,
+		postgresdb.GetCombatHighscoresParams{
+			World:      params.World,
+			PageSize:   int32(params.Limit),
+			PageOffset: int32(params.Offset),
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get combat highscores from PostgreSQL: %w", err)
+	}
+
+	highscores := make([]HighscoreSkillRecord, len(records))
+	for index, record := range records {
+		highscores[index] = HighscoreSkillRecord{
+			Rank:       int(record.PlayerRank),
+			PlayerID:   record.ID,
+			Username:   record.Username,
+			Experience: record.CombatExperience,
+			Level:      int(record.Level),
+		}
+	}
+
+	return highscores, nil
+}
+
+func (service *StoragePostgresService) CountHighscoresForSkill(
+	ctx context.Context,
+	params CountHighscoresForSkillParams,
+) (int, error) {
+	var (
+		count int64
+		err   error
+	)
+
+	switch params.Skill {
+	case SkillOverall:
+		count, err = service.queries.CountOverallHighscores(ctx, params.World)
+	case SkillCombat:
+		count, err = service.queries.CountCombatHighscores(ctx, params.World)
+	default:
+		count, err = service.queries.CountHighscoresForSkill(ctx, postgresdb.CountHighscoresForSkillParams{
+			World: params.World,
+			Name:  params.Skill,
+		})
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("unable to count highscore entries in PostgreSQL: %w", err)
+	}
+
+	return int(count), nil
+}
+
+func (service *StoragePostgresService) GetPlayerGains(
+	ctx context.Context,
+	params GetPlayerGainsParams,
+) (map[string]SkillGain, error) {
+	records, err := service.queries.GetPlayerSkillGainsByPlayerName(
+		ctx,
+		postgresdb.GetPlayerSkillGainsByPlayerNameParams{
+			World:              params.World,
+			NormalizedUsername: NormalizeUsername(params.Username),
+			FromDay:            params.From,
+			ToDay:              params.To,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get player gains from PostgreSQL: %w", err)
+	}
+
+	gains := make(map[string]SkillGain, len(records)+1)
+
+	var total SkillGain
+
+	for _, record := range records {
+		gain := SkillGain{
+			StartLevel:      int(record.StartLevel),
+			StartExperience: record.StartExperience,
+			EndLevel:        int(record.EndLevel),
+			EndExperience:   record.EndExperience,
+		}
+
+		total.StartLevel += gain.StartLevel
+		total.StartExperience += gain.StartExperience
+		total.EndLevel += gain.EndLevel
+		total.EndExperience += gain.EndExperience
+
+		gains[record.Name] = gain
+	}
+
+	gains[SkillOverall] = total
+
+	return gains, nil
+}
+
+func (service *StoragePostgresService) GetTopGainers(
+	ctx context.Context,
+	params GetTopGainersParams,
+) ([]GainerRecord, error) {
+	if params.Skill == SkillOverall {
+		return service.getTopOverallGainers(ctx, params)
+	}
+
+	records, err := service.queries.GetTopGainersForSkill(
+		ctx,
+		postgresdb.GetTopGainersForSkillParams{
+			World:      params.World,
+			Name:       params.Skill,
+			FromDay:    params.From,
+			ToDay:      params.To,
+			PageSize:   int32(params.Limit),
+			PageOffset: int32(params.Offset),
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get top gainers from PostgreSQL: %w", err)
+	}
+
+	gainers := make([]GainerRecord, len(records))
+	for index, record := range records {
+		gainers[index] = GainerRecord{
+			Rank:     int(record.PlayerRank),
+			PlayerID: record.ID,
+			Username: record.Username,
+			Gain: SkillGain{
+				StartLevel:      int(record.StartLevel),
+				StartExperience: record.StartExperience,
+				EndLevel:        int(record.EndLevel),
+				EndExperience:   record.EndExperience,
+			},
+		}
+	}
+
+	return gainers, nil
+}
+
+func (service *StoragePostgresService) getTopOverallGainers(
+	ctx context.Context,
+	params GetTopGainersParams,
+) ([]GainerRecord, error) {
+	records, err := service.queries.GetTopOverallGainers(
+		ctx,
+		postgresdb.GetTopOverallGainersParams{
+			World:      params.World,
+			FromDay:    params.From,
+			ToDay:      params.To,
+			PageSize:   int32(params.Limit),
+			PageOffset: int32(params.Offset),
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get top overall gainers from PostgreSQL: %w", err)
+	}
+
+	gainers := make([]GainerRecord, len(records))
+	for index, record := range records {
+		gainers[index] = GainerRecord{
+			Rank:     int(record.PlayerRank),
+			PlayerID: record.ID,
+			Username: record.Username,
+			Gain: SkillGain{
+				StartLevel:      int(record.StartLevel),
+				StartExperience: record.StartExperience,
+				EndLevel:        int(record.EndLevel),
+				EndExperience:   record.EndExperience,
+			},
+		}
+	}
+
+	return gainers, nil
+}
+
+func (service *StoragePostgresService) GetSaveFileStates(
+	ctx context.Context,
+	world string,
+) (map[string]SaveFileState, error) {
+	records, err := service.queries.GetAllSaveFiles(ctx, world)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get save file states from PostgreSQL: %w", err)
+	}
+
+	states := make(map[string]SaveFileState, len(records))
+	for _, record := range records {
+		states[record.Path] = SaveFileState{
+			World:      world,
+			Path:       record.Path,
+			ModifiedOn: record.ModifiedOn,
+			Size:       record.Size,
+			Hash:       record.Hash,
+		}
+	}
+
+	return states, nil
+}
+
+// RecordSaveFileState also releases the save from quarantine since it has now been read
+// successfully.
+func (service *StoragePostgresService) RecordSaveFileState(
+	ctx context.Context,
+	state SaveFileState,
+) error {
+	tx, err := service.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to start PostgreSQL transaction to record save file state: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	queriesWithTx := service.queries.WithTx(tx)
+
+	err = queriesWithTx.RecordSaveFile(ctx, postgresdb.RecordSaveFileParams{
+		World:      state.World,
+		Path:       state.Path,
+		ModifiedOn: state.ModifiedOn,
+		Size:       state.Size,
+		Hash:       state.Hash,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to record save file state to PostgreSQL: %w", err)
+	}
+
+	err = queriesWithTx.ReleaseSaveFile(ctx, postgresdb.ReleaseSaveFileParams{
+		World: state.World,
+		Path:  state.Path,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to release save file from quarantine in PostgreSQL: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("unable to commit save file state to PostgreSQL: %w", err)
+	}
+
+	return nil
+}
+
+func (service *StoragePostgresService) GetQuarantinedSaveFiles(
+	ctx context.Context,
+) ([]QuarantinedSaveFile, error) {
+	records, err := service.queries.GetQuarantinedSaveFiles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get quarantined save files from PostgreSQL: %w", err)
+	}
+
+	files := make([]QuarantinedSaveFile, len(records))
+	for index, record := range records {
+		files[index] = QuarantinedSaveFile{
+			World:       record.World,
+			Path:        record.Path,
+			Hash:        record.Hash,
+			Error:       record.Error,
+			FirstSeenOn: record.FirstSeenOn,
+			LastSeenOn:  record.LastSeenOn,
+		}
+	}
+
+	return files, nil
+}
+
+func (service *StoragePostgresService) QuarantineSaveFile(
+	ctx context.Context,
+	params QuarantineSaveFileParams,
+) error {
+	err := service.queries.QuarantineSaveFile(ctx, postgresdb.QuarantineSaveFileParams{
+		World:  params.World,
+		Path:   params.Path,
+		Hash:   params.Hash,
+		Error:  params.Error,
+		SeenOn: params.SeenOn,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to quarantine save file in PostgreSQL: %w", err)
+	}
+
+	return nil
+}
+
+func (service *StoragePostgresService) ReleaseSaveFile(
+	ctx context.Context,
+	params ReleaseSaveFileParams,
+) error {
+	err := service.queries.ReleaseSaveFile(ctx, postgresdb.ReleaseSaveFileParams{
+		World: params.World,
+		Path:  params.Path,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to release save file from quarantine in PostgreSQL: %w", err)
+	}
+
+	return nil
+}
+
+func (service *StoragePostgresService) StartIngestionRun(
+	ctx context.Context,
+	params StartIngestionRunParams,
+) error {
+	err := service.queries.CreateIngestionRun(ctx, postgresdb.CreateIngestionRunParams{
+		World:     params.World,
+		ID:        params.ID,
+		StartedOn: params.StartedOn,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to create ingestion run in PostgreSQL: %w", err)
+	}
+
+	return nil
+}
+
+func (service *StoragePostgresService) FinishIngestionRun(
+	ctx context.Context,
+	params FinishIngestionRunParams,
+) error {
+	tx, err := service.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to start PostgreSQL transaction to finish ingestion run: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	queriesWithTx := service.queries.WithTx(tx)
+
+	var runError *string
+	if params.Error != "" {
+		runError = &params.Error
+	}
+
+	err = queriesWithTx.FinishIngestionRun(ctx, postgresdb.FinishIngestionRunParams{
+		ID:             params.ID,
+		FinishedOn:     &params.FinishedOn,
+		FilesScanned:   int32(params.FilesScanned),
+		PlayersUpdated: int32(params.PlayersUpdated),
+		Error:          runError,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to finish ingestion run in PostgreSQL: %w", err)
+	}
+
+	for _, failure := range params.Failures {
+		err := queriesWithTx.RecordIngestionRunFailure(ctx, postgresdb.RecordIngestionRunFailureParams{
+			RunID: params.ID,
+			File:  failure.File,
+			Error: failure.Error,
+		})
+		if err != nil {
+			return fmt.Errorf("unable to record ingestion run failure in PostgreSQL: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("unable to commit ingestion run to PostgreSQL: %w", err)
+	}
+
+	return nil
+}
+
+func (service *StoragePostgresService) DeleteIngestionRunsBefore(
+	ctx context.Context,
+	before time.Time,
+) error {
+	if err := service.queries.DeleteIngestionRunsBefore(ctx, before); err != nil {
+		return fmt.Errorf("unable to delete old ingestion runs from PostgreSQL: %w", err)
+	}
+
+	return nil
+}
+
+func (service *StoragePostgresService) GetRecentIngestionRuns(
+	ctx context.Context,
+	limit int,
+) ([]IngestionRun, error) {
+	records, err := service.queries.GetRecentIngestionRuns(ctx, int32(limit))
+	if err != nil {
+		return nil, fmt.Errorf("unable to get recent ingestion runs from PostgreSQL: %w", err)
+	}
+
+	return service.ingestionRunPostgresRecordsToIngestionRuns(ctx, records)
+}
+
+func (service *StoragePostgresService) GetLastSuccessfulIngestionRun(
+	ctx context.Context,
+) (IngestionRun, error) {
+	record, err := service.queries.GetLastSuccessfulIngestionRun(ctx)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return IngestionRun{}, ErrIngestionRunNotFound
+	} else if err != nil {
+		return IngestionRun{}, fmt.Errorf("unable to get last successful ingestion run from PostgreSQL: %w", err)
+	}
+
+	runs, err := service.ingestionRunPostgresRecordsToIngestionRuns(ctx, []postgresdb.IngestionRun{record})
+	if err != nil {
+		return IngestionRun{}, err
+	}
+
+	return runs[0], nil
+}
+
+func (service *StoragePostgresService) ingestionRunPostgresRecordsToIngestionRuns(
+	ctx context.Context,
+	records []postgresdb.IngestionRun,
+) ([]IngestionRun, error) {
+	runIDs := make([]string, len(records))
+	for index, record := range records {
+		runIDs[index] = record.ID
+	}
+
+	failuresByRun := make(map[string][]IngestionFailure)
+
+	if len(runIDs) > 0 {
+		failures, err := service.queries.GetIngestionRunFailures(ctx, runIDs)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get ingestion run failures from PostgreSQL: %w", err)
+		}
+
+		for _, failure := range failures {
+			failuresByRun[failure.RunID] = append(failuresByRun[failure.RunID], IngestionFailure{
+				File:  failure.File,
+				Error: failure.Error,
+			})
+		}
+	}
+
+	runs := make([]IngestionRun, len(records))
+	for index, record := range records {
+		var finishedOn time.Time
+		if record.FinishedOn != nil {
+			finishedOn = *record.FinishedOn
+		}
+
+		var runError string
+		if record.Error != nil {
+			runError = *record.Error
+		}
+
+		runs[index] = IngestionRun{
+			ID:             record.ID,
+			World:          record.World,
+			StartedOn:      record.StartedOn,
+			FinishedOn:     finishedOn,
+			FilesScanned:   int(record.FilesScanned),
+			PlayersUpdated: int(record.PlayersUpdated),
+			Error:          runError,
+			Failures:       failuresByRun[record.ID],
+		}
+	}
+
+	return runs, nil
+}
+
+func playerPostgresRecordToPlayer(dbRecord postgresdb.Player) Player {
+	return Player{
+		ID:        dbRecord.ID,
+		World:     dbRecord.World,
+		Username:  dbRecord.Username,
+		CreatedOn: dbRecord.CreatedOn,
+	}
+}
@@ -3,10 +3,13 @@ package services
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/cadyyan/void-tool/internal/combat"
 	"github.com/cadyyan/void-tool/internal/database/sqlitedb"
+	"github.com/cadyyan/void-tool/internal/metrics"
 	"github.com/google/uuid"
 )
 
@@ -27,6 +30,12 @@ func NewStorageSQLiteService(
 	}
 }
 
+// withTx runs queries in a transaction. They're timed like the rest of the queries, sqlc's WithTx
+// would bypass that.
+func (service *StorageSQLiteService) withTx(tx *sql.Tx) *sqlitedb.Queries {
+	return sqlitedb.New(metrics.ObserveQueries(tx, metrics.DatabaseSQLite))
+}
+
 func (service *StorageSQLiteService) CreatePlayer(
 	ctx context.Context,
 	params CreatePlayerParams,
@@ -34,9 +43,11 @@ func (service *StorageSQLiteService) CreatePlayer(
 	id := uuid.New().String()
 
 	record, err := service.queries.CreatePlayer(ctx, sqlitedb.CreatePlayerParams{
-		ID:        id,
-		Username:  params.Username,
-		CreatedOn: params.CreatedOn.Format(time.RFC3339),
+		ID:                 id,
+		World:              params.World,
+		Username:           params.Username,
+		NormalizedUsername: NormalizeUsername(params.Username),
+		CreatedOn:          params.CreatedOn.Format(time.RFC3339),
 	})
 	if err != nil {
 		return Player{}, fmt.Errorf("unable to create player record in SQLite: %w", err)
@@ -73,13 +84,18 @@ func (service *StorageSQLiteService) GetAllPlayers(
 
 func (service *StorageSQLiteService) GetPlayerByUsername(
 	ctx context.Context,
-	username string,
+	params GetPlayerByUsernameParams,
 ) (Player, error) {
 	record, err := service.queries.GetPlayerByName(
 		ctx,
-		username,
+		sqlitedb.GetPlayerByNameParams{
+			World:              params.World,
+			NormalizedUsername: NormalizeUsername(params.Username),
+		},
 	)
-	if err != nil {
+	if errors.Is(err, sql.ErrNoRows) {
+		return Player{}, ErrPlayerNotFound
+	} else if err != nil {
 		return Player{}, fmt.Errorf("unable to get player by username from SQLite: %w", err)
 	}
 
@@ -91,6 +107,216 @@ func (service *StorageSQLiteService) GetPlayerByUsername(
 	return player, nil
 }
 
+func (service *StorageSQLiteService) GetPlayerByPreviousUsername(
+	ctx context.Context,
+	params GetPlayerByUsernameParams,
+) (Player, error) {
+	record, err := service.queries.GetPlayerByPreviousName(
+		ctx,
+		sqlitedb.GetPlayerByPreviousNameParams{
+			World:              params.World,
+			NormalizedUsername: NormalizeUsername(params.Username),
+		},
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Player{}, ErrPlayerNotFound
+	} else if err != nil {
+		return Player{}, fmt.Errorf("unable to get player by previous username from SQLite: %w", err)
+	}
+
+	return playerSQLiteRecordToPlayer(record)
+}
+
+func (service *StorageSQLiteService) GetPlayersCreatedOn(
+	ctx context.Context,
+	params GetPlayersCreatedOnParams,
+) ([]Player, error) {
+	records, err := service.queries.GetPlayersByCreatedOn(
+		ctx,
+		sqlitedb.GetPlayersByCreatedOnParams{
+			World:     params.World,
+			CreatedOn: params.CreatedOn.Format(time.RFC3339),
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get players by creation time from SQLite: %w", err)
+	}
+
+	players := make([]Player, len(records))
+	for index, record := range records {
+		player, err := playerSQLiteRecordToPlayer(record)
+		if err != nil {
+			return nil, err
+		}
+
+		players[index] = player
+	}
+
+	return players, nil
+}
+
+func (service *StorageSQLiteService) RenamePlayer(
+	ctx context.Context,
+	params RenamePlayerParams,
+) (Player, error) {
+	tx, err := service.db.BeginTx(ctx, nil)
+	if err != nil {
+		return Player{}, fmt.Errorf("unable to start SQLite transaction to rename player: %w", err)
+	}
+	defer tx.Rollback()
+
+	queriesWithTx := service.withTx(tx)
+
+	record, err := queriesWithTx.GetPlayerByID(ctx, params.PlayerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Player{}, ErrPlayerNotFound
+	} else if err != nil {
+		return Player{}, fmt.Errorf("unable to get player to rename from SQLite: %w", err)
+	}
+
+	if record.Username == params.Username {
+		return playerSQLiteRecordToPlayer(record)
+	}
+
+	err = queriesWithTx.RecordPlayerPreviousName(ctx, sqlitedb.RecordPlayerPreviousNameParams{
+		PlayerID:           record.ID,
+		Username:           record.Username,
+		NormalizedUsername: NormalizeUsername(record.Username),
+		RenamedOn:          formatSnapshotTime(params.RenamedOn),
+	})
+	if err != nil {
+		return Player{}, fmt.Errorf("unable to record previous player name in SQLite: %w", err)
+	}
+
+	// A player that goes back to an old name isn't using it as a previous name anymore
+	err = queriesWithTx.DeletePlayerPreviousName(ctx, sqlitedb.DeletePlayerPreviousNameParams{
+		PlayerID:           record.ID,
+		NormalizedUsername: NormalizeUsername(params.Username),
+	})
+	if err != nil {
+		return Player{}, fmt.Errorf("unable to delete previous player name from SQLite: %w", err)
+	}
+
+	err = queriesWithTx.RenamePlayer(ctx, sqlitedb.RenamePlayerParams{
+		Username:           params.Username,
+		NormalizedUsername: NormalizeUsername(params.Username),
+		ID:                 record.ID,
+	})
+	if err != nil {
+		return Player{}, fmt.Errorf("unable to rename player in SQLite: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Player{}, fmt.Errorf("unable to commit SQLite transaction to rename player: %w", err)
+	}
+
+	record.Username = params.Username
+
+	return playerSQLiteRecordToPlayer(record)
+}
+
+func (service *StorageSQLiteService) GetPlayerPreviousNames(
+	ctx context.Context,
+	playerID string,
+) ([]PlayerPreviousName, error) {
+	records, err := service.queries.GetPlayerPreviousNames(ctx, playerID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get previous player names from SQLite: %w", err)
+	}
+
+	names := make([]PlayerPreviousName, len(records))
+	for index, record := range records {
+		renamedOn, err := time.Parse(time.RFC3339, record.RenamedOn)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse when player was renamed: %w", err)
+		}
+
+		names[index] = PlayerPreviousName{
+			Username:  record.Username,
+			RenamedOn: renamedOn,
+		}
+	}
+
+	return names, nil
+}
+
+func (service *StorageSQLiteService) SearchPlayers(
+	ctx context.Context,
+	params SearchPlayersParams,
+) ([]Player, error) {
+	return searchPlayers(params, func(search playerSearch) ([]Player, error) {
+		records, err := service.queries.SearchPlayers(ctx, sqlitedb.SearchPlayersParams{
+			World:      params.World,
+			Pattern:    search.Pattern,
+			MaxResults: int64(search.Limit),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to search players in SQLite: %w", err)
+		}
+
+		players := make([]Player, len(records))
+		for index, record := range records {
+			player, err := playerSQLiteRecordToPlayer(record)
+			if err != nil {
+				return nil, err
+			}
+
+			players[index] = player
+		}
+
+		return players, nil
+	})
+}
+
+func (service *StorageSQLiteService) GetPlayerIndex(
+	ctx context.Context,
+	params GetPlayerIndexParams,
+) ([]PlayerIndexRecord, error) {
+	var descending int64
+	if params.Descending {
+		descending = 1
+	}
+
+	records, err := service.queries.GetPlayerIndex(ctx, sqlitedb.GetPlayerIndexParams{
+		World:      params.World,
+		SortBy:     string(params.Sort),
+		Descending: descending,
+		PageSize:   int64(params.Limit),
+		PageOffset: int64(params.Offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get player index from SQLite: %w", err)
+	}
+
+	index := make([]PlayerIndexRecord, len(records))
+	for position, record := range records {
+		lastChanged, err := parseLastChanged(record.LastChanged)
+		if err != nil {
+			return nil, err
+		}
+
+		index[position] = PlayerIndexRecord{
+			PlayerID:        record.ID,
+			Username:        record.Username,
+			TotalLevel:      int(record.TotalLevel),
+			TotalExperience: record.TotalExperience,
+			CombatLevel:     int(record.CombatLevel),
+			LastChanged:     lastChanged,
+		}
+	}
+
+	return index, nil
+}
+
+func (service *StorageSQLiteService) CountPlayers(ctx context.Context, world string) (int, error) {
+	count, err := service.queries.CountPlayers(ctx, world)
+	if err != nil {
+		return 0, fmt.Errorf("unable to count players in SQLite: %w", err)
+	}
+
+	return int(count), nil
+}
+
 func (service *StorageSQLiteService) GetOrCreatePlayerByUsername(
 	ctx context.Context,
 	params GetOrCreatePlayerByUsernameParams,
@@ -98,15 +324,21 @@ func (service *StorageSQLiteService) GetOrCreatePlayerByUsername(
 	err := service.queries.CreatePlayerIfNotExist(
 		ctx,
 		sqlitedb.CreatePlayerIfNotExistParams{
-			Username:  params.Username,
-			CreatedOn: params.CreatedOn.Format(time.RFC3339),
+			ID:                 uuid.New().String(),
+			World:              params.World,
+			Username:           params.Username,
+			NormalizedUsername: NormalizeUsername(params.Username),
+			CreatedOn:          params.CreatedOn.Format(time.RFC3339),
 		},
 	)
 	if err != nil {
 		return Player{}, fmt.Errorf("unable to ensure that player exists in SQLite: %w", err)
 	}
 
-	return service.GetPlayerByUsername(ctx, params.Username)
+	return service.GetPlayerByUsername(ctx, GetPlayerByUsernameParams{
+		World:    params.World,
+		Username: params.Username,
+	})
 }
 
 func (service *StorageSQLiteService) RecordPlayerSkills(
@@ -120,8 +352,9 @@ func (service *StorageSQLiteService) RecordPlayerSkills(
 	defer tx.Rollback()
 
 	date := params.Date.Format(time.DateOnly)
+	recordedOn := formatSnapshotTime(params.Date)
 
-	queriesWithTx := service.queries.WithTx(tx)
+	queriesWithTx := service.withTx(tx)
 	for name, skill := range params.Skills {
 		err := queriesWithTx.RecordPlayerSkill(ctx, sqlitedb.RecordPlayerSkillParams{
 			PlayerID:   params.PlayerID,
@@ -133,10 +366,35 @@ func (service *StorageSQLiteService) RecordPlayerSkills(
 		if err != nil {
 			return fmt.Errorf("unable to record player skill update to SQLite: %w", err)
 		}
+
+		err = queriesWithTx.RecordPlayerSkillSnapshot(ctx, sqlitedb.RecordPlayerSkillSnapshotParams{
+			PlayerID:   params.PlayerID,
+			Name:       name,
+			RecordedOn: recordedOn,
+			Level:      int64(skill.Level),
+			Experience: float64(skill.Experience),
+		})
+		if err != nil {
+			return fmt.Errorf("unable to record player skill snapshot to SQLite: %w", err)
+		}
+	}
+
+	levels := make(map[string]int, len(params.Skills))
+	for name, skill := range params.Skills {
+		levels[name] = skill.Level
+	}
+
+	err = queriesWithTx.RecordPlayerCombatLevel(ctx, sqlitedb.RecordPlayerCombatLevelParams{
+		PlayerID: params.PlayerID,
+		Day:      date,
+		Level:    int64(combat.Level(levels)),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to record player combat level to SQLite: %w", err)
 	}
 
 	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("unable to ")
+		return fmt.Errorf("unable to commit player skills to SQLite: %w", err)
 	}
 
 	return nil
@@ -144,11 +402,14 @@ func (service *StorageSQLiteService) RecordPlayerSkills(
 
 func (service *StorageSQLiteService) GetPlayerSkills(
 	ctx context.Context,
-	username string,
+	params GetPlayerSkillsParams,
 ) (map[string]PlayerSkillRecord, error) {
 	records, err := service.queries.GetAllPlayerSkillsByPlayerName(
 		ctx,
-		username,
+		sqlitedb.GetAllPlayerSkillsByPlayerNameParams{
+			World:              params.World,
+			NormalizedUsername: NormalizeUsername(params.Username),
+		},
 	)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get player skills from SQLite: %w", err)
@@ -165,28 +426,783 @@ func (service *StorageSQLiteService) GetPlayerSkills(
 	return skills, nil
 }
 
+func (service *StorageSQLiteService) GetPlayerSkillHistory(
+	ctx context.Context,
+	params GetPlayerSkillHistoryParams,
+) ([]PlayerSkillSnapshot, error) {
+	switch {
+	case params.Granularity == HistoryGranularityHour && params.Skill == SkillOverall:
+		return service.getPlayerOverallHourlyHistory(ctx, params)
+	case params.Granularity == HistoryGranularityHour:
+		return service.getPlayerSkillHourlyHistory(ctx, params)
+	case params.Skill == SkillOverall:
+		return service.getPlayerOverallHistory(ctx, params)
+	}
+
+	records, err := service.queries.GetPlayerSkillOverTimeByPlayerName(
+		ctx,
+		sqlitedb.GetPlayerSkillOverTimeByPlayerNameParams{
+			World:              params.World,
+			NormalizedUsername: NormalizeUsername(params.Username),
+			Name:               params.Skill,
+			FromDay:            params.From.Format(time.DateOnly),
+			ToDay:              params.To.Format(time.DateOnly),
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get player skill history from SQLite: %w", err)
+	}
+
+	snapshots := make([]PlayerSkillSnapshot, len(records))
+	for index, record := range records {
+		date, err := time.Parse(time.DateOnly, record.Day)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse player skill day from SQLite: %w", err)
+		}
+
+		snapshots[index] = PlayerSkillSnapshot{
+			Date:       date,
+			Level:      int(record.Level),
+			Experience: record.Experience,
+		}
+	}
+
+	return snapshots, nil
+}
+
+func (service *StorageSQLiteService) getPlayerOverallHistory(
+	ctx context.Context,
+	params GetPlayerSkillHistoryParams,
+) ([]PlayerSkillSnapshot, error) {
+	records, err := service.queries.GetPlayerOverallOverTimeByPlayerName(
+		ctx,
+		sqlitedb.GetPlayerOverallOverTimeByPlayerNameParams{
+			World:              params.World,
+			NormalizedUsername: NormalizeUsername(params.Username),
+			FromDay:            params.From.Format(time.DateOnly),
+			ToDay:              params.To.Format(time.DateOnly),
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get player overall history from SQLite: %w", err)
+	}
+
+	snapshots := make([]PlayerSkillSnapshot, len(records))
+	for index, record := range records {
+		date, err := time.Parse(time.DateOnly, record.Day)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse player skill day from SQLite: %w", err)
+		}
+
+		snapshots[index] = PlayerSkillSnapshot{
+			Date:       date,
+			Level:      int(record.TotalLevel),
+			Experience: record.TotalExperience,
+		}
+	}
+
+	return snapshots, nil
+}
+
+// formatSnapshotTime formats timestamps so they sort chronologically as strings. RFC3339Nano
+// drops trailing zeros so it can't be used here.
+func formatSnapshotTime(value time.Time) string {
+	return value.UTC().Truncate(time.Second).Format(time.RFC3339)
+}
+
+func (service *StorageSQLiteService) getPlayerSkillHourlyHistory(
+	ctx context.Context,
+	params GetPlayerSkillHistoryParams,
+) ([]PlayerSkillSnapshot, error) {
+	records, err := service.queries.GetPlayerSkillHourlyByPlayerName(
+		ctx,
+		sqlitedb.GetPlayerSkillHourlyByPlayerNameParams{
+			World:              params.World,
+			NormalizedUsername: NormalizeUsername(params.Username),
+			Name:               params.Skill,
+			FromTime:           formatSnapshotTime(params.From),
+			ToTime:             formatSnapshotTime(params.To),
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get player hourly skill history from SQLite: %w", err)
+	}
+
+	snapshots := make([]PlayerSkillSnapshot, len(records))
+	for index, record := range records {
+		hour, err := time.Parse(time.RFC3339, record.Hour)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse player skill snapshot hour from SQLite: %w", err)
+		}
+
+		snapshots[index] = PlayerSkillSnapshot{
+			Date:       hour,
+			Level:      int(record.Level),
+			Experience: record.Experience,
+		}
+	}
+
+	return snapshots, nil
+}
+
+func (service *StorageSQLiteService) getPlayerOverallHourlyHistory(
+	ctx context.Context,
+	params GetPlayerSkillHistoryParams,
+) ([]PlayerSkillSnapshot, error) {
+	records, err := service.queries.GetPlayerOverallHourlyByPlayerName(
+		ctx,
+		sqlitedb.GetPlayerOverallHourlyByPlayerNameParams{
+			World:              params.World,
+			NormalizedUsername: NormalizeUsername(params.Username),
+			FromTime:           formatSnapshotTime(params.From),
+			ToTime:             formatSnapshotTime(params.To),
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get player hourly overall history from SQLite: %w", err)
+	}
+
+	snapshots := make([]PlayerSkillSnapshot, len(records))
+	for index, record := range records {
+		hour, err := time.Parse(time.RFC3339, record.Hour)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse player skill snapshot hour from SQLite: %w", err)
+		}
+
+		snapshots[index] = PlayerSkillSnapshot{
+			Date:       hour,
+			Level:      int(record.TotalLevel),
+			Experience: record.TotalExperience,
+		}
+	}
+
+	return snapshots, nil
+}
+
+func (service *StorageSQLiteService) DeleteIntradaySnapshotsBefore(
+	ctx context.Context,
+	before time.Time,
+) (int, error) {
+	deleted, err := service.queries.DeletePlayerSkillSnapshotsBefore(ctx, formatSnapshotTime(before))
+	if err != nil {
+		return 0, fmt.Errorf("unable to delete player skill snapshots from SQLite: %w", err)
+	}
+
+	return int(deleted), nil
+}
+
+func (service *StorageSQLiteService) GetPlayerSkillDays(
+	ctx context.Context,
+	playerID string,
+) ([]PlayerSkillDay, error) {
+	records, err := service.queries.GetPlayerSkillDaysByPlayerID(ctx, playerID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get player skill days from SQLite: %w", err)
+	}
+
+	var days []PlayerSkillDay
+
+	for _, record := range records {
+		date, err := time.Parse(time.DateOnly, record.Day)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse player skill day from SQLite: %w", err)
+		}
+
+		// Records are ordered by day so each day's skills are together
+		if len(days) == 0 || !days[len(days)-1].Date.Equal(date) {
+			days = append(days, PlayerSkillDay{
+				Date:   date,
+				Skills: make(map[string]PlayerSkillRecord),
+			})
+		}
+
+		days[len(days)-1].Skills[record.Name] = PlayerSkillRecord{
+			Level:      int(record.Level),
+			Experience: record.Experience,
+		}
+	}
+
+	return days, nil
+}
+
+func (service *StorageSQLiteService) DeletePlayerSkillDays(
+	ctx context.Context,
+	params DeletePlayerSkillDaysParams,
+) error {
+	tx, err := service.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("unable to start SQLite transaction to delete player skill days: %w", err)
+	}
+	defer tx.Rollback()
+
+	queriesWithTx := service.withTx(tx)
+
+	for _, day := range params.Days {
+		date := day.Format(time.DateOnly)
+
+		err := queriesWithTx.DeletePlayerSkillsOnDay(ctx, sqlitedb.DeletePlayerSkillsOnDayParams{
+			PlayerID: params.PlayerID,
+			Day:      date,
+		})
+		if err != nil {
+			return fmt.Errorf("unable to delete player skills from SQLite: %w", err)
+		}
+
+		err = queriesWithTx.DeletePlayerCombatLevelOnDay(ctx, sqlitedb.DeletePlayerCombatLevelOnDayParams{
+			PlayerID: params.PlayerID,
+			Day:      date,
+		})
+		if err != nil {
+			return fmt.Errorf("unable to delete player combat level from SQLite: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("unable to commit deleted player skill days to SQLite: %w", err)
+	}
+
+	return nil
+}
+
 func (service *StorageSQLiteService) GetHighscoresForSkill(
 	ctx context.Context,
-	skill string,
+	params GetHighscoresForSkillParams,
 ) ([]HighscoreSkillRecord, error) {
-	records, err := service.queries.GetHighscoresForSkill(ctx, skill)
+	switch params.Skill {
+	case SkillOverall:
+		return service.getOverallHighscores(ctx, params)
+	case SkillCombat:
+		return service.getCombatHighscores(ctx, params)
+	}
+
+	records, err := service.queries.GetHighscoresForSkill(
+		ctx,
+		sqlitedb.GetHighscoresForSkillParams{
+			World:      params.World,
+			Name:       params.Skill,
+			PageSize:   int64(params.Limit),
+			PageOffset: int64(params.Offset),
+		},
+	)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get highscores from SQLite: %w", err)
 	}
 
 	highscores := make([]HighscoreSkillRecord, len(records))
-	for index, record := range highscores {
+	for index, record := range records {
 		highscores[index] = HighscoreSkillRecord{
-			PlayerID:   record.PlayerID,
+			Rank:       int(record.PlayerRank),
+			PlayerID:   record.ID,
 			Username:   record.Username,
 			Experience: record.Experience,
-			Level:      record.Level,
+			Level:      int(record.Level),
+		}
+	}
+
+	return highscores, nil
+}
+
+func (service *StorageSQLiteService) getOverallHighscores(
+	ctx context.Context,
+	params GetHighscoresForSkillParams,
+) ([]HighscoreSkillRecord, error) {
+	records, err := service.queries.GetOverallHighscores(
+		ctx,
+		sqlitedb.GetOverallHighscoresParams{
+			World:      params.World,
+			PageSize:   int64(params.Limit),
+			PageOffset: int64(params.Offset),
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get overall highscores from SQLite: %w", err)
+	}
+
+	highscores := make([]HighscoreSkillRecord, len(records))
+	for index, record := range records {
+		highscores[index] = HighscoreSkillRecord{
+			Rank:       int(record.PlayerRank),
+			PlayerID:   record.ID,
+			Username:   record.Username,
+			Experience: record.TotalExperience,
+			Level:      int(record.TotalLevel),
+		}
+	}
+
+	return highscores, nil
+}
+
+func (service *StorageSQLiteService) getCombatHighscores(
+	ctx context.Context,
+	params GetHighscoresForSkillParams,
+) ([]HighscoreSkillRecord, error) {
+	records, err := service.queries.GetCombatHighscores(
+		ctx,
+		sqlitedb.GetCombatHighscoresParams{
+			World:      params.World,
+			PageSize:   int64(params.Limit),
+			PageOffset: int64(params.Offset),
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get combat highscores from SQLite: %w", err)
+	}
+
+	highscores := make([]HighscoreSkillRecord, len(records))
+	for index, record := range records {
+		highscores[index] = HighscoreSkillRecord{
+			Rank:       int(record.PlayerRank),
+			PlayerID:   record.ID,
+			Username:   record.Username,
+			Experience: record.CombatExperience,
+			Level:      int(record.Level),
 		}
 	}
 
 	return highscores, nil
 }
 
+func (service *StorageSQLiteService) CountHighscoresForSkill(
+	ctx context.Context,
+	params CountHighscoresForSkillParams,
+) (int, error) {
+	var (
+		count int64
+		err   error
+	)
+
+	switch params.Skill {
+	case SkillOverall:
+		count, err = service.queries.CountOverallHighscores(ctx, params.World)
+	case SkillCombat:
+		count, err = service.queries.CountCombatHighscores(ctx, params.World)
+	default:
+		count, err = service.queries.CountHighscoresForSkill(ctx, sqlitedb.CountHighscoresForSkillParams{
+			World: params.World,
+			Name:  params.Skill,
+		})
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("unable to count highscore entries in SQLite: %w", err)
+	}
+
+	return int(count), nil
+}
+
+func (service *StorageSQLiteService) GetPlayerGains(
+	ctx context.Context,
+	params GetPlayerGainsParams,
+) (map[string]SkillGain, error) {
+	records, err := service.queries.GetPlayerSkillGainsByPlayerName(
+		ctx,
+		sqlitedb.GetPlayerSkillGainsByPlayerNameParams{
+			World:              params.World,
+			NormalizedUsername: NormalizeUsername(params.Username),
+			FromDay:            params.From.Format(time.DateOnly),
+			ToDay:              params.To.Format(time.DateOnly),
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get player gains from SQLite: %w", err)
+	}
+
+	gains := make(map[string]SkillGain, len(records)+1)
+
+	var total SkillGain
+
+	for _, record := range records {
+		gain := SkillGain{
+			StartLevel:      int(record.StartLevel),
+			StartExperience: record.StartExperience,
+			EndLevel:        int(record.EndLevel),
+			EndExperience:   record.EndExperience,
+		}
+
+		total.StartLevel += gain.StartLevel
+		total.StartExperience += gain.StartExperience
+		total.EndLevel += gain.EndLevel
+		total.EndExperience += gain.EndExperience
+
+		gains[record.Name] = gain
+	}
+
+	gains[SkillOverall] = total
+
+	return gains, nil
+}
+
+func (service *StorageSQLiteService) GetTopGainers(
+	ctx context.Context,
+	params GetTopGainersParams,
+) ([]GainerRecord, error) {
+	if params.Skill == SkillOverall {
+		return service.getTopOverallGainers(ctx, params)
+	}
+
+	records, err := service.queries.GetTopGainersForSkill(
+		ctx,
+		sqlitedb.GetTopGainersForSkillParams{
+			World:      params.World,
+			Name:       params.Skill,
+			FromDay:    params.From.Format(time.DateOnly),
+			ToDay:      params.To.Format(time.DateOnly),
+			PageSize:   int64(params.Limit),
+			PageOffset: int64(params.Offset),
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get top gainers from SQLite: %w", err)
+	}
+
+	gainers := make([]GainerRecord, len(records))
+	for index, record := range records {
+		gainers[index] = GainerRecord{
+			Rank:     int(record.PlayerRank),
+			PlayerID: record.ID,
+			Username: record.Username,
+			Gain: SkillGain{
+				StartLevel:      int(record.StartLevel),
+				StartExperience: record.StartExperience,
+				EndLevel:        int(record.EndLevel),
+				EndExperience:   record.EndExperience,
+			},
+		}
+	}
+
+	return gainers, nil
+}
+
+func (service *StorageSQLiteService) getTopOverallGainers(
+	ctx context.Context,
+	params GetTopGainersParams,
+) ([]GainerRecord, error) {
+	records, err := service.queries.GetTopOverallGainers(
+		ctx,
+		sqlitedb.GetTopOverallGainersParams{
+			World:      params.World,
+			FromDay:    params.From.Format(time.DateOnly),
+			ToDay:      params.To.Format(time.DateOnly),
+			PageSize:   int64(params.Limit),
+			PageOffset: int64(params.Offset),
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get top overall gainers from SQLite: %w", err)
+	}
+
+	gainers := make([]GainerRecord, len(records))
+	for index, record := range records {
+		gainers[index] = GainerRecord{
+			Rank:     int(record.PlayerRank),
+			PlayerID: record.ID,
+			Username: record.Username,
+			Gain: SkillGain{
+				StartLevel:      int(record.StartLevel),
+				StartExperience: record.StartExperience,
+				EndLevel:        int(record.EndLevel),
+				EndExperience:   record.EndExperience,
+			},
+		}
+	}
+
+	return gainers, nil
+}
+
+func (service *StorageSQLiteService) GetSaveFileStates(
+	ctx context.Context,
+	world string,
+) (map[string]SaveFileState, error) {
+	records, err := service.queries.GetAllSaveFiles(ctx, world)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get save file states from SQLite: %w", err)
+	}
+
+	states := make(map[string]SaveFileState, len(records))
+	for _, record := range records {
+		modifiedOn, err := time.Parse(time.RFC3339Nano, record.ModifiedOn)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse save file modified on timestamp from SQLite: %w", err)
+		}
+
+		states[record.Path] = SaveFileState{
+			World:      world,
+			Path:       record.Path,
+			ModifiedOn: modifiedOn,
+			Size:       record.Size,
+			Hash:       record.Hash,
+		}
+	}
+
+	return states, nil
+}
+
+// RecordSaveFileState also releases the save from quarantine since it has now been read
+// successfully.
+func (service *StorageSQLiteService) RecordSaveFileState(
+	ctx context.Context,
+	state SaveFileState,
+) error {
+	tx, err := service.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("unable to start SQLite transaction to record save file state: %w", err)
+	}
+	defer tx.Rollback()
+
+	queriesWithTx := service.withTx(tx)
+
+	err = queriesWithTx.RecordSaveFile(ctx, sqlitedb.RecordSaveFileParams{
+		World:      state.World,
+		Path:       state.Path,
+		ModifiedOn: state.ModifiedOn.UTC().Format(time.RFC3339Nano),
+		Size:       state.Size,
+		Hash:       state.Hash,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to record save file state to SQLite: %w", err)
+	}
+
+	err = queriesWithTx.ReleaseSaveFile(ctx, sqlitedb.ReleaseSaveFileParams{
+		World: state.World,
+		Path:  state.Path,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to release save file from quarantine in SQLite: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("unable to commit save file state to SQLite: %w", err)
+	}
+
+	return nil
+}
+
+func (service *StorageSQLiteService) GetQuarantinedSaveFiles(
+	ctx context.Context,
+) ([]QuarantinedSaveFile, error) {
+	records, err := service.queries.GetQuarantinedSaveFiles(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get quarantined save files from SQLite: %w", err)
+	}
+
+	files := make([]QuarantinedSaveFile, len(records))
+	for index, record := range records {
+		firstSeenOn, err := time.Parse(time.RFC3339Nano, record.FirstSeenOn)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse quarantined save file timestamp from SQLite: %w", err)
+		}
+
+		lastSeenOn, err := time.Parse(time.RFC3339Nano, record.LastSeenOn)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse quarantined save file timestamp from SQLite: %w", err)
+		}
+
+		files[index] = QuarantinedSaveFile{
+			World:       record.World,
+			Path:        record.Path,
+			Hash:        record.Hash,
+			Error:       record.Error,
+			FirstSeenOn: firstSeenOn,
+			LastSeenOn:  lastSeenOn,
+		}
+	}
+
+	return files, nil
+}
+
+func (service *StorageSQLiteService) QuarantineSaveFile(
+	ctx context.Context,
+	params QuarantineSaveFileParams,
+) error {
+	err := service.queries.QuarantineSaveFile(ctx, sqlitedb.QuarantineSaveFileParams{
+		World:  params.World,
+		Path:   params.Path,
+		Hash:   params.Hash,
+		Error:  params.Error,
+		SeenOn: params.SeenOn.UTC().Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to quarantine save file in SQLite: %w", err)
+	}
+
+	return nil
+}
+
+func (service *StorageSQLiteService) ReleaseSaveFile(
+	ctx context.Context,
+	params ReleaseSaveFileParams,
+) error {
+	err := service.queries.ReleaseSaveFile(ctx, sqlitedb.ReleaseSaveFileParams{
+		World: params.World,
+		Path:  params.Path,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to release save file from quarantine in SQLite: %w", err)
+	}
+
+	return nil
+}
+
+func (service *StorageSQLiteService) StartIngestionRun(
+	ctx context.Context,
+	params StartIngestionRunParams,
+) error {
+	err := service.queries.CreateIngestionRun(ctx, sqlitedb.CreateIngestionRunParams{
+		World:     params.World,
+		ID:        params.ID,
+		StartedOn: params.StartedOn.UTC().Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to create ingestion run in SQLite: %w", err)
+	}
+
+	return nil
+}
+
+func (service *StorageSQLiteService) FinishIngestionRun(
+	ctx context.Context,
+	params FinishIngestionRunParams,
+) error {
+	tx, err := service.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("unable to start SQLite transaction to finish ingestion run: %w", err)
+	}
+	defer tx.Rollback()
+
+	queriesWithTx := service.withTx(tx)
+
+	err = queriesWithTx.FinishIngestionRun(ctx, sqlitedb.FinishIngestionRunParams{
+		ID: params.ID,
+		FinishedOn: sql.NullString{
+			String: params.FinishedOn.UTC().Format(time.RFC3339Nano),
+			Valid:  true,
+		},
+		FilesScanned:   int64(params.FilesScanned),
+		PlayersUpdated: int64(params.PlayersUpdated),
+		Error: sql.NullString{
+			String: params.Error,
+			Valid:  params.Error != "",
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to finish ingestion run in SQLite: %w", err)
+	}
+
+	for _, failure := range params.Failures {
+		err := queriesWithTx.RecordIngestionRunFailure(ctx, sqlitedb.RecordIngestionRunFailureParams{
+			RunID: params.ID,
+			File:  failure.File,
+			Error: failure.Error,
+		})
+		if err != nil {
+			return fmt.Errorf("unable to record ingestion run failure in SQLite: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("unable to commit ingestion run to SQLite: %w", err)
+	}
+
+	return nil
+}
+
+func (service *StorageSQLiteService) DeleteIngestionRunsBefore(
+	ctx context.Context,
+	before time.Time,
+) error {
+	err := service.queries.DeleteIngestionRunsBefore(ctx, before.UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		return fmt.Errorf("unable to delete old ingestion runs from SQLite: %w", err)
+	}
+
+	return nil
+}
+
+func (service *StorageSQLiteService) GetRecentIngestionRuns(
+	ctx context.Context,
+	limit int,
+) ([]IngestionRun, error) {
+	records, err := service.queries.GetRecentIngestionRuns(ctx, int64(limit))
+	if err != nil {
+		return nil, fmt.Errorf("unable to get recent ingestion runs from SQLite: %w", err)
+	}
+
+	return service.ingestionRunSQLiteRecordsToIngestionRuns(ctx, records)
+}
+
+func (service *StorageSQLiteService) GetLastSuccessfulIngestionRun(
+	ctx context.Context,
+) (IngestionRun, error) {
+	record, err := service.queries.GetLastSuccessfulIngestionRun(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return IngestionRun{}, ErrIngestionRunNotFound
+	} else if err != nil {
+		return IngestionRun{}, fmt.Errorf("unable to get last successful ingestion run from SQLite: %w", err)
+	}
+
+	runs, err := service.ingestionRunSQLiteRecordsToIngestionRuns(ctx, []sqlitedb.IngestionRun{record})
+	if err != nil {
+		return IngestionRun{}, err
+	}
+
+	return runs[0], nil
+}
+
+func (service *StorageSQLiteService) ingestionRunSQLiteRecordsToIngestionRuns(
+	ctx context.Context,
+	records []sqlitedb.IngestionRun,
+) ([]IngestionRun, error) {
+	runIDs := make([]string, len(records))
+	for index, record := range records {
+		runIDs[index] = record.ID
+	}
+
+	failuresByRun := make(map[string][]IngestionFailure)
+
+	if len(runIDs) > 0 {
+		failures, err := service.queries.GetIngestionRunFailures(ctx, runIDs)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get ingestion run failures from SQLite: %w", err)
+		}
+
+		for _, failure := range failures {
+			failuresByRun[failure.RunID] = append(failuresByRun[failure.RunID], IngestionFailure{
+				File:  failure.File,
+				Error: failure.Error,
+			})
+		}
+	}
+
+	runs := make([]IngestionRun, len(records))
+	for index, record := range records {
+		startedOn, err := time.Parse(time.RFC3339Nano, record.StartedOn)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse ingestion run started on timestamp from SQLite: %w", err)
+		}
+
+		var finishedOn time.Time
+		if record.FinishedOn.Valid {
+			finishedOn, err = time.Parse(time.RFC3339Nano, record.FinishedOn.String)
+			if err != nil {
+				return nil, fmt.Errorf("unable to parse ingestion run finished on timestamp from SQLite: %w", err)
+			}
+		}
+
+		runs[index] = IngestionRun{
+			ID:             record.ID,
+			World:          record.World,
+			StartedOn:      startedOn,
+			FinishedOn:     finishedOn,
+			FilesScanned:   int(record.FilesScanned),
+			PlayersUpdated: int(record.PlayersUpdated),
+			Error:          record.Error.String,
+			Failures:       failuresByRun[record.ID],
+		}
+	}
+
+	return runs, nil
+}
+
 func playerSQLiteRecordToPlayer(dbRecord sqlitedb.Player) (Player, error) {
 	createdOn, err := time.Parse(time.RFC3339, dbRecord.CreatedOn)
 	if err != nil {
@@ -195,6 +1211,7 @@ func playerSQLiteRecordToPlayer(dbRecord sqlitedb.Player) (Player, error) {
 
 	return Player{
 		ID:        dbRecord.ID,
+		World:     dbRecord.World,
 		Username:  dbRecord.Username,
 		CreatedOn: createdOn,
 	}, nil
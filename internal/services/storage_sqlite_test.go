@@ -0,0 +1,160 @@
+package services_test
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/cadyyan/void-tool/database/sqlite"
+	"github.com/cadyyan/void-tool/internal/configuration"
+	"github.com/cadyyan/void-tool/internal/database/sqlitedb"
+	"github.com/cadyyan/void-tool/internal/services"
+	"github.com/cadyyan/void-tool/internal/services/storagetest"
+	"github.com/golang-migrate/migrate/v4"
+	migrateSQLiteDriver "github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+func TestStorageSQLiteService(t *testing.T) {
+	t.Parallel()
+
+	storagetest.Run(t, func(t *testing.T) services.StorageService {
+		t.Helper()
+
+		config := configuration.SQLiteConfiguration{
+			Path: filepath.Join(t.TempDir(), "void-tool.db"),
+		}
+
+		db, err := config.Connect()
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+
+		if err := newSQLiteMigrator(t, db).Up(); !errors.Is(err, migrate.ErrNoChange) {
+			require.NoError(t, err)
+		}
+
+		return services.NewStorageSQLiteService(db, sqlitedb.New(db))
+	})
+}
+
+func newSQLiteMigrator(t *testing.T, db *sql.DB) *migrate.Migrate {
+	t.Helper()
+
+	sourceDriver, err := iofs.New(sqlite.MigrationsFS, "migrations")
+	require.NoError(t, err)
+
+	dbDriver, err := migrateSQLiteDriver.WithInstance(db, &migrateSQLiteDriver.Config{})
+	require.NoError(t, err)
+
+	migrator, err := migrate.NewWithInstance("iofs", sourceDriver, "sqlite", dbDriver)
+	require.NoError(t, err)
+
+	return migrator
+}
+
+// TestSQLiteNormalizedUsernamesMigration checks that players who were recorded under names that
+// only differ by case or separators are merged rather than stopping the migration.
+func TestSQLiteNormalizedUsernamesMigration(t *testing.T) {
+	t.Parallel()
+
+	config := configuration.SQLiteConfiguration{
+		Path: filepath.Join(t.TempDir(), "void-tool.db"),
+	}
+
+	db, err := config.Connect()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	migrator := newSQLiteMigrator(t, db)
+
+	// The migration before normalized usernames were added
+	require.NoError(t, migrator.Migrate(1792361437))
+
+	_, err = db.ExecContext(t.Context(), `
+		INSERT INTO players (id, world, username, created_on) VALUES
+			('zezima-1', 'main', 'Zezima', '2026-01-01T00:00:00Z'),
+			('zezima-2', 'main', 'zezima', '2026-02-01T00:00:00Z'),
+			('zezima-3', 'leagues', 'Zezima', '2026-02-01T00:00:00Z'),
+			('iron-man-1', 'main', 'iron man', '2026-01-01T00:00:00Z'),
+			('iron-man-2', 'main', 'iron_man', '2026-02-01T00:00:00Z');
+
+		INSERT INTO player_skills (player_id, name, day, level, experience) VALUES
+			('zezima-1', 'Attack', '2026-03-01', 50, 101333),
+			('zezima-1', 'Attack', '2026-03-02', 51, 111945),
+			('zezima-2', 'Attack', '2026-02-01', 30, 13363),
+			('zezima-2', 'Attack', '2026-03-01', 40, 37224);
+
+		INSERT INTO player_combat_levels (player_id, day, level) VALUES
+			('zezima-1', '2026-03-01', 40),
+			('zezima-2', '2026-02-01', 25),
+			('zezima-2', '2026-03-01', 30);
+
+		INSERT INTO player_skill_snapshots (player_id, name, recorded_on, level, experience) VALUES
+			('zezima-2', 'Attack', '2026-02-01T12:00:00Z', 30, 13363);
+
+		INSERT INTO player_previous_names (player_id, username, renamed_on) VALUES
+			('zezima-2', 'Zez', '2026-01-15T00:00:00Z');
+	`)
+	require.NoError(t, err)
+
+	require.NoError(t, migrator.Up())
+
+	require.Equal(
+		t,
+		[]string{"iron-man-2", "zezima-1", "zezima-3"},
+		queryStrings(t, db, "SELECT id FROM players ORDER BY id"),
+	)
+
+	// Records that both players had keep the values of the one that's kept
+	require.Equal(
+		t,
+		[]string{"2026-02-01 30", "2026-03-01 50", "2026-03-02 51"},
+		queryStrings(t, db, `
+			SELECT day || ' ' || level FROM player_skills WHERE player_id = 'zezima-1' ORDER BY day
+		`),
+	)
+	require.Equal(
+		t,
+		[]string{"2026-02-01 25", "2026-03-01 40"},
+		queryStrings(t, db, `
+			SELECT day || ' ' || level FROM player_combat_levels
+			WHERE player_id = 'zezima-1'
+			ORDER BY day
+		`),
+	)
+	require.Equal(
+		t,
+		[]string{"zezima-1"},
+		queryStrings(t, db, "SELECT player_id FROM player_skill_snapshots"),
+	)
+	require.Equal(
+		t,
+		[]string{"zezima-1 zez"},
+		queryStrings(t, db, "SELECT player_id || ' ' || normalized_username FROM player_previous_names"),
+	)
+}
+
+func queryStrings(t *testing.T, db *sql.DB, query string) []string {
+	t.Helper()
+
+	rows, err := db.QueryContext(t.Context(), query)
+	require.NoError(t, err)
+
+	defer rows.Close()
+
+	var values []string
+
+	for rows.Next() {
+		var value string
+		require.NoError(t, rows.Scan(&value))
+
+		values = append(values, value)
+	}
+
+	require.NoError(t, rows.Err())
+
+	return values
+}
@@ -0,0 +1,284 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/cadyyan/void-tool/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracedStorageService traces every call to another storage service. Things that don't exist
+// are recorded on the span without failing it since callers look them up as a matter of course.
+type TracedStorageService struct {
+	next StorageService
+}
+
+var _ StorageService = (*TracedStorageService)(nil)
+
+func NewTracedStorageService(next StorageService) *TracedStorageService {
+	return &TracedStorageService{
+		next: next,
+	}
+}
+
+func traceStorageCall[T any](
+	ctx context.Context,
+	method string,
+	call func(ctx context.Context) (T, error),
+) (T, error) {
+	ctx, span := tracing.Start(
+		ctx,
+		"StorageService."+method,
+		trace.WithAttributes(attribute.String("storage.method", method)),
+	)
+
+	result, err := call(ctx)
+	tracing.End(span, err, ErrNotFound)
+
+	return result, err
+}
+
+func traceStorageCallWithoutResult(
+	ctx context.Context,
+	method string,
+	call func(ctx context.Context) error,
+) error {
+	_, err := traceStorageCall(ctx, method, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, call(ctx)
+	})
+
+	return err
+}
+
+func (service *TracedStorageService) CreatePlayer(ctx context.Context, params CreatePlayerParams) (Player, error) {
+	return traceStorageCall(ctx, "CreatePlayer", func(ctx context.Context) (Player, error) {
+		return service.next.CreatePlayer(ctx, params)
+	})
+}
+
+func (service *TracedStorageService) GetAllPlayers(ctx context.Context) ([]Player, error) {
+	return traceStorageCall(ctx, "GetAllPlayers", func(ctx context.Context) ([]Player, error) {
+		return service.next.GetAllPlayers(ctx)
+	})
+}
+
+func (service *TracedStorageService) GetPlayerByUsername(
+	ctx context.Context,
+	params GetPlayerByUsernameParams,
+) (Player, error) {
+	return traceStorageCall(ctx, "GetPlayerByUsername", func(ctx context.Context) (Player, error) {
+		return service.next.GetPlayerByUsername(ctx, params)
+	})
+}
+
+func (service *TracedStorageService) GetPlayerByPreviousUsername(
+	ctx context.Context,
+	params GetPlayerByUsernameParams,
+) (Player, error) {
+	return traceStorageCall(ctx, "GetPlayerByPreviousUsername", func(ctx context.Context) (Player, error) {
+		return service.next.GetPlayerByPreviousUsername(ctx, params)
+	})
+}
+
+func (service *TracedStorageService) GetPlayersCreatedOn(
+	ctx context.Context,
+	params GetPlayersCreatedOnParams,
+) ([]Player, error) {
+	return traceStorageCall(ctx, "GetPlayersCreatedOn", func(ctx context.Context) ([]Player, error) {
+		return service.next.GetPlayersCreatedOn(ctx, params)
+	})
+}
+
+func (service *TracedStorageService) RenamePlayer(ctx context.Context, params RenamePlayerParams) (Player, error) {
+	return traceStorageCall(ctx, "RenamePlayer", func(ctx context.Context) (Player, error) {
+		return service.next.RenamePlayer(ctx, params)
+	})
+}
+
+func (service *TracedStorageService) GetPlayerPreviousNames(
+	ctx context.Context,
+	playerID string,
+) ([]PlayerPreviousName, error) {
+	return traceStorageCall(ctx, "GetPlayerPreviousNames", func(ctx context.Context) ([]PlayerPreviousName, error) {
+		return service.next.GetPlayerPreviousNames(ctx, playerID)
+	})
+}
+
+func (service *TracedStorageService) SearchPlayers(ctx context.Context, params SearchPlayersParams) ([]Player, error) {
+	return traceStorageCall(ctx, "SearchPlayers", func(ctx context.Context) ([]Player, error) {
+		return service.next.SearchPlayers(ctx, params)
+	})
+}
+
+func (service *TracedStorageService) GetPlayerIndex(
+	ctx context.Context,
+	params GetPlayerIndexParams,
+) ([]PlayerIndexRecord, error) {
+	return traceStorageCall(ctx, "GetPlayerIndex", func(ctx context.Context) ([]PlayerIndexRecord, error) {
+		return service.next.GetPlayerIndex(ctx, params)
+	})
+}
+
+func (service *TracedStorageService) CountPlayers(ctx context.Context, world string) (int, error) {
+	return traceStorageCall(ctx, "CountPlayers", func(ctx context.Context) (int, error) {
+		return service.next.CountPlayers(ctx, world)
+	})
+}
+
+func (service *TracedStorageService) GetOrCreatePlayerByUsername(
+	ctx context.Context,
+	params GetOrCreatePlayerByUsernameParams,
+) (Player, error) {
+	return traceStorageCall(ctx, "GetOrCreatePlayerByUsername", func(ctx context.Context) (Player, error) {
+		return service.next.GetOrCreatePlayerByUsername(ctx, params)
+	})
+}
+
+func (service *TracedStorageService) RecordPlayerSkills(ctx context.Context, params RecordPlayerSkillsParams) error {
+	return traceStorageCallWithoutResult(ctx, "RecordPlayerSkills", func(ctx context.Context) error {
+		return service.next.RecordPlayerSkills(ctx, params)
+	})
+}
+
+func (service *TracedStorageService) GetPlayerSkills(
+	ctx context.Context,
+	params GetPlayerSkillsParams,
+) (map[string]PlayerSkillRecord, error) {
+	return traceStorageCall(ctx, "GetPlayerSkills", func(ctx context.Context) (map[string]PlayerSkillRecord, error) {
+		return service.next.GetPlayerSkills(ctx, params)
+	})
+}
+
+func (service *TracedStorageService) GetPlayerSkillHistory(
+	ctx context.Context,
+	params GetPlayerSkillHistoryParams,
+) ([]PlayerSkillSnapshot, error) {
+	return traceStorageCall(ctx, "GetPlayerSkillHistory", func(ctx context.Context) ([]PlayerSkillSnapshot, error) {
+		return service.next.GetPlayerSkillHistory(ctx, params)
+	})
+}
+
+func (service *TracedStorageService) GetHighscoresForSkill(
+	ctx context.Context,
+	params GetHighscoresForSkillParams,
+) ([]HighscoreSkillRecord, error) {
+	return traceStorageCall(ctx, "GetHighscoresForSkill", func(ctx context.Context) ([]HighscoreSkillRecord, error) {
+		return service.next.GetHighscoresForSkill(ctx, params)
+	})
+}
+
+func (service *TracedStorageService) CountHighscoresForSkill(
+	ctx context.Context,
+	params CountHighscoresForSkillParams,
+) (int, error) {
+	return traceStorageCall(ctx, "CountHighscoresForSkill", func(ctx context.Context) (int, error) {
+		return service.next.CountHighscoresForSkill(ctx, params)
+	})
+}
+
+func (service *TracedStorageService) GetPlayerGains(
+	ctx context.Context,
+	params GetPlayerGainsParams,
+) (map[string]SkillGain, error) {
+	return traceStorageCall(ctx, "GetPlayerGains", func(ctx context.Context) (map[string]SkillGain, error) {
+		return service.next.GetPlayerGains(ctx, params)
+	})
+}
+
+func (service *TracedStorageService) GetTopGainers(
+	ctx context.Context,
+	params GetTopGainersParams,
+) ([]GainerRecord, error) {
+	return traceStorageCall(ctx, "GetTopGainers", func(ctx context.Context) ([]GainerRecord, error) {
+		return service.next.GetTopGainers(ctx, params)
+	})
+}
+
+func (service *TracedStorageService) DeleteIntradaySnapshotsBefore(ctx context.Context, before time.Time) (int, error) {
+	return traceStorageCall(ctx, "DeleteIntradaySnapshotsBefore", func(ctx context.Context) (int, error) {
+		return service.next.DeleteIntradaySnapshotsBefore(ctx, before)
+	})
+}
+
+func (service *TracedStorageService) GetPlayerSkillDays(
+	ctx context.Context,
+	playerID string,
+) ([]PlayerSkillDay, error) {
+	return traceStorageCall(ctx, "GetPlayerSkillDays", func(ctx context.Context) ([]PlayerSkillDay, error) {
+		return service.next.GetPlayerSkillDays(ctx, playerID)
+	})
+}
+
+func (service *TracedStorageService) DeletePlayerSkillDays(
+	ctx context.Context,
+	params DeletePlayerSkillDaysParams,
+) error {
+	return traceStorageCallWithoutResult(ctx, "DeletePlayerSkillDays", func(ctx context.Context) error {
+		return service.next.DeletePlayerSkillDays(ctx, params)
+	})
+}
+
+func (service *TracedStorageService) GetSaveFileStates(
+	ctx context.Context,
+	world string,
+) (map[string]SaveFileState, error) {
+	return traceStorageCall(ctx, "GetSaveFileStates", func(ctx context.Context) (map[string]SaveFileState, error) {
+		return service.next.GetSaveFileStates(ctx, world)
+	})
+}
+
+func (service *TracedStorageService) RecordSaveFileState(ctx context.Context, state SaveFileState) error {
+	return traceStorageCallWithoutResult(ctx, "RecordSaveFileState", func(ctx context.Context) error {
+		return service.next.RecordSaveFileState(ctx, state)
+	})
+}
+
+func (service *TracedStorageService) GetQuarantinedSaveFiles(ctx context.Context) ([]QuarantinedSaveFile, error) {
+	return traceStorageCall(ctx, "GetQuarantinedSaveFiles", func(ctx context.Context) ([]QuarantinedSaveFile, error) {
+		return service.next.GetQuarantinedSaveFiles(ctx)
+	})
+}
+
+func (service *TracedStorageService) QuarantineSaveFile(ctx context.Context, params QuarantineSaveFileParams) error {
+	return traceStorageCallWithoutResult(ctx, "QuarantineSaveFile", func(ctx context.Context) error {
+		return service.next.QuarantineSaveFile(ctx, params)
+	})
+}
+
+func (service *TracedStorageService) ReleaseSaveFile(ctx context.Context, params ReleaseSaveFileParams) error {
+	return traceStorageCallWithoutResult(ctx, "ReleaseSaveFile", func(ctx context.Context) error {
+		return service.next.ReleaseSaveFile(ctx, params)
+	})
+}
+
+func (service *TracedStorageService) StartIngestionRun(ctx context.Context, params StartIngestionRunParams) error {
+	return traceStorageCallWithoutResult(ctx, "StartIngestionRun", func(ctx context.Context) error {
+		return service.next.StartIngestionRun(ctx, params)
+	})
+}
+
+func (service *TracedStorageService) FinishIngestionRun(ctx context.Context, params FinishIngestionRunParams) error {
+	return traceStorageCallWithoutResult(ctx, "FinishIngestionRun", func(ctx context.Context) error {
+		return service.next.FinishIngestionRun(ctx, params)
+	})
+}
+
+func (service *TracedStorageService) DeleteIngestionRunsBefore(ctx context.Context, before time.Time) error {
+	return traceStorageCallWithoutResult(ctx, "DeleteIngestionRunsBefore", func(ctx context.Context) error {
+		return service.next.DeleteIngestionRunsBefore(ctx, before)
+	})
+}
+
+func (service *TracedStorageService) GetRecentIngestionRuns(ctx context.Context, limit int) ([]IngestionRun, error) {
+	return traceStorageCall(ctx, "GetRecentIngestionRuns", func(ctx context.Context) ([]IngestionRun, error) {
+		return service.next.GetRecentIngestionRuns(ctx, limit)
+	})
+}
+
+func (service *TracedStorageService) GetLastSuccessfulIngestionRun(ctx context.Context) (IngestionRun, error) {
+	return traceStorageCall(ctx, "GetLastSuccessfulIngestionRun", func(ctx context.Context) (IngestionRun, error) {
+		return service.next.GetLastSuccessfulIngestionRun(ctx)
+	})
+}
@@ -0,0 +1,16 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/cadyyan/void-tool/internal/services"
+	"github.com/cadyyan/void-tool/internal/services/storagetest"
+)
+
+func TestTracedStorageService(t *testing.T) {
+	t.Parallel()
+
+	storagetest.Run(t, func(_ *testing.T) services.StorageService {
+		return services.NewTracedStorageService(services.NewStorageMemoryService())
+	})
+}
@@ -0,0 +1,1369 @@
+// Package storagetest is a conformance test suite for services.StorageService implementations.
+package storagetest
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/cadyyan/void-tool/internal/services"
+	"github.com/stretchr/testify/require"
+)
+
+// NewStorageFunc returns an empty storage service. It's called once for every test.
+type NewStorageFunc func(t *testing.T) services.StorageService
+
+var (
+	day1 = time.Date(2026, time.January, 5, 0, 0, 0, 0, time.UTC)
+	day2 = day1.AddDate(0, 0, 1)
+	day3 = day1.AddDate(0, 0, 2)
+)
+
+const (
+	world      = services.DefaultWorld
+	otherWorld = "leagues"
+)
+
+// Run checks that a storage service behaves the same way as every other implementation.
+func Run(t *testing.T, newStorage NewStorageFunc) {
+	t.Helper()
+
+	tests := map[string]func(t *testing.T, storage services.StorageService){
+		"CreatePlayer":                      testCreatePlayer,
+		"GetOrCreatePlayerIsIdempotent":     testGetOrCreatePlayerIsIdempotent,
+		"RenamePlayer":                      testRenamePlayer,
+		"UsernamesAreNormalized":            testUsernamesAreNormalized,
+		"PlayerNotFound":                    testPlayerNotFound,
+		"SearchPlayers":                     testSearchPlayers,
+		"PlayerIndex":                       testPlayerIndex,
+		"RecordPlayerSkillsUpserts":         testRecordPlayerSkillsUpserts,
+		"GetPlayerSkillsSelectsLatest":      testGetPlayerSkillsSelectsLatest,
+		"PlayerSkillHistory":                testPlayerSkillHistory,
+		"PlayerSkillHourlyHistory":          testPlayerSkillHourlyHistory,
+		"SkillHighscoreOrdering":            testSkillHighscoreOrdering,
+		"HighscoresUseLatestSkills":         testHighscoresUseLatestSkills,
+		"OverallHighscoreOrdering":          testOverallHighscoreOrdering,
+		"CombatHighscoreOrdering":           testCombatHighscoreOrdering,
+		"PlayerGains":                       testPlayerGains,
+		"TopGainers":                        testTopGainers,
+		"DeleteIntradaySnapshots":           testDeleteIntradaySnapshots,
+		"PlayerSkillDays":                   testPlayerSkillDays,
+		"WorldsAreSeparate":                 testWorldsAreSeparate,
+		"SaveFileStates":                    testSaveFileStates,
+		"QuarantinedSaveFiles":              testQuarantinedSaveFiles,
+		"IngestionRuns":                     testIngestionRuns,
+		"LastSuccessfulIngestionRunMissing": testLastSuccessfulIngestionRunMissing,
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			test(t, newStorage(t))
+		})
+	}
+}
+
+func testCreatePlayer(t *testing.T, storage services.StorageService) {
+	t.Helper()
+
+	createdOn := time.Date(2026, time.January, 2, 3, 4, 5, 0, time.UTC)
+
+	player, err := storage.CreatePlayer(t.Context(), services.CreatePlayerParams{
+		World:     world,
+		Username:  "zezima",
+		CreatedOn: createdOn,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, player.ID)
+	require.Equal(t, "zezima", player.Username)
+	requireSameTime(t, createdOn, player.CreatedOn)
+
+	found, err := storage.GetPlayerByUsername(t.Context(), services.GetPlayerByUsernameParams{
+		World:    world,
+		Username: "zezima",
+	})
+	require.NoError(t, err)
+	require.Equal(t, player.ID, found.ID)
+	require.Equal(t, player.Username, found.Username)
+	requireSameTime(t, createdOn, found.CreatedOn)
+
+	_, err = storage.CreatePlayer(t.Context(), services.CreatePlayerParams{
+		World:     world,
+		Username:  "zezima",
+		CreatedOn: createdOn,
+	})
+	require.Error(t, err, "usernames must be unique")
+
+	_, err = storage.GetPlayerByUsername(t.Context(), services.GetPlayerByUsernameParams{
+		World:    world,
+		Username: "nobody",
+	})
+	require.ErrorIs(t, err, services.ErrPlayerNotFound)
+}
+
+func testGetOrCreatePlayerIsIdempotent(t *testing.T, storage services.StorageService) {
+	t.Helper()
+
+	params := services.GetOrCreatePlayerByUsernameParams{
+		World:     world,
+		Username:  "zezima",
+		CreatedOn: day1,
+	}
+
+	first, err := storage.GetOrCreatePlayerByUsername(t.Context(), params)
+	require.NoError(t, err)
+	require.NotEmpty(t, first.ID)
+
+	params.CreatedOn = day2
+
+	second, err := storage.GetOrCreatePlayerByUsername(t.Context(), params)
+	require.NoError(t, err)
+	require.Equal(t, first.ID, second.ID)
+	requireSameTime(t, day1, second.CreatedOn)
+
+	players, err := storage.GetAllPlayers(t.Context())
+	require.NoError(t, err)
+	require.Len(t, players, 1)
+}
+
+func testRenamePlayer(t *testing.T, storage services.StorageService) {
+	t.Helper()
+
+	player := createPlayer(t, storage, "zezima")
+	other := createPlayer(t, storage, "durial321")
+	recordSkills(t, storage, player, day1, map[string]services.PlayerSkillRecord{
+		"Attack": {Level: 10, Experience: 1_154},
+	})
+
+	createdOn, err := storage.GetPlayersCreatedOn(t.Context(), services.GetPlayersCreatedOnParams{
+		World:     world,
+		CreatedOn: day1,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"durial321", "zezima"}, usernames(createdOn))
+
+	createdOn, err = storage.GetPlayersCreatedOn(t.Context(), services.GetPlayersCreatedOnParams{
+		World:     otherWorld,
+		CreatedOn: day1,
+	})
+	require.NoError(t, err)
+	require.Empty(t, createdOn)
+
+	renamed, err := storage.RenamePlayer(t.Context(), services.RenamePlayerParams{
+		PlayerID:  player.ID,
+		Username:  "zezima2",
+		RenamedOn: day2,
+	})
+	require.NoError(t, err)
+	require.Equal(t, player.ID, renamed.ID)
+	require.Equal(t, "zezima2", renamed.Username)
+
+	_, err = storage.GetPlayerByUsername(t.Context(), services.GetPlayerByUsernameParams{
+		World:    world,
+		Username: "zezima",
+	})
+	require.ErrorIs(t, err, services.ErrPlayerNotFound)
+
+	found, err := storage.GetPlayerByPreviousUsername(t.Context(), services.GetPlayerByUsernameParams{
+		World:    world,
+		Username: "zezima",
+	})
+	require.NoError(t, err)
+	require.Equal(t, player.ID, found.ID)
+	require.Equal(t, "zezima2", found.Username)
+
+	_, err = storage.GetPlayerByPreviousUsername(t.Context(), services.GetPlayerByUsernameParams{
+		World:    otherWorld,
+		Username: "zezima",
+	})
+	require.ErrorIs(t, err, services.ErrPlayerNotFound)
+
+	skills, err := storage.GetPlayerSkills(t.Context(), services.GetPlayerSkillsParams{
+		World:    world,
+		Username: "zezima2",
+	})
+	require.NoError(t, err)
+	require.Equal(t, 10, skills["Attack"].Level, "history must follow the player")
+
+	_, err = storage.RenamePlayer(t.Context(), services.RenamePlayerParams{
+		PlayerID:  other.ID,
+		Username:  "zezima2",
+		RenamedOn: day2,
+	})
+	require.Error(t, err, "usernames must stay unique")
+
+	_, err = storage.RenamePlayer(t.Context(), services.RenamePlayerParams{
+		PlayerID:  player.ID,
+		Username:  "zezima3",
+		RenamedOn: day3,
+	})
+	require.NoError(t, err)
+
+	names, err := storage.GetPlayerPreviousNames(t.Context(), player.ID)
+	require.NoError(t, err)
+	require.Len(t, names, 2)
+	require.Equal(t, "zezima2", names[0].Username, "newest names come first")
+	requireSameTime(t, day3, names[0].RenamedOn)
+	require.Equal(t, "zezima", names[1].Username)
+	requireSameTime(t, day2, names[1].RenamedOn)
+
+	// Going back to an old name makes it the current name again
+	_, err = storage.RenamePlayer(t.Context(), services.RenamePlayerParams{
+		PlayerID:  player.ID,
+		Username:  "zezima",
+		RenamedOn: day3.Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	names, err = storage.GetPlayerPreviousNames(t.Context(), player.ID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"zezima3", "zezima2"}, []string{names[0].Username, names[1].Username})
+
+	_, err = storage.RenamePlayer(t.Context(), services.RenamePlayerParams{
+		PlayerID:  "missing",
+		Username:  "nobody",
+		RenamedOn: day3,
+	})
+	require.ErrorIs(t, err, services.ErrPlayerNotFound)
+}
+
+func testUsernamesAreNormalized(t *testing.T, storage services.StorageService) {
+	t.Helper()
+
+	player := createPlayer(t, storage, "Iron Man")
+	recordSkills(t, storage, player, day1, map[string]services.PlayerSkillRecord{
+		"Attack": {Level: 10, Experience: 1_154},
+	})
+
+	for _, username := range []string{"Iron Man", "iron man", "IRON_MAN", "iron-man", " iron\u00a0man "} {
+		found, err := storage.GetPlayerByUsername(t.Context(), services.GetPlayerByUsernameParams{
+			World:    world,
+			Username: username,
+		})
+		require.NoError(t, err, username)
+		require.Equal(t, player.ID, found.ID, username)
+		require.Equal(t, "Iron Man", found.Username, "the display name is kept")
+
+		skills, err := storage.GetPlayerSkills(t.Context(), services.GetPlayerSkillsParams{
+			World:    world,
+			Username: username,
+		})
+		require.NoError(t, err, username)
+		require.Equal(t, 10, skills["Attack"].Level, username)
+	}
+
+	_, err := storage.CreatePlayer(t.Context(), services.CreatePlayerParams{
+		World:     world,
+		Username:  "iron_man",
+		CreatedOn: day1,
+	})
+	require.Error(t, err, "normalized usernames must be unique")
+
+	same, err := storage.GetOrCreatePlayerByUsername(
+		t.Context(),
+		services.GetOrCreatePlayerByUsernameParams{
+			World:     world,
+			Username:  "IRON-MAN",
+			CreatedOn: day1,
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, player.ID, same.ID)
+
+	// Only changing how a name is written isn't kept as a previous name
+	renamed, err := storage.RenamePlayer(t.Context(), services.RenamePlayerParams{
+		PlayerID:  player.ID,
+		Username:  "iron man",
+		RenamedOn: day2,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "iron man", renamed.Username)
+
+	names, err := storage.GetPlayerPreviousNames(t.Context(), player.ID)
+	require.NoError(t, err)
+	require.Empty(t, names)
+
+	_, err = storage.RenamePlayer(t.Context(), services.RenamePlayerParams{
+		PlayerID:  player.ID,
+		Username:  "Steel Man",
+		RenamedOn: day3,
+	})
+	require.NoError(t, err)
+
+	found, err := storage.GetPlayerByPreviousUsername(t.Context(), services.GetPlayerByUsernameParams{
+		World:    world,
+		Username: "IRON_MAN",
+	})
+	require.NoError(t, err)
+	require.Equal(t, player.ID, found.ID)
+}
+
+func testPlayerNotFound(t *testing.T, storage services.StorageService) {
+	t.Helper()
+
+	player := createPlayer(t, storage, "alice")
+
+	params := services.GetPlayerByUsernameParams{
+		World:    world,
+		Username: "bob",
+	}
+
+	_, err := storage.GetPlayerByUsername(t.Context(), params)
+	require.ErrorIs(t, err, services.ErrPlayerNotFound)
+	require.ErrorIs(t, err, services.ErrNotFound)
+
+	_, err = storage.GetPlayerByPreviousUsername(t.Context(), params)
+	require.ErrorIs(t, err, services.ErrPlayerNotFound)
+
+	// Players in other worlds aren't found either
+	_, err = storage.GetPlayerByUsername(t.Context(), services.GetPlayerByUsernameParams{
+		World:    otherWorld,
+		Username: player.Username,
+	})
+	require.ErrorIs(t, err, services.ErrPlayerNotFound)
+
+	_, err = storage.RenamePlayer(t.Context(), services.RenamePlayerParams{
+		PlayerID:  "00000000-0000-0000-0000-000000000000",
+		Username:  "carol",
+		RenamedOn: day2,
+	})
+	require.ErrorIs(t, err, services.ErrPlayerNotFound)
+}
+
+func testSearchPlayers(t *testing.T, storage services.StorageService) {
+	t.Helper()
+
+	for _, username := range []string{"Zezima", "Zezima Jr", "Lord Zezima", "Zaz Mage", "Iron_Man", "Ironman"} {
+		createPlayer(t, storage, username)
+	}
+
+	_, err := storage.CreatePlayer(t.Context(), services.CreatePlayerParams{
+		World:     otherWorld,
+		Username:  "Zezima",
+		CreatedOn: day1,
+	})
+	require.NoError(t, err)
+
+	search := func(query string, limit int) []string {
+		t.Helper()
+
+		players, err := storage.SearchPlayers(t.Context(), services.SearchPlayersParams{
+			World: world,
+			Query: query,
+			Limit: limit,
+		})
+		require.NoError(t, err, query)
+
+		for _, player := range players {
+			require.Equal(t, world, player.World, query)
+		}
+
+		return usernames(players)
+	}
+
+	require.Equal(
+		t,
+		[]string{"Zezima", "Zezima Jr", "Lord Zezima"},
+		search("ZEZIMA", 0),
+		"exact matches come first, then prefixes, then the rest",
+	)
+	require.Equal(
+		t,
+		[]string{"Zezima", "Zaz Mage", "Zezima Jr", "Lord Zezima"},
+		search("zzma", 0),
+		"shorter names come first",
+	)
+	require.Equal(t, []string{"Zezima"}, search("zez", 1))
+	require.Equal(
+		t,
+		[]string{"Zezima", "Zezima Jr", "Lord Zezima"},
+		search("zima", 0),
+		"names that don't start with the query are found when too few do",
+	)
+	require.Equal(
+		t,
+		[]string{"Zezima", "Zezima Jr"},
+		search("zezim", 2),
+		"names that start with the query are enough when there are as many as the limit",
+	)
+	require.Equal(t, []string{"Iron_Man"}, search("iron man", 0), "spaces are underscores")
+
+	// Enough short names fuzzily match "mage" to fill every candidate the fuzzy search loads but
+	// a longer name containing it is still a closer match
+	for index := range 11 {
+		createPlayer(t, storage, "Ma Ge "+strconv.Itoa(index))
+	}
+
+	require.Equal(
+		t,
+		[]string{"Zaz Mage"},
+		search("mage", 1),
+		"names containing the query come before fuzzy matches that are shorter",
+	)
+
+	require.Empty(t, search("", 0))
+	require.Empty(t, search("   ", 0))
+
+	// Underscores and percent signs aren't LIKE wildcards
+	require.Equal(t, []string{"Iron_Man"}, search("n_m", 0))
+	require.Empty(t, search("%", 0))
+}
+
+func testPlayerIndex(t *testing.T, storage services.StorageService) {
+	t.Helper()
+
+	alice := createPlayer(t, storage, "alice")
+	recordSkills(t, storage, alice, day1, map[string]services.PlayerSkillRecord{
+		"Attack":   {Level: 1, Experience: 0},
+		"Strength": {Level: 1, Experience: 0},
+	})
+	recordSkills(t, storage, alice, day3, map[string]services.PlayerSkillRecord{
+		"Attack":   {Level: 60, Experience: 273_742},
+		"Strength": {Level: 50, Experience: 101_333},
+	})
+
+	bob := createPlayer(t, storage, "Bob")
+	recordSkills(t, storage, bob, day2, map[string]services.PlayerSkillRecord{
+		"Attack":   {Level: 99, Experience: 13_034_431},
+		"Strength": {Level: 1, Experience: 0},
+	})
+
+	createPlayer(t, storage, "carol")
+
+	_, err := storage.CreatePlayer(t.Context(), services.CreatePlayerParams{
+		World:     otherWorld,
+		Username:  "dave",
+		CreatedOn: day1,
+	})
+	require.NoError(t, err)
+
+	index := func(sort services.PlayerIndexSort, descending bool, limit, offset int) []services.PlayerIndexRecord {
+		t.Helper()
+
+		records, err := storage.GetPlayerIndex(t.Context(), services.GetPlayerIndexParams{
+			World:      world,
+			Sort:       sort,
+			Descending: descending,
+			Limit:      limit,
+			Offset:     offset,
+		})
+		require.NoError(t, err, sort)
+
+		return records
+	}
+
+	indexUsernames := func(records []services.PlayerIndexRecord) []string {
+		names := make([]string, len(records))
+		for position, record := range records {
+			names[position] = record.Username
+		}
+
+		return names
+	}
+
+	records := index(services.PlayerIndexSortUsername, false, 10, 0)
+	require.Equal(t, []string{"alice", "Bob", "carol"}, indexUsernames(records))
+
+	require.Equal(t, alice.ID, records[0].PlayerID)
+	require.Equal(t, 110, records[0].TotalLevel, "only the latest skills count")
+	require.InDelta(t, 375_075, records[0].TotalExperience, 0)
+	requireSameTime(t, day3, records[0].LastChanged)
+
+	require.Equal(t, 0, records[2].TotalLevel, "players without skills are still listed")
+	require.Equal(t, 0, records[2].CombatLevel)
+	require.True(t, records[2].LastChanged.IsZero())
+
+	require.Greater(t, records[0].CombatLevel, records[2].CombatLevel)
+
+	require.Equal(
+		t,
+		[]string{"carol", "Bob", "alice"},
+		indexUsernames(index(services.PlayerIndexSortUsername, true, 10, 0)),
+	)
+	require.Equal(
+		t,
+		[]string{"alice", "Bob", "carol"},
+		indexUsernames(index(services.PlayerIndexSortTotalLevel, true, 10, 0)),
+	)
+	require.Equal(
+		t,
+		[]string{"Bob", "alice", "carol"},
+		indexUsernames(index(services.PlayerIndexSortTotalExperience, true, 10, 0)),
+	)
+	require.Equal(
+		t,
+		[]string{"carol", "Bob", "alice"},
+		indexUsernames(index(services.PlayerIndexSortLastChanged, false, 10, 0)),
+	)
+	require.Equal(
+		t,
+		[]string{"carol"},
+		indexUsernames(index(services.PlayerIndexSortCombatLevel, false, 1, 0)),
+	)
+	require.Equal(
+		t,
+		[]string{"Bob", "carol"},
+		indexUsernames(index(services.PlayerIndexSortUsername, false, 2, 1)),
+		"pages start at the offset",
+	)
+
+	count, err := storage.CountPlayers(t.Context(), world)
+	require.NoError(t, err)
+	require.Equal(t, 3, count)
+}
+
+func testRecordPlayerSkillsUpserts(t *testing.T, storage services.StorageService) {
+	t.Helper()
+
+	player := createPlayer(t, storage, "zezima")
+
+	recordSkills(t, storage, player, day1, map[string]services.PlayerSkillRecord{
+		"Attack":   {Level: 10, Experience: 1_154},
+		"Strength": {Level: 5, Experience: 388},
+	})
+	recordSkills(t, storage, player, day1.Add(time.Hour), map[string]services.PlayerSkillRecord{
+		"Attack": {Level: 12, Experience: 1_584},
+	})
+
+	skills, err := storage.GetPlayerSkills(t.Context(), services.GetPlayerSkillsParams{
+		World:    world,
+		Username: "zezima",
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]services.PlayerSkillRecord{
+		"Attack":   {Level: 12, Experience: 1_584},
+		"Strength": {Level: 5, Experience: 388},
+	}, skills)
+
+	history, err := storage.GetPlayerSkillHistory(t.Context(), services.GetPlayerSkillHistoryParams{
+		World:       world,
+		Username:    "zezima",
+		Skill:       "Attack",
+		From:        day1,
+		To:          day3,
+		Granularity: services.HistoryGranularityDay,
+	})
+	require.NoError(t, err)
+	require.Len(t, history, 1, "recording the same day twice must update it")
+	require.Equal(t, 12, history[0].Level)
+}
+
+func testGetPlayerSkillsSelectsLatest(t *testing.T, storage services.StorageService) {
+	t.Helper()
+
+	player := createPlayer(t, storage, "zezima")
+
+	// Recorded out of order to make sure the latest day wins rather than the last write
+	recordSkills(t, storage, player, day3, map[string]services.PlayerSkillRecord{
+		"Attack": {Level: 30, Experience: 13_363},
+	})
+	recordSkills(t, storage, player, day1, map[string]services.PlayerSkillRecord{
+		"Attack":   {Level: 10, Experience: 1_154},
+		"Strength": {Level: 5, Experience: 388},
+	})
+	recordSkills(t, storage, player, day2, map[string]services.PlayerSkillRecord{
+		"Attack": {Level: 20, Experience: 4_470},
+	})
+
+	skills, err := storage.GetPlayerSkills(t.Context(), services.GetPlayerSkillsParams{
+		World:    world,
+		Username: "zezima",
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]services.PlayerSkillRecord{
+		"Attack":   {Level: 30, Experience: 13_363},
+		"Strength": {Level: 5, Experience: 388},
+	}, skills)
+
+	skills, err = storage.GetPlayerSkills(t.Context(), services.GetPlayerSkillsParams{
+		World:    world,
+		Username: "nobody",
+	})
+	require.NoError(t, err)
+	require.Empty(t, skills)
+}
+
+func testPlayerSkillHistory(t *testing.T, storage services.StorageService) {
+	t.Helper()
+
+	player := createPlayer(t, storage, "zezima")
+
+	recordSkills(t, storage, player, day1, map[string]services.PlayerSkillRecord{
+		"Attack":   {Level: 10, Experience: 1_154},
+		"Strength": {Level: 5, Experience: 388},
+	})
+	recordSkills(t, storage, player, day2, map[string]services.PlayerSkillRecord{
+		"Attack":   {Level: 20, Experience: 4_470},
+		"Strength": {Level: 6, Experience: 512},
+	})
+	recordSkills(t, storage, player, day3, map[string]services.PlayerSkillRecord{
+		"Attack":   {Level: 30, Experience: 13_363},
+		"Strength": {Level: 7, Experience: 650},
+	})
+
+	history, err := storage.GetPlayerSkillHistory(t.Context(), services.GetPlayerSkillHistoryParams{
+		World:       world,
+		Username:    "zezima",
+		Skill:       "Attack",
+		From:        day2,
+		To:          day3.Add(12 * time.Hour),
+		Granularity: services.HistoryGranularityDay,
+	})
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	requireSameTime(t, day2, history[0].Date)
+	require.Equal(t, 20, history[0].Level)
+	require.InDelta(t, 4_470, history[0].Experience, 0)
+	requireSameTime(t, day3, history[1].Date)
+	require.Equal(t, 30, history[1].Level)
+
+	overall, err := storage.GetPlayerSkillHistory(t.Context(), services.GetPlayerSkillHistoryParams{
+		World:       world,
+		Username:    "zezima",
+		Skill:       services.SkillOverall,
+		From:        day1,
+		To:          day1,
+		Granularity: services.HistoryGranularityDay,
+	})
+	require.NoError(t, err)
+	require.Len(t, overall, 1)
+	require.Equal(t, 15, overall[0].Level)
+	require.InDelta(t, 1_542, overall[0].Experience, 0)
+}
+
+func testPlayerSkillHourlyHistory(t *testing.T, storage services.StorageService) {
+	t.Helper()
+
+	player := createPlayer(t, storage, "zezima")
+	hour := day1.Add(10 * time.Hour)
+
+	recordSkills(t, storage, player, hour.Add(15*time.Minute), map[string]services.PlayerSkillRecord{
+		"Attack":   {Level: 10, Experience: 1_154},
+		"Strength": {Level: 5, Experience: 388},
+	})
+	recordSkills(t, storage, player, hour.Add(45*time.Minute), map[string]services.PlayerSkillRecord{
+		"Attack":   {Level: 11, Experience: 1_358},
+		"Strength": {Level: 5, Experience: 400},
+	})
+	recordSkills(t, storage, player, hour.Add(90*time.Minute), map[string]services.PlayerSkillRecord{
+		"Attack":   {Level: 12, Experience: 1_584},
+		"Strength": {Level: 6, Experience: 512},
+	})
+
+	history, err := storage.GetPlayerSkillHistory(t.Context(), services.GetPlayerSkillHistoryParams{
+		World:       world,
+		Username:    "zezima",
+		Skill:       "Attack",
+		From:        hour,
+		To:          hour.Add(2 * time.Hour),
+		Granularity: services.HistoryGranularityHour,
+	})
+	require.NoError(t, err)
+	require.Len(t, history, 2, "only the last snapshot of each hour is kept")
+	requireSameTime(t, hour, history[0].Date)
+	require.Equal(t, 11, history[0].Level)
+	requireSameTime(t, hour.Add(time.Hour), history[1].Date)
+	require.Equal(t, 12, history[1].Level)
+
+	overall, err := storage.GetPlayerSkillHistory(t.Context(), services.GetPlayerSkillHistoryParams{
+		World:       world,
+		Username:    "zezima",
+		Skill:       services.SkillOverall,
+		From:        hour,
+		To:          hour.Add(30 * time.Minute),
+		Granularity: services.HistoryGranularityHour,
+	})
+	require.NoError(t, err)
+	require.Len(t, overall, 1)
+	require.Equal(t, 15, overall[0].Level)
+	require.InDelta(t, 1_542, overall[0].Experience, 0)
+}
+
+func testSkillHighscoreOrdering(t *testing.T, storage services.StorageService) {
+	t.Helper()
+
+	experience := map[string]float64{
+		"alice": 500,
+		"bob":   1_000,
+		"carol": 1_000,
+		"dave":  200,
+	}
+	for username, value := range experience {
+		recordSkills(t, storage, createPlayer(t, storage, username), day1, map[string]services.PlayerSkillRecord{
+			"Attack": {Level: 5, Experience: value},
+		})
+	}
+
+	highscores, err := storage.GetHighscoresForSkill(t.Context(), services.GetHighscoresForSkillParams{
+		World:  world,
+		Skill:  "Attack",
+		Limit:  10,
+		Offset: 0,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []rankedPlayer{
+		{Rank: 1, Username: "bob"},
+		{Rank: 1, Username: "carol"},
+		{Rank: 3, Username: "alice"},
+		{Rank: 4, Username: "dave"},
+	}, rankedPlayers(highscores))
+
+	page, err := storage.GetHighscoresForSkill(t.Context(), services.GetHighscoresForSkillParams{
+		World:  world,
+		Skill:  "Attack",
+		Limit:  2,
+		Offset: 2,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []rankedPlayer{
+		{Rank: 3, Username: "alice"},
+		{Rank: 4, Username: "dave"},
+	}, rankedPlayers(page))
+
+	count, err := storage.CountHighscoresForSkill(t.Context(), services.CountHighscoresForSkillParams{
+		World: world,
+		Skill: "Attack",
+	})
+	require.NoError(t, err)
+	require.Equal(t, 4, count)
+
+	count, err = storage.CountHighscoresForSkill(t.Context(), services.CountHighscoresForSkillParams{
+		World: world,
+		Skill: "Magic",
+	})
+	require.NoError(t, err)
+	require.Zero(t, count)
+}
+
+func testHighscoresUseLatestSkills(t *testing.T, storage services.StorageService) {
+	t.Helper()
+
+	alice := createPlayer(t, storage, "alice")
+	bob := createPlayer(t, storage, "bob")
+
+	for index, day := range []time.Time{day1, day2, day3} {
+		recordSkills(t, storage, alice, day, map[string]services.PlayerSkillRecord{
+			"Attack": {Level: 10 + index, Experience: float64(1_000 * (index + 1))},
+		})
+	}
+
+	recordSkills(t, storage, bob, day1, map[string]services.PlayerSkillRecord{
+		"Attack": {Level: 11, Experience: 2_000},
+	})
+
+	highscores, err := storage.GetHighscoresForSkill(t.Context(), services.GetHighscoresForSkillParams{
+		World:  world,
+		Skill:  "Attack",
+		Limit:  10,
+		Offset: 0,
+	})
+	require.NoError(t, err)
+	require.Len(t, highscores, 2, "every player must appear exactly once")
+	require.Equal(t, "alice", highscores[0].Username)
+	require.Equal(t, alice.ID, highscores[0].PlayerID)
+	require.Equal(t, 12, highscores[0].Level)
+	require.InDelta(t, 3_000, highscores[0].Experience, 0)
+	require.Equal(t, "bob", highscores[1].Username)
+	require.Equal(t, 2, highscores[1].Rank)
+
+	count, err := storage.CountHighscoresForSkill(t.Context(), services.CountHighscoresForSkillParams{
+		World: world,
+		Skill: "Attack",
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+}
+
+func testOverallHighscoreOrdering(t *testing.T, storage services.StorageService) {
+	t.Helper()
+
+	// Total level beats total experience
+	recordSkills(t, storage, createPlayer(t, storage, "alice"), day1, map[string]services.PlayerSkillRecord{
+		"Attack":   {Level: 50, Experience: 101_333},
+		"Strength": {Level: 50, Experience: 101_333},
+	})
+	recordSkills(t, storage, createPlayer(t, storage, "bob"), day1, map[string]services.PlayerSkillRecord{
+		"Attack":   {Level: 99, Experience: 13_034_431},
+		"Strength": {Level: 1, Experience: 0},
+	})
+	recordSkills(t, storage, createPlayer(t, storage, "carol"), day1, map[string]services.PlayerSkillRecord{
+		"Attack":   {Level: 60, Experience: 273_742},
+		"Strength": {Level: 41, Experience: 37_224},
+	})
+
+	highscores, err := storage.GetHighscoresForSkill(t.Context(), services.GetHighscoresForSkillParams{
+		World:  world,
+		Skill:  services.SkillOverall,
+		Limit:  10,
+		Offset: 0,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []rankedPlayer{
+		{Rank: 1, Username: "carol"},
+		{Rank: 2, Username: "bob"},
+		{Rank: 3, Username: "alice"},
+	}, rankedPlayers(highscores))
+	require.Equal(t, 101, highscores[0].Level)
+	require.InDelta(t, 310_966, highscores[0].Experience, 0)
+
+	count, err := storage.CountHighscoresForSkill(t.Context(), services.CountHighscoresForSkillParams{
+		World: world,
+		Skill: services.SkillOverall,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, count)
+}
+
+func testCombatHighscoreOrdering(t *testing.T, storage services.StorageService) {
+	t.Helper()
+
+	recordSkills(t, storage, createPlayer(t, storage, "alice"), day1, map[string]services.PlayerSkillRecord{
+		"Attack":       {Level: 40, Experience: 37_224},
+		"Strength":     {Level: 40, Experience: 37_224},
+		"Constitution": {Level: 40, Experience: 37_224},
+		"Woodcutting":  {Level: 99, Experience: 13_034_431},
+	})
+	recordSkills(t, storage, createPlayer(t, storage, "bob"), day1, map[string]services.PlayerSkillRecord{
+		"Attack":       {Level: 60, Experience: 273_742},
+		"Strength":     {Level: 60, Experience: 273_742},
+		"Constitution": {Level: 60, Experience: 273_742},
+	})
+
+	highscores, err := storage.GetHighscoresForSkill(t.Context(), services.GetHighscoresForSkillParams{
+		World:  world,
+		Skill:  services.SkillCombat,
+		Limit:  10,
+		Offset: 0,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []rankedPlayer{
+		{Rank: 1, Username: "bob"},
+		{Rank: 2, Username: "alice"},
+	}, rankedPlayers(highscores))
+	require.Greater(t, highscores[0].Level, highscores[1].Level)
+	require.InDelta(t, 111_672, highscores[1].Experience, 0, "only combat skills count")
+
+	count, err := storage.CountHighscoresForSkill(t.Context(), services.CountHighscoresForSkillParams{
+		World: world,
+		Skill: services.SkillCombat,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+}
+
+func testPlayerGains(t *testing.T, storage services.StorageService) {
+	t.Helper()
+
+	player := createPlayer(t, storage, "zezima")
+
+	recordSkills(t, storage, player, day1, map[string]services.PlayerSkillRecord{
+		"Attack": {Level: 10, Experience: 1_154},
+	})
+	recordSkills(t, storage, player, day2, map[string]services.PlayerSkillRecord{
+		"Attack": {Level: 20, Experience: 4_470},
+	})
+	recordSkills(t, storage, player, day3, map[string]services.PlayerSkillRecord{
+		"Attack":   {Level: 30, Experience: 13_363},
+		"Strength": {Level: 5, Experience: 388},
+	})
+
+	gains, err := storage.GetPlayerGains(t.Context(), services.GetPlayerGainsParams{
+		World:    world,
+		Username: "zezima",
+		From:     day2,
+		To:       day3,
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]services.SkillGain{
+		"Attack": {StartLevel: 20, StartExperience: 4_470, EndLevel: 30, EndExperience: 13_363},
+		// First seen during the period so it starts from its first record
+		"Strength":            {StartLevel: 5, StartExperience: 388, EndLevel: 5, EndExperience: 388},
+		services.SkillOverall: {StartLevel: 25, StartExperience: 4_858, EndLevel: 35, EndExperience: 13_751},
+	}, gains)
+}
+
+func testTopGainers(t *testing.T, storage services.StorageService) {
+	t.Helper()
+
+	gained := map[string]float64{
+		"alice": 1_000,
+		"bob":   5_000,
+		"carol": 1_000,
+		"dave":  0,
+	}
+	for username, value := range gained {
+		player := createPlayer(t, storage, username)
+
+		recordSkills(t, storage, player, day1, map[string]services.PlayerSkillRecord{
+			"Attack": {Level: 10, Experience: 1_154},
+		})
+		recordSkills(t, storage, player, day2, map[string]services.PlayerSkillRecord{
+			"Attack": {Level: 10, Experience: 1_154 + value},
+		})
+	}
+
+	for _, skill := range []string{"Attack", services.SkillOverall} {
+		gainers, err := storage.GetTopGainers(t.Context(), services.GetTopGainersParams{
+			World:  world,
+			Skill:  skill,
+			From:   day1,
+			To:     day2,
+			Limit:  10,
+			Offset: 0,
+		})
+		require.NoError(t, err)
+
+		ranked := make([]rankedPlayer, len(gainers))
+		for index, gainer := range gainers {
+			ranked[index] = rankedPlayer{Rank: gainer.Rank, Username: gainer.Username}
+		}
+
+		require.Equal(t, []rankedPlayer{
+			{Rank: 1, Username: "bob"},
+			{Rank: 2, Username: "alice"},
+			{Rank: 2, Username: "carol"},
+		}, ranked, "players that didn't gain anything are left out")
+		require.InDelta(t, 5_000, gainers[0].Gain.ExperienceGained(), 0)
+	}
+}
+
+func testDeleteIntradaySnapshots(t *testing.T, storage services.StorageService) {
+	t.Helper()
+
+	player := createPlayer(t, storage, "zezima")
+	skills := map[string]services.PlayerSkillRecord{
+		"Attack":   {Level: 10, Experience: 1_154},
+		"Strength": {Level: 5, Experience: 388},
+	}
+
+	recordSkills(t, storage, player, day1.Add(time.Hour), skills)
+	recordSkills(t, storage, player, day2.Add(time.Hour), skills)
+
+	deleted, err := storage.DeleteIntradaySnapshotsBefore(t.Context(), day2)
+	require.NoError(t, err)
+	require.Equal(t, 2, deleted)
+
+	history, err := storage.GetPlayerSkillHistory(t.Context(), services.GetPlayerSkillHistoryParams{
+		World:       world,
+		Username:    "zezima",
+		Skill:       "Attack",
+		From:        day1,
+		To:          day3,
+		Granularity: services.HistoryGranularityHour,
+	})
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	requireSameTime(t, day2.Add(time.Hour), history[0].Date)
+
+	// Daily history isn't affected
+	history, err = storage.GetPlayerSkillHistory(t.Context(), services.GetPlayerSkillHistoryParams{
+		World:       world,
+		Username:    "zezima",
+		Skill:       "Attack",
+		From:        day1,
+		To:          day3,
+		Granularity: services.HistoryGranularityDay,
+	})
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+}
+
+func testPlayerSkillDays(t *testing.T, storage services.StorageService) {
+	t.Helper()
+
+	player := createPlayer(t, storage, "zezima")
+
+	for index, day := range []time.Time{day1, day2, day3} {
+		recordSkills(t, storage, player, day, map[string]services.PlayerSkillRecord{
+			"Attack":       {Level: 10 + index, Experience: float64(1_154 + index)},
+			"Constitution": {Level: 10, Experience: 1_154},
+		})
+	}
+
+	days, err := storage.GetPlayerSkillDays(t.Context(), player.ID)
+	require.NoError(t, err)
+	require.Len(t, days, 3)
+
+	for index, day := range []time.Time{day1, day2, day3} {
+		requireSameTime(t, day, days[index].Date)
+		require.Len(t, days[index].Skills, 2)
+		require.Equal(t, 10+index, days[index].Skills["Attack"].Level)
+	}
+
+	err = storage.DeletePlayerSkillDays(t.Context(), services.DeletePlayerSkillDaysParams{
+		PlayerID: player.ID,
+		Days:     []time.Time{day1, day2},
+	})
+	require.NoError(t, err)
+
+	days, err = storage.GetPlayerSkillDays(t.Context(), player.ID)
+	require.NoError(t, err)
+	require.Len(t, days, 1)
+	requireSameTime(t, day3, days[0].Date)
+
+	highscores, err := storage.GetHighscoresForSkill(t.Context(), services.GetHighscoresForSkillParams{
+		World:  world,
+		Skill:  services.SkillCombat,
+		Limit:  10,
+		Offset: 0,
+	})
+	require.NoError(t, err)
+	require.Len(t, highscores, 1, "combat levels are removed along with the days")
+}
+
+func testWorldsAreSeparate(t *testing.T, storage services.StorageService) {
+	t.Helper()
+
+	mainPlayer := createPlayer(t, storage, "zezima")
+	recordSkills(t, storage, mainPlayer, day1, map[string]services.PlayerSkillRecord{
+		"Attack": {Level: 10, Experience: 1_154},
+	})
+	recordSkills(t, storage, mainPlayer, day2, map[string]services.PlayerSkillRecord{
+		"Attack": {Level: 20, Experience: 4_470},
+	})
+
+	// The same name in another world is a different player
+	otherPlayer, err := storage.CreatePlayer(t.Context(), services.CreatePlayerParams{
+		World:     otherWorld,
+		Username:  "zezima",
+		CreatedOn: day1,
+	})
+	require.NoError(t, err)
+	require.NotEqual(t, mainPlayer.ID, otherPlayer.ID)
+	require.Equal(t, otherWorld, otherPlayer.World)
+
+	recordSkills(t, storage, otherPlayer, day2, map[string]services.PlayerSkillRecord{
+		"Attack": {Level: 30, Experience: 13_363},
+	})
+	recordSkills(t, storage, createPlayer(t, storage, "alice"), day2, map[string]services.PlayerSkillRecord{
+		"Attack": {Level: 40, Experience: 37_224},
+	})
+
+	found, err := storage.GetPlayerByUsername(t.Context(), services.GetPlayerByUsernameParams{
+		World:    otherWorld,
+		Username: "zezima",
+	})
+	require.NoError(t, err)
+	require.Equal(t, otherPlayer.ID, found.ID)
+
+	skills, err := storage.GetPlayerSkills(t.Context(), services.GetPlayerSkillsParams{
+		World:    otherWorld,
+		Username: "zezima",
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[string]services.PlayerSkillRecord{
+		"Attack": {Level: 30, Experience: 13_363},
+	}, skills)
+
+	for _, skill := range []string{"Attack", services.SkillOverall, services.SkillCombat} {
+		highscores, err := storage.GetHighscoresForSkill(t.Context(), services.GetHighscoresForSkillParams{
+			World:  otherWorld,
+			Skill:  skill,
+			Limit:  10,
+			Offset: 0,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []rankedPlayer{{Rank: 1, Username: "zezima"}}, rankedPlayers(highscores), skill)
+		require.Equal(t, otherPlayer.ID, highscores[0].PlayerID)
+
+		count, err := storage.CountHighscoresForSkill(t.Context(), services.CountHighscoresForSkillParams{
+			World: otherWorld,
+			Skill: skill,
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, count, skill)
+
+		count, err = storage.CountHighscoresForSkill(t.Context(), services.CountHighscoresForSkillParams{
+			World: world,
+			Skill: skill,
+		})
+		require.NoError(t, err)
+		require.Equal(t, 2, count, skill)
+	}
+
+	gains, err := storage.GetPlayerGains(t.Context(), services.GetPlayerGainsParams{
+		World:    world,
+		Username: "zezima",
+		From:     day1,
+		To:       day2,
+	})
+	require.NoError(t, err)
+	require.InDelta(t, 3_316, gains["Attack"].ExperienceGained(), 0)
+
+	gainers, err := storage.GetTopGainers(t.Context(), services.GetTopGainersParams{
+		World:  world,
+		Skill:  "Attack",
+		From:   day1,
+		To:     day2,
+		Limit:  10,
+		Offset: 0,
+	})
+	require.NoError(t, err)
+	require.Len(t, gainers, 1)
+	require.Equal(t, mainPlayer.ID, gainers[0].PlayerID)
+
+	history, err := storage.GetPlayerSkillHistory(t.Context(), services.GetPlayerSkillHistoryParams{
+		World:       otherWorld,
+		Username:    "zezima",
+		Skill:       "Attack",
+		From:        day1,
+		To:          day3,
+		Granularity: services.HistoryGranularityHour,
+	})
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	require.Equal(t, 30, history[0].Level)
+
+	// Save files with the same path in different worlds are tracked separately
+	for _, state := range []services.SaveFileState{
+		{World: world, Path: "zezima.toml", ModifiedOn: day1, Size: 1, Hash: "main"},
+		{World: otherWorld, Path: "zezima.toml", ModifiedOn: day2, Size: 2, Hash: "other"},
+	} {
+		require.NoError(t, storage.RecordSaveFileState(t.Context(), state))
+	}
+
+	states, err := storage.GetSaveFileStates(t.Context(), otherWorld)
+	require.NoError(t, err)
+	require.Len(t, states, 1)
+	require.Equal(t, "other", states["zezima.toml"].Hash)
+	require.Equal(t, otherWorld, states["zezima.toml"].World)
+
+	for _, saveWorld := range []string{world, otherWorld} {
+		require.NoError(t, storage.QuarantineSaveFile(t.Context(), services.QuarantineSaveFileParams{
+			World:  saveWorld,
+			Path:   "alice.toml",
+			Hash:   saveWorld,
+			Error:  "bad",
+			SeenOn: day2,
+		}))
+	}
+
+	require.NoError(t, storage.ReleaseSaveFile(t.Context(), services.ReleaseSaveFileParams{
+		World: world,
+		Path:  "alice.toml",
+	}))
+
+	files, err := storage.GetQuarantinedSaveFiles(t.Context())
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Equal(t, otherWorld, files[0].World)
+	require.Equal(t, "alice.toml", files[0].Path)
+}
+
+func testSaveFileStates(t *testing.T, storage services.StorageService) {
+	t.Helper()
+
+	state := services.SaveFileState{
+		World:      world,
+		Path:       "players/zezima.toml",
+		ModifiedOn: time.Date(2026, time.January, 2, 3, 4, 5, 0, time.UTC),
+		Size:       1_024,
+		Hash:       "first",
+	}
+	require.NoError(t, storage.RecordSaveFileState(t.Context(), state))
+
+	state.Hash = "second"
+	state.Size = 2_048
+	require.NoError(t, storage.RecordSaveFileState(t.Context(), state))
+
+	states, err := storage.GetSaveFileStates(t.Context(), world)
+	require.NoError(t, err)
+	require.Len(t, states, 1)
+
+	saved := states[state.Path]
+	require.Equal(t, state.Path, saved.Path)
+	require.Equal(t, state.Hash, saved.Hash)
+	require.Equal(t, state.Size, saved.Size)
+	requireSameTime(t, state.ModifiedOn, saved.ModifiedOn)
+}
+
+func testQuarantinedSaveFiles(t *testing.T, storage services.StorageService) {
+	t.Helper()
+
+	firstSeenOn := time.Date(2026, time.January, 2, 3, 4, 5, 0, time.UTC)
+	lastSeenOn := firstSeenOn.Add(time.Hour)
+
+	for _, params := range []services.QuarantineSaveFileParams{
+		{World: world, Path: "b.toml", Hash: "one", Error: "bad", SeenOn: firstSeenOn},
+		{World: world, Path: "a.toml", Hash: "two", Error: "bad", SeenOn: firstSeenOn},
+		{World: world, Path: "b.toml", Hash: "three", Error: "worse", SeenOn: lastSeenOn},
+	} {
+		require.NoError(t, storage.QuarantineSaveFile(t.Context(), params))
+	}
+
+	files, err := storage.GetQuarantinedSaveFiles(t.Context())
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+	require.Equal(t, "a.toml", files[0].Path)
+	require.Equal(t, "b.toml", files[1].Path)
+	require.Equal(t, "three", files[1].Hash)
+	require.Equal(t, "worse", files[1].Error)
+	requireSameTime(t, firstSeenOn, files[1].FirstSeenOn)
+	requireSameTime(t, lastSeenOn, files[1].LastSeenOn)
+
+	require.NoError(t, storage.ReleaseSaveFile(t.Context(), services.ReleaseSaveFileParams{
+		World: world,
+		Path:  "a.toml",
+	}))
+
+	// Reading a save successfully releases it too
+	require.NoError(t, storage.RecordSaveFileState(t.Context(), services.SaveFileState{
+		World:      world,
+		Path:       "b.toml",
+		ModifiedOn: lastSeenOn,
+		Size:       1,
+		Hash:       "four",
+	}))
+
+	files, err = storage.GetQuarantinedSaveFiles(t.Context())
+	require.NoError(t, err)
+	require.Empty(t, files)
+}
+
+func testIngestionRuns(t *testing.T, storage services.StorageService) {
+	t.Helper()
+
+	startedOn := time.Date(2026, time.January, 2, 3, 0, 0, 0, time.UTC)
+	runs := []services.FinishIngestionRunParams{
+		{
+			ID:             "old",
+			FinishedOn:     startedOn.Add(time.Minute),
+			FilesScanned:   1,
+			PlayersUpdated: 1,
+			Error:          "",
+			Failures:       nil,
+		},
+		{
+			ID:             "succeeded",
+			FinishedOn:     startedOn.Add(time.Hour + time.Minute),
+			FilesScanned:   3,
+			PlayersUpdated: 1,
+			Error:          "",
+			Failures: []services.IngestionFailure{
+				{File: "b.toml", Error: "bad"},
+				{File: "a.toml", Error: "bad"},
+			},
+		},
+		{
+			ID:             "failed",
+			FinishedOn:     startedOn.Add(2*time.Hour + time.Minute),
+			FilesScanned:   0,
+			PlayersUpdated: 0,
+			Error:          "data directory is missing",
+			Failures:       nil,
+		},
+	}
+
+	for index, run := range runs {
+		err := storage.StartIngestionRun(t.Context(), services.StartIngestionRunParams{
+			ID:        run.ID,
+			World:     world,
+			StartedOn: startedOn.Add(time.Duration(index) * time.Hour),
+		})
+		require.NoError(t, err)
+		require.NoError(t, storage.FinishIngestionRun(t.Context(), run))
+	}
+
+	err := storage.StartIngestionRun(t.Context(), services.StartIngestionRunParams{
+		ID:        "running",
+		World:     otherWorld,
+		StartedOn: startedOn.Add(3 * time.Hour),
+	})
+	require.NoError(t, err)
+
+	recent, err := storage.GetRecentIngestionRuns(t.Context(), 3)
+	require.NoError(t, err)
+	require.Len(t, recent, 3)
+	require.Equal(t, "running", recent[0].ID)
+	require.Equal(t, otherWorld, recent[0].World)
+	require.False(t, recent[0].Finished())
+	require.Equal(t, "failed", recent[1].ID)
+	require.Equal(t, world, recent[1].World)
+	require.False(t, recent[1].Succeeded())
+	require.Equal(t, "data directory is missing", recent[1].Error)
+	require.Equal(t, "succeeded", recent[2].ID)
+
+	last, err := storage.GetLastSuccessfulIngestionRun(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, "succeeded", last.ID)
+	require.Equal(t, 3, last.FilesScanned)
+	require.Equal(t, time.Minute, last.Duration())
+	require.Equal(t, []services.IngestionFailure{
+		{File: "a.toml", Error: "bad"},
+		{File: "b.toml", Error: "bad"},
+	}, last.Failures)
+
+	require.NoError(t, storage.DeleteIngestionRunsBefore(t.Context(), startedOn.Add(time.Hour)))
+
+	recent, err = storage.GetRecentIngestionRuns(t.Context(), 10)
+	require.NoError(t, err)
+	require.Len(t, recent, 3)
+	require.Equal(t, "succeeded", recent[2].ID)
+}
+
+func testLastSuccessfulIngestionRunMissing(t *testing.T, storage services.StorageService) {
+	t.Helper()
+
+	_, err := storage.GetLastSuccessfulIngestionRun(t.Context())
+	require.ErrorIs(t, err, services.ErrIngestionRunNotFound)
+	require.ErrorIs(t, err, services.ErrNotFound)
+}
+
+type rankedPlayer struct {
+	Rank     int
+	Username string
+}
+
+func rankedPlayers(highscores []services.HighscoreSkillRecord) []rankedPlayer {
+	ranked := make([]rankedPlayer, len(highscores))
+	for index, highscore := range highscores {
+		ranked[index] = rankedPlayer{Rank: highscore.Rank, Username: highscore.Username}
+	}
+
+	return ranked
+}
+
+func usernames(players []services.Player) []string {
+	names := make([]string, len(players))
+	for index, player := range players {
+		names[index] = player.Username
+	}
+
+	return names
+}
+
+func createPlayer(t *testing.T, storage services.StorageService, username string) services.Player {
+	t.Helper()
+
+	player, err := storage.CreatePlayer(t.Context(), services.CreatePlayerParams{
+		World:     world,
+		Username:  username,
+		CreatedOn: day1,
+	})
+	require.NoError(t, err)
+
+	return player
+}
+
+func recordSkills(
+	t *testing.T,
+	storage services.StorageService,
+	player services.Player,
+	date time.Time,
+	skills map[string]services.PlayerSkillRecord,
+) {
+	t.Helper()
+
+	err := storage.RecordPlayerSkills(t.Context(), services.RecordPlayerSkillsParams{
+		PlayerID: player.ID,
+		Skills:   skills,
+		Date:     date,
+	})
+	require.NoError(t, err)
+}
+
+// requireSameTime compares instants since backends hand times back in different locations.
+func requireSameTime(t *testing.T, expected, actual time.Time) {
+	t.Helper()
+
+	require.Truef(t, expected.Equal(actual), "expected %s, got %s", expected, actual)
+}
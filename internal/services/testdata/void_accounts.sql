@@ -0,0 +1,16 @@
+-- Accounts as Void's database storage saves them, see database/void/schema.sql
+
+INSERT INTO accounts (id, name) VALUES
+    (1, 'Zezima'),
+    (2, 'durial321');
+
+INSERT INTO experience (player_id, attack, defence, strength, constitution, ranged, prayer, magic, cooking, woodcutting, fletching, fishing, firemaking, crafting, smithing, mining, herblore, agility, thieving, slayer, farming, runecrafting, hunter, construction, summoning, dungeoneering) VALUES
+    (1, 130344310.0, 0.0, 0.0, 11540.0, 0.0, 0.0, 0.0, 0.0, 11540.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0),
+    (2, 0.0, 0.0, 0.0, 11540.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 3880.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0, 0.0);
+
+INSERT INTO levels (player_id, attack, defence, strength, constitution, ranged, prayer, magic, cooking, woodcutting, fletching, fishing, firemaking, crafting, smithing, mining, herblore, agility, thieving, slayer, farming, runecrafting, hunter, construction, summoning, dungeoneering) VALUES
+    (1, 99, 1, 1, 10, 1, 1, 1, 1, 10, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1),
+    (2, 1, 1, 1, 10, 1, 1, 1, 1, 1, 1, 5, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1);
+
+INSERT INTO variables (player_id, name, long_value) VALUES
+    (1, 'creation', 1767225600000);
@@ -0,0 +1,21 @@
+package services
+
+import (
+	"strings"
+)
+
+// NormalizeUsername is the form usernames are compared in. Like in RuneScape, case doesn't
+// matter and spaces, underscores and hyphens are the same character, so "Iron Man", "iron_man"
+// and "IRON-MAN" are all the same player. The migration that added normalized usernames does
+// the same thing in SQL so the two have to be kept in sync.
+func NormalizeUsername(username string) string {
+	return strings.Map(func(char rune) rune {
+		switch char {
+		// RuneScape displays spaces in names as non-breaking spaces
+		case ' ', '-', '\u00a0':
+			return '_'
+		default:
+			return char
+		}
+	}, strings.ToLower(strings.TrimSpace(username)))
+}
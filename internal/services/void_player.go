@@ -2,11 +2,62 @@ package services
 
 import (
 	"context"
+	"fmt"
 	"time"
 )
 
 type VoidPlayerService interface {
-	GetAllPlayers(ctx context.Context) ([]VoidPlayer, error)
+	GetAllPlayers(ctx context.Context, params GetAllPlayersParams) (GetAllPlayersResult, error)
+	GetPlayer(ctx context.Context, accountName string) (VoidPlayer, error)
+}
+
+// VoidWorld is a single game world, like the main world or a seasonal one, and where its
+// players are read from.
+type VoidWorld struct {
+	Name    string
+	Players VoidPlayerService
+
+	// DataDir is the directory save files are read from. It's empty for worlds that are read
+	// from Void's database.
+	DataDir string
+}
+
+type GetAllPlayersParams struct {
+	// Unchanged lets saves that have already been ingested be skipped without decoding them. It's
+	// called with the file's modification time and size and, if it returns false, again once the
+	// contents have been hashed.
+	Unchanged func(source PlayerSource) bool
+}
+
+type GetAllPlayersResult struct {
+	Players []VoidPlayer
+
+	// Failures are the saves that couldn't be read. A bad save doesn't stop the rest from being
+	// read.
+	Failures []PlayerSourceError
+}
+
+// PlayerSourceError is the reason a single save couldn't be read. The source's hash is only set
+// if the save's contents could be read.
+type PlayerSourceError struct {
+	Source PlayerSource
+	Err    error
+}
+
+func (err PlayerSourceError) Error() string {
+	return fmt.Sprintf("%s: %s", err.Source.Path, err.Err)
+}
+
+func (err PlayerSourceError) Unwrap() error {
+	return err.Err
+}
+
+// PlayerSource identifies where a player was read from and the version of it that was read.
+type PlayerSource struct {
+	Path       string
+	ModifiedOn time.Time
+	Size       int64
+	Hash       string
 }
 
 type VoidPlayer struct {
@@ -14,4 +65,34 @@ type VoidPlayer struct {
 	Experience  map[string]float64
 	Levels      map[string]int
 	CreatedOn   time.Time
+	Inventory   []VoidItem
+	Equipment   []VoidItem
+	Bank        []VoidItem
+	Source      PlayerSource
+}
+
+// VoidItem is an item in one of a player's containers. Empty slots are left out so Slot keeps
+// track of where the item is.
+type VoidItem struct {
+	Slot   int
+	ID     string
+	Amount int
+}
+
+const coinsItemID = "coins"
+
+// CoinValue is the number of coins the player is holding across their inventory, equipment and
+// bank.
+func (player VoidPlayer) CoinValue() int {
+	total := 0
+
+	for _, container := range [][]VoidItem{player.Inventory, player.Equipment, player.Bank} {
+		for _, item := range container {
+			if item.ID == coinsItemID {
+				total += item.Amount
+			}
+		}
+	}
+
+	return total
 }
@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/cadyyan/void-tool/internal/database/voiddb"
+	"github.com/jackc/pgx/v5"
+)
+
+// VoidPlayerDatabaseService reads players from the Void game server's database storage instead
+// of save files. See database/void/schema.sql for the tables it expects.
+type VoidPlayerDatabaseService struct {
+	queries *voiddb.Queries
+}
+
+var _ VoidPlayerService = (*VoidPlayerDatabaseService)(nil)
+
+func NewVoidPlayerDatabaseService(queries *voiddb.Queries) *VoidPlayerDatabaseService {
+	return &VoidPlayerDatabaseService{
+		queries: queries,
+	}
+}
+
+// GetAllPlayers reads every account. Rows don't have modification times so accounts are only
+// skipped as unchanged when the hash of their skills matches.
+func (service *VoidPlayerDatabaseService) GetAllPlayers(
+	ctx context.Context,
+	params GetAllPlayersParams,
+) (GetAllPlayersResult, error) {
+	records, err := service.queries.GetAllAccounts(ctx)
+	if err != nil {
+		return GetAllPlayersResult{}, fmt.Errorf("unable to get accounts from the Void database: %w", err)
+	}
+
+	result := GetAllPlayersResult{
+		Players:  make([]VoidPlayer, 0, len(records)),
+		Failures: nil,
+	}
+
+	for _, record := range records {
+		source := PlayerSource{
+			Path:       accountSourcePath(record.Name),
+			ModifiedOn: time.Time{},
+			Size:       0,
+			Hash:       "",
+		}
+
+		if params.Unchanged != nil && params.Unchanged(source) {
+			continue
+		}
+
+		player := voidPlayerFromAccount(record.Name, record.Experience, record.Level, record.Creation)
+
+		source.Hash = hashAccount(player)
+		if params.Unchanged != nil && params.Unchanged(source) {
+			continue
+		}
+
+		player.Source = source
+
+		result.Players = append(result.Players, player)
+	}
+
+	return result, nil
+}
+
+// GetPlayer reads a single account. Account names are matched without regard to case.
+func (service *VoidPlayerDatabaseService) GetPlayer(
+	ctx context.Context,
+	accountName string,
+) (VoidPlayer, error) {
+	record, err := service.queries.GetAccountByName(ctx, accountName)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return VoidPlayer{}, fmt.Errorf("%w: %s", ErrPlayerSaveNotFound, accountName)
+	} else if err != nil {
+		return VoidPlayer{}, fmt.Errorf("unable to get account from the Void database: %w", err)
+	}
+
+	player := voidPlayerFromAccount(record.Name, record.Experience, record.Level, record.Creation)
+	player.Source = PlayerSource{
+		Path:       accountSourcePath(record.Name),
+		ModifiedOn: time.Time{},
+		Size:       0,
+		Hash:       hashAccount(player),
+	}
+
+	return player, nil
+}
+
+// accountSourcePath identifies an account in places that expect a save file path, like the list
+// of quarantined saves.
+func accountSourcePath(accountName string) string {
+	return "accounts/" + accountName
+}
+
+func voidPlayerFromAccount(
+	accountName string,
+	savedExperience voiddb.Experience,
+	savedLevels voiddb.Level,
+	creation int64,
+) VoidPlayer {
+	experience, levels := newVoidPlayerSkills(
+		[]float64{
+			savedExperience.Attack,
+			savedExperience.Defence,
+			savedExperience.Strength,
+			savedExperience.Constitution,
+			savedExperience.Ranged,
+			savedExperience.Prayer,
+			savedExperience.Magic,
+			savedExperience.Cooking,
+			savedExperience.Woodcutting,
+			savedExperience.Fletching,
+			savedExperience.Fishing,
+			savedExperience.Firemaking,
+			savedExperience.Crafting,
+			savedExperience.Smithing,
+			savedExperience.Mining,
+			savedExperience.Herblore,
+			savedExperience.Agility,
+			savedExperience.Thieving,
+			savedExperience.Slayer,
+			savedExperience.Farming,
+			savedExperience.Runecrafting,
+			savedExperience.Hunter,
+			savedExperience.Construction,
+			savedExperience.Summoning,
+			savedExperience.Dungeoneering,
+		},
+		[]int{
+			int(savedLevels.Attack),
+			int(savedLevels.Defence),
+			int(savedLevels.Strength),
+			int(savedLevels.Constitution),
+			int(savedLevels.Ranged),
+			int(savedLevels.Prayer),
+			int(savedLevels.Magic),
+			int(savedLevels.Cooking),
+			int(savedLevels.Woodcutting),
+			int(savedLevels.Fletching),
+			int(savedLevels.Fishing),
+			int(savedLevels.Firemaking),
+			int(savedLevels.Crafting),
+			int(savedLevels.Smithing),
+			int(savedLevels.Mining),
+			int(savedLevels.Herblore),
+			int(savedLevels.Agility),
+			int(savedLevels.Thieving),
+			int(savedLevels.Slayer),
+			int(savedLevels.Farming),
+			int(savedLevels.Runecrafting),
+			int(savedLevels.Hunter),
+			int(savedLevels.Construction),
+			int(savedLevels.Summoning),
+			int(savedLevels.Dungeoneering),
+		},
+	)
+
+	// Items aren't read from the database yet
+	return VoidPlayer{
+		AccountName: accountName,
+		Experience:  experience,
+		Levels:      levels,
+		CreatedOn:   time.UnixMilli(creation),
+		Inventory:   nil,
+		Equipment:   nil,
+		Bank:        nil,
+		Source: PlayerSource{
+			Path:       "",
+			ModifiedOn: time.Time{},
+			Size:       0,
+			Hash:       "",
+		},
+	}
+}
+
+// hashAccount hashes everything that's read from an account so unchanged accounts can be
+// skipped.
+func hashAccount(player VoidPlayer) string {
+	hash := sha256.New()
+
+	hash.Write([]byte(player.AccountName))
+	hash.Write([]byte(strconv.FormatInt(player.CreatedOn.UnixMilli(), 10)))
+
+	for _, skill := range SkillOrder {
+		fmt.Fprintf(hash, "|%s:%d:%g", skill, player.Levels[skill], player.Experience[skill])
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
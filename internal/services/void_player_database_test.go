@@ -0,0 +1,106 @@
+package services_test
+
+import (
+	_ "embed"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/cadyyan/void-tool/database/void"
+	"github.com/cadyyan/void-tool/internal/database/voiddb"
+	"github.com/cadyyan/void-tool/internal/services"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+)
+
+//go:embed testdata/void_accounts.sql
+var voidAccountsFixture string
+
+// newVoidPlayerDatabaseService seeds a schema of its own in the database at
+// VOID_TEST_POSTGRES_URL so it doesn't clash with void-tool's tables.
+func newVoidPlayerDatabaseService(t *testing.T) *services.VoidPlayerDatabaseService {
+	t.Helper()
+
+	url := os.Getenv("VOID_TEST_POSTGRES_URL")
+	if url == "" {
+		t.Skip("VOID_TEST_POSTGRES_URL is not set")
+	}
+
+	config, err := pgxpool.ParseConfig(url)
+	require.NoError(t, err)
+
+	config.ConnConfig.RuntimeParams["search_path"] = "void_fixtures"
+
+	pool, err := pgxpool.NewWithConfig(t.Context(), config)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+
+	_, err = pool.Exec(t.Context(), "DROP SCHEMA IF EXISTS void_fixtures CASCADE; CREATE SCHEMA void_fixtures")
+	require.NoError(t, err)
+
+	_, err = pool.Exec(t.Context(), void.Schema)
+	require.NoError(t, err)
+
+	_, err = pool.Exec(t.Context(), voidAccountsFixture)
+	require.NoError(t, err)
+
+	return services.NewVoidPlayerDatabaseService(voiddb.New(pool))
+}
+
+func TestVoidPlayerDatabaseServiceGetAllPlayers(t *testing.T) {
+	service := newVoidPlayerDatabaseService(t)
+
+	result, err := service.GetAllPlayers(t.Context(), services.GetAllPlayersParams{Unchanged: nil})
+	require.NoError(t, err)
+	require.Empty(t, result.Failures)
+	require.Len(t, result.Players, 2)
+
+	zezima := result.Players[0]
+	require.Equal(t, "Zezima", zezima.AccountName)
+	require.InDelta(t, 13_034_431, zezima.Experience["Attack"], 0)
+	require.Equal(t, 99, zezima.Levels["Attack"])
+	require.Equal(t, 10, zezima.Levels["Woodcutting"])
+	require.Equal(t, 10, zezima.Levels["Constitution"])
+	require.Len(t, zezima.Levels, 25)
+	require.True(t, time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC).Equal(zezima.CreatedOn))
+	require.Equal(t, "accounts/Zezima", zezima.Source.Path)
+	require.NotEmpty(t, zezima.Source.Hash)
+
+	durial := result.Players[1]
+	require.Equal(t, "durial321", durial.AccountName)
+	require.Equal(t, 5, durial.Levels["Fishing"])
+	require.Zero(t, durial.CreatedOn.UnixMilli(), "accounts without a creation time start at the epoch")
+}
+
+func TestVoidPlayerDatabaseServiceSkipsUnchangedAccounts(t *testing.T) {
+	service := newVoidPlayerDatabaseService(t)
+
+	result, err := service.GetAllPlayers(t.Context(), services.GetAllPlayersParams{Unchanged: nil})
+	require.NoError(t, err)
+
+	hashes := make(map[string]string)
+	for _, player := range result.Players {
+		hashes[player.Source.Path] = player.Source.Hash
+	}
+
+	result, err = service.GetAllPlayers(t.Context(), services.GetAllPlayersParams{
+		Unchanged: func(source services.PlayerSource) bool {
+			return source.Path == "accounts/Zezima" && source.Hash == hashes[source.Path]
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Players, 1)
+	require.Equal(t, "durial321", result.Players[0].AccountName)
+}
+
+func TestVoidPlayerDatabaseServiceGetPlayer(t *testing.T) {
+	service := newVoidPlayerDatabaseService(t)
+
+	player, err := service.GetPlayer(t.Context(), "zezima")
+	require.NoError(t, err)
+	require.Equal(t, "Zezima", player.AccountName)
+	require.Equal(t, 99, player.Levels["Attack"])
+
+	_, err = service.GetPlayer(t.Context(), "nobody")
+	require.ErrorIs(t, err, services.ErrPlayerSaveNotFound)
+}
@@ -2,20 +2,42 @@ package services
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io/fs"
+	"path"
+	"strings"
 	"time"
 
 	"github.com/BurntSushi/toml"
+	"github.com/cadyyan/void-tool/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type VoidPlayerFileService struct {
-	fs fs.FS
+	fs      fs.FS
+	pattern string
 }
 
+// DefaultSaveFilePattern matches every save in a data directory.
+const DefaultSaveFilePattern = "*.toml"
+
 func NewVoidPlayerFileService(fileSystem fs.FS) *VoidPlayerFileService {
+	return NewVoidPlayerFileServiceWithPattern(fileSystem, DefaultSaveFilePattern)
+}
+
+// NewVoidPlayerFileServiceWithPattern only reads the saves matching the glob pattern when getting
+// all players.
+func NewVoidPlayerFileServiceWithPattern(
+	fileSystem fs.FS,
+	pattern string,
+) *VoidPlayerFileService {
 	return &VoidPlayerFileService{
-		fs: fileSystem,
+		fs:      fileSystem,
+		pattern: pattern,
 	}
 }
 
@@ -23,49 +45,206 @@ var _ VoidPlayerService = (*VoidPlayerFileService)(nil)
 
 func (service *VoidPlayerFileService) GetAllPlayers(
 	ctx context.Context,
-) ([]VoidPlayer, error) {
-	playerFiles, err := fs.Glob(service.fs, "*.toml")
+	params GetAllPlayersParams,
+) (GetAllPlayersResult, error) {
+	playerFiles, err := fs.Glob(service.fs, service.pattern)
 	if err != nil {
-		return nil, fmt.Errorf("unable to find player files: %w", err)
+		return GetAllPlayersResult{}, fmt.Errorf("unable to find player files: %w", err)
 	}
 
-	players := make([]VoidPlayer, len(playerFiles))
-	for index, playerFile := range playerFiles {
-		save, err := service.parsePlayerFile(service.fs, playerFile)
+	result := GetAllPlayersResult{
+		Players:  make([]VoidPlayer, 0, len(playerFiles)),
+		Failures: nil,
+	}
+
+	for _, playerFile := range playerFiles {
+		source := PlayerSource{
+			Path:       playerFile,
+			ModifiedOn: time.Time{},
+			Size:       0,
+			Hash:       "",
+		}
+
+		info, err := fs.Stat(service.fs, playerFile)
+		if err != nil {
+			result.Failures = append(result.Failures, PlayerSourceError{
+				Source: source,
+				Err:    fmt.Errorf("unable to read player save file: %w", err),
+			})
+
+			continue
+		}
+
+		source.ModifiedOn = info.ModTime()
+		source.Size = info.Size()
+
+		if params.Unchanged != nil && params.Unchanged(source) {
+			continue
+		}
+
+		contents, err := fs.ReadFile(service.fs, playerFile)
 		if err != nil {
-			return nil, fmt.Errorf("unable to get some player data: %w", err)
+			result.Failures = append(result.Failures, PlayerSourceError{
+				Source: source,
+				Err:    fmt.Errorf("unable to read player save file: %w", err),
+			})
+
+			continue
 		}
 
-		players[index] = save
+		source.Hash = hashSaveFile(contents)
+		if params.Unchanged != nil && params.Unchanged(source) {
+			continue
+		}
+
+		save, err := service.parsePlayerFile(ctx, source, contents)
+		if err != nil {
+			result.Failures = append(result.Failures, PlayerSourceError{
+				Source: source,
+				Err:    err,
+			})
+
+			continue
+		}
+
+		result.Players = append(result.Players, save)
 	}
 
-	return players, nil
+	return result, nil
 }
 
-func (service *VoidPlayerFileService) parsePlayerFile(
-	fileSystem fs.FS,
+// GetPlayerFromFile reads a single save file given its path relative to the data directory.
+func (service *VoidPlayerFileService) GetPlayerFromFile(
+	ctx context.Context,
 	filePath string,
 ) (VoidPlayer, error) {
+	if !fs.ValidPath(filePath) {
+		return VoidPlayer{}, fmt.Errorf("%w: %s", ErrPlayerSaveNotFound, filePath)
+	}
+
+	return service.readPlayerFile(ctx, filePath)
+}
+
+var (
+	ErrPlayerSaveNotFound = errors.New("player save file not found")
+	ErrInvalidPlayerSave  = errors.New("invalid player save file")
+)
+
+// GetPlayer reads a single player's save file. Saves are named after the account so this tries
+// the name as given and then in lowercase.
+func (service *VoidPlayerFileService) GetPlayer(
+	ctx context.Context,
+	accountName string,
+) (VoidPlayer, error) {
+	candidates := []string{
+		accountName + ".toml",
+		strings.ToLower(accountName) + ".toml",
+	}
+
+	for _, candidate := range candidates {
+		// Don't allow the account name to escape the data directory
+		if !fs.ValidPath(candidate) || path.Base(candidate) != candidate {
+			continue
+		}
+
+		_, err := fs.Stat(service.fs, candidate)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return VoidPlayer{}, fmt.Errorf("unable to find player save file: %w", err)
+		}
+
+		return service.readPlayerFile(ctx, candidate)
+	}
+
+	return VoidPlayer{}, fmt.Errorf("%w: %s", ErrPlayerSaveNotFound, accountName)
+}
+
+func (service *VoidPlayerFileService) readPlayerFile(
+	ctx context.Context,
+	filePath string,
+) (VoidPlayer, error) {
+	info, err := fs.Stat(service.fs, filePath)
+	if err != nil {
+		return VoidPlayer{}, fmt.Errorf("unable to read player save file: %w", err)
+	}
+
+	contents, err := fs.ReadFile(service.fs, filePath)
+	if err != nil {
+		return VoidPlayer{}, fmt.Errorf("unable to read player save file: %w", err)
+	}
+
+	source := PlayerSource{
+		Path:       filePath,
+		ModifiedOn: info.ModTime(),
+		Size:       info.Size(),
+		Hash:       hashSaveFile(contents),
+	}
+
+	player, err := service.parsePlayerFile(ctx, source, contents)
+	if err != nil {
+		return VoidPlayer{}, PlayerSourceError{Source: source, Err: err}
+	}
+
+	return player, nil
+}
+
+func hashSaveFile(contents []byte) string {
+	hash := sha256.Sum256(contents)
+
+	return hex.EncodeToString(hash[:])
+}
+
+func (service *VoidPlayerFileService) parsePlayerFile(
+	ctx context.Context,
+	source PlayerSource,
+	contents []byte,
+) (VoidPlayer, error) {
+	_, span := tracing.Start(
+		ctx,
+		"parse save file",
+		trace.WithAttributes(
+			attribute.String("save.path", source.Path),
+			attribute.Int64("save.size", source.Size),
+		),
+	)
+
+	player, err := decodePlayerFile(source, contents)
+	tracing.End(span, err)
+
+	return player, err
+}
+
+func decodePlayerFile(source PlayerSource, contents []byte) (VoidPlayer, error) {
 	var save PlayerSaveFileFormat
 
-	_, err := toml.DecodeFS(fileSystem, filePath, &save)
+	_, err := toml.Decode(string(contents), &save)
 	if err != nil {
 		return VoidPlayer{}, fmt.Errorf("unable to read player save file: %w", err)
 	}
 
-	experience := make(map[string]float64)
-	levels := make(map[string]int)
+	// Saves that were only partly written can still decode so make sure every skill is there
+	if len(save.Experience) < len(SkillOrder) || len(save.Levels) < len(SkillOrder) {
+		return VoidPlayer{}, fmt.Errorf(
+			"%w: expected %d skills but found %d experience values and %d levels",
+			ErrInvalidPlayerSave,
+			len(SkillOrder),
+			len(save.Experience),
+			len(save.Levels),
+		)
+	}
 
-	for index, skill := range skillOrder {
-		experience[skill] = float64(save.Experience[index]) / 10.0
+	if save.AccountName == "" {
+		return VoidPlayer{}, fmt.Errorf("%w: missing account name", ErrInvalidPlayerSave)
+	}
 
-		if skill == "Constitution" {
-			levels[skill] = calculateLevelFromExperience(experience[skill])
-		} else {
-			levels[skill] = save.Levels[index]
-		}
+	savedExperience := make([]float64, len(save.Experience))
+	for index, value := range save.Experience {
+		savedExperience[index] = float64(value)
 	}
 
+	experience, levels := newVoidPlayerSkills(savedExperience, save.Levels)
+
 	creationTime := time.UnixMilli(save.Variables.Creation)
 
 	return VoidPlayer{
@@ -73,20 +252,84 @@ func (service *VoidPlayerFileService) parsePlayerFile(
 		Experience:  experience,
 		Levels:      levels,
 		CreatedOn:   creationTime,
+		Inventory:   parseItems(save.Inventories.Inventory),
+		Equipment:   parseItems(save.Inventories.Equipment),
+		Bank:        parseItems(save.Inventories.Bank),
+		Source:      source,
 	}, nil
 }
 
+func parseItems(slots []PlayerSaveFileItemFormat) []VoidItem {
+	items := make([]VoidItem, 0, len(slots))
+
+	for slot, item := range slots {
+		if item.ID == "" {
+			continue
+		}
+
+		// Stackable items always save their amount but anything else only has an ID
+		amount := item.Amount
+		if amount == 0 {
+			amount = 1
+		}
+
+		items = append(items, VoidItem{
+			Slot:   slot,
+			ID:     item.ID,
+			Amount: amount,
+		})
+	}
+
+	return items
+}
+
 type PlayerSaveFileFormat struct {
-	AccountName string                        `toml:"accountName"`
-	Experience  []int                         `toml:"experience"`
-	Levels      []int                         `toml:"levels"`
-	Variables   PlayerSaveFileVariablesFormat `toml:"variables"`
+	AccountName string                          `toml:"accountName"`
+	Experience  []int                           `toml:"experience"`
+	Levels      []int                           `toml:"levels"`
+	Variables   PlayerSaveFileVariablesFormat   `toml:"variables"`
+	Inventories PlayerSaveFileInventoriesFormat `toml:"inventories"`
 }
 
 type PlayerSaveFileVariablesFormat struct {
 	Creation int64 `toml:"creation"`
 }
 
+type PlayerSaveFileInventoriesFormat struct {
+	Inventory []PlayerSaveFileItemFormat `toml:"inventory"`
+	Equipment []PlayerSaveFileItemFormat `toml:"worn_equipment"`
+	Bank      []PlayerSaveFileItemFormat `toml:"bank"`
+}
+
+// PlayerSaveFileItemFormat is a single slot in a container. Empty slots are saved as an empty
+// table.
+type PlayerSaveFileItemFormat struct {
+	ID     string `toml:"id"`
+	Amount int    `toml:"amount"`
+}
+
+// newVoidPlayerSkills converts the experience and levels Void saves, in SkillOrder, into maps
+// keyed by skill name. Void saves experience in tenths of a point.
+func newVoidPlayerSkills(
+	savedExperience []float64,
+	savedLevels []int,
+) (map[string]float64, map[string]int) {
+	experience := make(map[string]float64, len(SkillOrder))
+	levels := make(map[string]int, len(SkillOrder))
+
+	for index, skill := range SkillOrder {
+		experience[skill] = savedExperience[index] / 10.0
+
+		if skill == "Constitution" {
+			levels[skill] = calculateLevelFromExperience(experience[skill])
+		} else {
+			levels[skill] = savedLevels[index]
+		}
+	}
+
+	return experience, levels
+}
+
 func calculateLevelFromExperience(exp float64) int {
 	for index, requiredExp := range experienceTable {
 		if exp < requiredExp {
@@ -97,7 +340,8 @@ func calculateLevelFromExperience(exp float64) int {
 	return 99
 }
 
-var skillOrder = []string{
+// SkillOrder is every skill, in the order Void saves them and the game lists them.
+var SkillOrder = []string{
 	"Attack",
 	"Defence",
 	"Strength",
@@ -0,0 +1,43 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/cadyyan/void-tool"
+
+// Start starts a span as a child of the span in ctx, if there is one. Spans are sent to the
+// global tracer provider so nothing is recorded until tracing is set up.
+func Start(
+	ctx context.Context,
+	name string,
+	options ...trace.SpanStartOption,
+) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, options...)
+}
+
+// End ends a span, marking it as failed when err isn't nil. Errors that are expected, like
+// looking up something that doesn't exist, can be passed as expected so they're recorded
+// without failing the span.
+func End(span trace.Span, err error, expected ...error) {
+	defer span.End()
+
+	if err == nil {
+		return
+	}
+
+	span.RecordError(err)
+
+	for _, target := range expected {
+		if errors.Is(err, target) {
+			return
+		}
+	}
+
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,75 @@
+package web_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cadyyan/void-tool/internal/configuration"
+	"github.com/cadyyan/void-tool/internal/services"
+	"github.com/cadyyan/void-tool/internal/web"
+	"github.com/stretchr/testify/require"
+)
+
+var adminPaths = []string{"/admin/ingestion", "/api/admin/ingestion"}
+
+func newAdminTestServer(t *testing.T, password string) *httptest.Server {
+	t.Helper()
+
+	config, err := configuration.NewConfigurationFromEnv()
+	require.NoError(t, err)
+
+	config.Admin.Password = password
+
+	worlds := web.Worlds{{Name: apiWorld, Players: nil, DataDir: ""}}
+
+	server := httptest.NewServer(web.NewRouter(
+		config.Logging.BuildLogger(),
+		config,
+		services.NewStorageMemoryService(),
+		worlds,
+	))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func getAdmin(t *testing.T, server *httptest.Server, path string, username, password string) int {
+	t.Helper()
+
+	request, err := http.NewRequestWithContext(t.Context(), http.MethodGet, server.URL+path, nil)
+	require.NoError(t, err)
+
+	if username != "" {
+		request.SetBasicAuth(username, password)
+	}
+
+	response, err := server.Client().Do(request)
+	require.NoError(t, err)
+	require.NoError(t, response.Body.Close())
+
+	return response.StatusCode
+}
+
+func TestAdminRequiresPassword(t *testing.T) {
+	t.Parallel()
+
+	server := newAdminTestServer(t, "hunter2")
+
+	for _, path := range adminPaths {
+		require.Equal(t, http.StatusUnauthorized, getAdmin(t, server, path, "", ""), path)
+		require.Equal(t, http.StatusUnauthorized, getAdmin(t, server, path, "admin", "wrong"), path)
+		require.Equal(t, http.StatusOK, getAdmin(t, server, path, "admin", "hunter2"), path)
+	}
+}
+
+func TestAdminDisabledWithoutPassword(t *testing.T) {
+	t.Parallel()
+
+	server := newAdminTestServer(t, "")
+
+	for _, path := range adminPaths {
+		require.Equal(t, http.StatusNotFound, getAdmin(t, server, path, "", ""), path)
+		require.Equal(t, http.StatusNotFound, getAdmin(t, server, path, "admin", ""), path)
+	}
+}
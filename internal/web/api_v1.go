@@ -0,0 +1,509 @@
+package web
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/cadyyan/void-tool/internal/logging"
+	"github.com/cadyyan/void-tool/internal/services"
+	"github.com/go-chi/chi/v5"
+)
+
+// apiV1Version is the version of the API described in its OpenAPI document. It only changes when
+// responses change in a way clients can see.
+const apiV1Version = "1.0.0"
+
+// apiOperation is a single endpoint of the versioned API. The routes and the OpenAPI document
+// are both built from the same operations.
+type apiOperation struct {
+	Method     string
+	Path       string
+	ID         string
+	Summary    string
+	Tag        string
+	Parameters []openAPIParameter
+
+	// Response is an example of what the operation responds with, only its type matters
+	Response any
+
+	Handler http.HandlerFunc
+}
+
+// writeAPIError responds with problem details, what the API responds with whenever a request
+// isn't successful.
+func writeAPIError(w http.ResponseWriter, status int, code string, message string) {
+	writeProblem(w, httpError{
+		Status:  status,
+		Code:    code,
+		Message: message,
+	})
+}
+
+// apiResponse wraps everything the API responds with so there's room for more than the data
+// later on.
+type apiResponse[T any] struct {
+	Data T `json:"data"`
+}
+
+// apiPage is a page of a list. The next page is fetched by passing NextCursor as the cursor
+// query parameter, it's null on the last page.
+type apiPage[T any] struct {
+	Data       []T     `json:"data"`
+	NextCursor *string `json:"nextCursor"`
+}
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// apiPageRequest is the page of a list a client asked for. Cursors are opaque to clients but
+// they're just offsets into the list.
+type apiPageRequest struct {
+	Limit  int
+	Offset int
+}
+
+const apiCursorPrefix = "offset:"
+
+func newAPIPageRequestFromRequest(r *http.Request) (apiPageRequest, error) {
+	query := r.URL.Query()
+
+	request := apiPageRequest{
+		Limit:  defaultPageSize,
+		Offset: 0,
+	}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			return apiPageRequest{}, fmt.Errorf("invalid limit: %s", value)
+		}
+
+		request.Limit = min(limit, maxPageSize)
+	}
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err != nil {
+			return apiPageRequest{}, errInvalidCursor
+		}
+
+		offset, ok := strings.CutPrefix(string(decoded), apiCursorPrefix)
+		if !ok {
+			return apiPageRequest{}, errInvalidCursor
+		}
+
+		request.Offset, err = strconv.Atoi(offset)
+		if err != nil || request.Offset < 0 {
+			return apiPageRequest{}, errInvalidCursor
+		}
+	}
+
+	return request, nil
+}
+
+// Fetch is how many items to fetch for the page. One more than the limit is fetched to find out
+// if there's another page.
+func (request apiPageRequest) Fetch() int {
+	return request.Limit + 1
+}
+
+func newAPIPage[T any](request apiPageRequest, items []T) apiPage[T] {
+	page := apiPage[T]{
+		Data:       items,
+		NextCursor: nil,
+	}
+
+	if len(items) > request.Limit {
+		page.Data = items[:request.Limit]
+
+		cursor := base64.RawURLEncoding.EncodeToString(
+			[]byte(apiCursorPrefix + strconv.Itoa(request.Offset+request.Limit)),
+		)
+		page.NextCursor = &cursor
+	}
+
+	return page
+}
+
+// writeAPIPageRequestError responds to a request for a page that couldn't be read.
+func writeAPIPageRequestError(w http.ResponseWriter, err error) {
+	if errors.Is(err, errInvalidCursor) {
+		writeAPIError(w, http.StatusBadRequest, errorCodeInvalidCursor, err.Error())
+
+		return
+	}
+
+	writeAPIError(w, http.StatusBadRequest, errorCodeBadRequest, err.Error())
+}
+
+// lookupAPIWorld finds the world an API request is for, responding with an error when it
+// doesn't exist.
+func lookupAPIWorld(w http.ResponseWriter, r *http.Request, worlds Worlds) (services.VoidWorld, bool) {
+	world, ok := worlds.lookup(r)
+	if !ok {
+		writeAPIError(w, http.StatusNotFound, errorCodeUnknownWorld, "unknown world")
+	}
+
+	return world, ok
+}
+
+// lookupAPIPlayer finds the player an API request is for. Unlike pages, the API doesn't redirect
+// players that have been renamed, they're found by their previous names too.
+func lookupAPIPlayer(
+	ctx context.Context,
+	storageService services.StorageService,
+	world services.VoidWorld,
+	username string,
+) (services.Player, error) {
+	params := services.GetPlayerByUsernameParams{
+		World:    world.Name,
+		Username: username,
+	}
+
+	player, err := storageService.GetPlayerByUsername(ctx, params)
+	if errors.Is(err, services.ErrPlayerNotFound) {
+		player, err = storageService.GetPlayerByPreviousUsername(ctx, params)
+	}
+
+	if err != nil {
+		return services.Player{}, fmt.Errorf("unable to get player: %w", err)
+	}
+
+	return player, nil
+}
+
+// writeAPIPlayerError responds to a request for a player that couldn't be found.
+func writeAPIPlayerError(
+	ctx context.Context,
+	logger *slog.Logger,
+	w http.ResponseWriter,
+	err error,
+) {
+	if errors.Is(err, services.ErrPlayerNotFound) {
+		writeAPIError(w, http.StatusNotFound, errorCodePlayerNotFound, "player not found")
+
+		return
+	}
+
+	logger.ErrorContext(ctx, "Unable to get player", logging.Err(err))
+	writeAPIError(w, http.StatusInternalServerError, errorCodeInternal, "unable to get player")
+}
+
+// apiV1Routes serves the versioned API and its OpenAPI document.
+func apiV1Routes(
+	router chi.Router,
+	logger *slog.Logger,
+	storageService services.StorageService,
+	worlds Worlds,
+) {
+	operations := apiV1Operations(logger, storageService, worlds)
+
+	for _, operation := range operations {
+		router.Method(operation.Method, operation.Path, operation.Handler)
+	}
+
+	document := newAPIV1Document(operations, worlds)
+
+	router.Get("/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		if err := writeJSON(w, http.StatusOK, document); err != nil {
+			logger.ErrorContext(r.Context(), "Unable to write OpenAPI document", logging.Err(err))
+		}
+	})
+
+	router.NotFound(func(w http.ResponseWriter, _ *http.Request) {
+		writeAPIError(w, http.StatusNotFound, errorCodeNotFound, "not found")
+	})
+	router.MethodNotAllowed(func(w http.ResponseWriter, _ *http.Request) {
+		writeProblem(w, errMethodNotAllowed)
+	})
+}
+
+// apiV1Operations are every endpoint of the versioned API.
+func apiV1Operations(
+	logger *slog.Logger,
+	storageService services.StorageService,
+	worlds Worlds,
+) []apiOperation {
+	playerIndexSorts := make([]string, len(services.PlayerIndexSorts))
+	for index, sort := range services.PlayerIndexSorts {
+		playerIndexSorts[index] = string(sort)
+	}
+
+	return []apiOperation{
+		{
+			Method:     http.MethodGet,
+			Path:       "/worlds",
+			ID:         "listWorlds",
+			Summary:    "Every world the site tracks",
+			Tag:        "worlds",
+			Parameters: nil,
+			Response:   apiResponse[[]apiWorld]{},
+			Handler:    HandlerAPIV1Worlds(logger, worlds),
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/players",
+			ID:      "listPlayers",
+			Summary: "Every player in a world with their latest totals",
+			Tag:     "players",
+			Parameters: []openAPIParameter{
+				{
+					Name:        "sort",
+					In:          "query",
+					Description: "The column to sort by, players with the same value are sorted by username",
+					Required:    false,
+					Schema: &openAPISchema{
+						Type:    "string",
+						Enum:    playerIndexSorts,
+						Default: string(services.PlayerIndexSortUsername),
+					},
+				},
+				{
+					Name:        "order",
+					In:          "query",
+					Description: "Descending by default, apart from usernames",
+					Required:    false,
+					Schema:      &openAPISchema{Type: "string", Enum: []string{"asc", "desc"}},
+				},
+				apiLimitParameter,
+				apiCursorParameter,
+			},
+			Response: apiPage[apiPlayerSummary]{},
+			Handler:  HandlerAPIV1Players(logger, storageService, worlds),
+		},
+		{
+			Method:     http.MethodGet,
+			Path:       "/players/{username}",
+			ID:         "getPlayer",
+			Summary:    "A player and the names they used to have",
+			Tag:        "players",
+			Parameters: []openAPIParameter{apiUsernameParameter},
+			Response:   apiResponse[apiPlayer]{},
+			Handler:    HandlerAPIV1Player(logger, storageService, worlds),
+		},
+		{
+			Method:     http.MethodGet,
+			Path:       "/players/{username}/skills",
+			ID:         "getPlayerSkills",
+			Summary:    "A player's latest skills",
+			Tag:        "players",
+			Parameters: []openAPIParameter{apiUsernameParameter},
+			Response:   apiResponse[apiPlayerSkills]{},
+			Handler:    HandlerAPIV1PlayerSkills(logger, storageService, worlds),
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/players/{username}/skills/{skill}/history",
+			ID:      "getPlayerSkillHistory",
+			Summary: "A player's skill over time",
+			Tag:     "players",
+			Parameters: []openAPIParameter{
+				apiUsernameParameter,
+				apiSkillParameter(highscoreSkills),
+				{
+					Name:        "granularity",
+					In:          "query",
+					Description: "How far apart snapshots are",
+					Required:    false,
+					Schema: &openAPISchema{
+						Type: "string",
+						Enum: []string{
+							string(services.HistoryGranularityDay),
+							string(services.HistoryGranularityHour),
+						},
+						Default: string(services.HistoryGranularityDay),
+					},
+				},
+				apiFromParameter,
+				apiToParameter,
+			},
+			Response: apiResponse[apiSkillHistory]{},
+			Handler:  HandlerAPIV1PlayerSkillHistory(logger, storageService, worlds),
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/players/{username}/gains",
+			ID:      "getPlayerGains",
+			Summary: "What a player gained in each skill over a period",
+			Tag:     "players",
+			Parameters: []openAPIParameter{
+				apiUsernameParameter,
+				apiPeriodParameter,
+				apiFromParameter,
+				apiToParameter,
+			},
+			Response: apiResponse[apiPlayerGains]{},
+			Handler:  HandlerAPIV1PlayerGains(logger, storageService, worlds),
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/highscores/{skill}",
+			ID:      "listHighscores",
+			Summary: "Players ranked by a skill",
+			Tag:     "highscores",
+			Parameters: []openAPIParameter{
+				apiSkillParameter(highscoreCategories),
+				apiLimitParameter,
+				apiCursorParameter,
+			},
+			Response: apiPage[apiHighscore]{},
+			Handler:  HandlerAPIV1Highscores(logger, storageService, worlds),
+		},
+		{
+			Method:  http.MethodGet,
+			Path:    "/gains/{skill}",
+			ID:      "listGains",
+			Summary: "Players ranked by what they gained in a skill over a period",
+			Tag:     "gains",
+			Parameters: []openAPIParameter{
+				apiSkillParameter(highscoreSkills),
+				apiPeriodParameter,
+				apiFromParameter,
+				apiToParameter,
+				apiLimitParameter,
+				apiCursorParameter,
+			},
+			Response: apiGainsPage{},
+			Handler:  HandlerAPIV1Gains(logger, storageService, worlds),
+		},
+	}
+}
+
+func newAPIV1Document(operations []apiOperation, worlds Worlds) openAPIDocument {
+	schemas := make(openAPISchemas)
+	paths := make(map[string]map[string]openAPIOperation)
+
+	errorResponse := openAPIResponse{
+		Description: "Something went wrong",
+		Content: map[string]openAPIMediaType{
+			"application/problem+json": {
+				Schema: schemas.schemaOf(reflect.TypeFor[problem]()),
+			},
+		},
+	}
+
+	// Problems are described from their type like everything else but their codes are only known
+	// here
+	schemas["Problem"].Properties["code"].Enum = errorCodes
+
+	worldNames := make([]string, len(worlds))
+	for index, world := range worlds {
+		worldNames[index] = world.Name
+	}
+
+	for _, operation := range operations {
+		if paths[operation.Path] == nil {
+			paths[operation.Path] = make(map[string]openAPIOperation)
+		}
+
+		parameters := append([]openAPIParameter{{
+			Name:        "world",
+			In:          "query",
+			Description: "The world to look in, the default world when it's missing",
+			Required:    false,
+			Schema:      &openAPISchema{Type: "string", Enum: worldNames, Default: worlds[0].Name},
+		}}, operation.Parameters...)
+
+		paths[operation.Path][strings.ToLower(operation.Method)] = openAPIOperation{
+			OperationID: operation.ID,
+			Summary:     operation.Summary,
+			Tags:        []string{operation.Tag},
+			Parameters:  parameters,
+			Responses: map[string]openAPIResponse{
+				strconv.Itoa(http.StatusOK): {
+					Description: operation.Summary,
+					Content: map[string]openAPIMediaType{
+						"application/json": {
+							Schema: schemas.schemaOf(reflect.TypeOf(operation.Response)),
+						},
+					},
+				},
+				"default": errorResponse,
+			},
+		}
+	}
+
+	return openAPIDocument{
+		OpenAPI: "3.1.0",
+		Info: openAPIInfo{
+			Title:       "Void Tool API",
+			Version:     apiV1Version,
+			Description: "Players, skills, highscores and gains tracked from Void game servers.",
+		},
+		Paths: paths,
+		Components: openAPIComponents{
+			Schemas: schemas,
+		},
+	}
+}
+
+// Parameters shared by several operations.
+var (
+	apiUsernameParameter = openAPIParameter{
+		Name:        "username",
+		In:          "path",
+		Description: "The player's username or one of their previous usernames",
+		Required:    true,
+		Schema:      &openAPISchema{Type: "string"},
+	}
+	apiLimitParameter = openAPIParameter{
+		Name:        "limit",
+		In:          "query",
+		Description: "The most items to return",
+		Required:    false,
+		Schema: &openAPISchema{
+			Type:    "integer",
+			Default: defaultPageSize,
+			Minimum: ptr(1),
+			Maximum: ptr(maxPageSize),
+		},
+	}
+	apiCursorParameter = openAPIParameter{
+		Name:        "cursor",
+		In:          "query",
+		Description: "The nextCursor of the previous page",
+		Required:    false,
+		Schema:      &openAPISchema{Type: "string"},
+	}
+	apiPeriodParameter = openAPIParameter{
+		Name:        "period",
+		In:          "query",
+		Description: "The period to compare over, ending today. Custom periods use from and to",
+		Required:    false,
+		Schema:      &openAPISchema{Type: "string", Enum: gainsPeriods, Default: gainsPeriodWeek},
+	}
+	apiFromParameter = openAPIParameter{
+		Name:        "from",
+		In:          "query",
+		Description: "The first day of the period",
+		Required:    false,
+		Schema:      &openAPISchema{Type: "string", Format: "date"},
+	}
+	apiToParameter = openAPIParameter{
+		Name:        "to",
+		In:          "query",
+		Description: "The last day of the period, today when it's missing",
+		Required:    false,
+		Schema:      &openAPISchema{Type: "string", Format: "date"},
+	}
+)
+
+func apiSkillParameter(names []string) openAPIParameter {
+	return openAPIParameter{
+		Name:        "skill",
+		In:          "path",
+		Description: "The name of the skill, in any case",
+		Required:    true,
+		Schema:      &openAPISchema{Type: "string", Enum: names},
+	}
+}
+
+func ptr[T any](value T) *T {
+	return &value
+}
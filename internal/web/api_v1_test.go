@@ -0,0 +1,277 @@
+package web_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cadyyan/void-tool/internal/configuration"
+	"github.com/cadyyan/void-tool/internal/services"
+	"github.com/cadyyan/void-tool/internal/web"
+	"github.com/stretchr/testify/require"
+)
+
+const apiWorld = "main"
+
+func newAPITestServer(t *testing.T, usernames ...string) *httptest.Server {
+	t.Helper()
+
+	config, err := configuration.NewConfigurationFromEnv()
+	require.NoError(t, err)
+
+	storage := services.NewStorageMemoryService()
+
+	for _, username := range usernames {
+		_, err := storage.CreatePlayer(t.Context(), services.CreatePlayerParams{
+			World:     apiWorld,
+			Username:  username,
+			CreatedOn: time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC),
+		})
+		require.NoError(t, err)
+	}
+
+	worlds := web.Worlds{{Name: apiWorld, Players: nil, DataDir: ""}}
+
+	server := httptest.NewServer(web.NewRouter(config.Logging.BuildLogger(), config, storage, worlds))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func getAPI(t *testing.T, server *httptest.Server, path string, body any) *http.Response {
+	t.Helper()
+
+	response, err := server.Client().Get(server.URL + path)
+	require.NoError(t, err)
+
+	defer response.Body.Close()
+
+	require.NoError(t, json.NewDecoder(response.Body).Decode(body), path)
+
+	return response
+}
+
+func TestAPIV1OpenAPIDocument(t *testing.T) {
+	t.Parallel()
+
+	server := newAPITestServer(t)
+
+	var document struct {
+		Paths map[string]map[string]struct {
+			OperationID string `json:"operationId"`
+			Responses   map[string]struct {
+				Content map[string]struct {
+					Schema map[string]any `json:"schema"`
+				} `json:"content"`
+			} `json:"responses"`
+		} `json:"paths"`
+		Components struct {
+			Schemas map[string]map[string]any `json:"schemas"`
+		} `json:"components"`
+	}
+
+	response := getAPI(t, server, "/api/v1/openapi.json", &document)
+	require.Equal(t, http.StatusOK, response.StatusCode)
+
+	var operationIDs []string
+
+	for path, operations := range document.Paths {
+		for method, operation := range operations {
+			name := method + " " + path
+			operationIDs = append(operationIDs, operation.OperationID)
+
+			require.NotEmpty(t, operation.Responses["200"].Content["application/json"].Schema, name)
+			require.NotEmpty(
+				t,
+				operation.Responses["default"].Content["application/problem+json"].Schema,
+				name,
+			)
+		}
+	}
+
+	slices.Sort(operationIDs)
+	require.Equal(
+		t,
+		[]string{
+			"getPlayer",
+			"getPlayerGains",
+			"getPlayerSkillHistory",
+			"getPlayerSkills",
+			"listGains",
+			"listHighscores",
+			"listPlayers",
+			"listWorlds",
+		},
+		operationIDs,
+	)
+
+	// Every reference, wherever it is in the document, has to point at a schema that exists
+	var raw any
+
+	getAPI(t, server, "/api/v1/openapi.json", &raw)
+
+	refs := collectRefs(raw, nil)
+	require.NotEmpty(t, refs)
+
+	for _, ref := range refs {
+		name, ok := strings.CutPrefix(ref, "#/components/schemas/")
+		require.True(t, ok, ref)
+		require.NotEmpty(t, document.Components.Schemas[name], ref)
+	}
+
+	problem := document.Components.Schemas["Problem"]
+	require.NotEmpty(t, problem)
+
+	code, ok := problem["properties"].(map[string]any)["code"].(map[string]any)
+	require.True(t, ok)
+	require.Contains(t, code["enum"], "unknown_highscore_category")
+}
+
+func collectRefs(value any, refs []string) []string {
+	switch value := value.(type) {
+	case map[string]any:
+		for key, child := range value {
+			if ref, ok := child.(string); ok && key == "$ref" {
+				refs = append(refs, ref)
+
+				continue
+			}
+
+			refs = collectRefs(child, refs)
+		}
+	case []any:
+		for _, child := range value {
+			refs = collectRefs(child, refs)
+		}
+	}
+
+	return refs
+}
+
+func TestAPIV1Pagination(t *testing.T) {
+	t.Parallel()
+
+	usernames := []string{"alice", "bob", "carol", "dave", "erin", "frank"}
+	server := newAPITestServer(t, usernames...)
+
+	for _, limit := range []int{1, 4, 6, 10} {
+		t.Run(fmt.Sprintf("limit %d", limit), func(t *testing.T) {
+			t.Parallel()
+
+			var (
+				found  []string
+				cursor *string
+				pages  int
+			)
+
+			for {
+				query := url.Values{"sort": {"username"}, "limit": {fmt.Sprint(limit)}}
+				if cursor != nil {
+					query.Set("cursor", *cursor)
+				}
+
+				var page struct {
+					Data []struct {
+						Username string `json:"username"`
+					} `json:"data"`
+					NextCursor *string `json:"nextCursor"`
+				}
+
+				response := getAPI(t, server, "/api/v1/players?"+query.Encode(), &page)
+				require.Equal(t, http.StatusOK, response.StatusCode)
+				require.LessOrEqual(t, len(page.Data), limit)
+
+				for _, player := range page.Data {
+					found = append(found, player.Username)
+				}
+
+				pages++
+				require.LessOrEqual(t, pages, len(usernames), "pages never ended")
+
+				if page.NextCursor == nil {
+					break
+				}
+
+				cursor = page.NextCursor
+			}
+
+			require.Equal(t, usernames, found)
+			require.Equal(t, (len(usernames)+limit-1)/limit, pages, "the last page has a next cursor")
+		})
+	}
+}
+
+func TestAPIV1InvalidPages(t *testing.T) {
+	t.Parallel()
+
+	server := newAPITestServer(t, "alice")
+
+	cursor := func(value string) string {
+		return url.QueryEscape(base64.RawURLEncoding.EncodeToString([]byte(value)))
+	}
+
+	tests := []struct {
+		query string
+		code  string
+	}{
+		{query: "limit=0", code: "bad_request"},
+		{query: "limit=-1", code: "bad_request"},
+		{query: "limit=ten", code: "bad_request"},
+		{query: "cursor=%25%25%25", code: "invalid_cursor"},
+		{query: "cursor=" + cursor("10"), code: "invalid_cursor"},
+		{query: "cursor=" + cursor("offset:ten"), code: "invalid_cursor"},
+		{query: "cursor=" + cursor("offset:-1"), code: "invalid_cursor"},
+	}
+
+	for _, path := range []string{"/api/v1/players", "/api/v1/highscores/overall", "/api/v1/gains/overall"} {
+		for _, test := range tests {
+			name := path + "?" + test.query
+
+			var problem struct {
+				Status int    `json:"status"`
+				Code   string `json:"code"`
+				Detail string `json:"detail"`
+			}
+
+			response := getAPI(t, server, name, &problem)
+			require.Equal(t, http.StatusBadRequest, response.StatusCode, name)
+			require.Equal(t, "application/problem+json", response.Header.Get("Content-Type"), name)
+			require.Equal(t, http.StatusBadRequest, problem.Status, name)
+			require.Equal(t, test.code, problem.Code, name)
+			require.NotEmpty(t, problem.Detail, name)
+		}
+	}
+}
+
+func TestAPIV1UnknownPaths(t *testing.T) {
+	t.Parallel()
+
+	server := newAPITestServer(t, "alice")
+
+	tests := []struct {
+		path string
+		code string
+	}{
+		{path: "/api/v1/players/bob", code: "player_not_found"},
+		{path: "/api/v1/players/alice/skills/knitting/history", code: "unknown_skill"},
+		{path: "/api/v1/highscores/knitting", code: "unknown_highscore_category"},
+		{path: "/api/v1/gains/knitting", code: "unknown_skill"},
+	}
+
+	for _, test := range tests {
+		var problem struct {
+			Code string `json:"code"`
+		}
+
+		response := getAPI(t, server, test.path, &problem)
+		require.Equal(t, http.StatusNotFound, response.StatusCode, test.path)
+		require.Equal(t, test.code, problem.Code, test.path)
+	}
+}
@@ -0,0 +1,126 @@
+package web
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/cadyyan/void-tool/internal/services"
+)
+
+const (
+	chartWidth   = 640
+	chartHeight  = 240
+	chartPadding = 40
+)
+
+// LineChart is the data needed to draw an SVG line chart in a template. All coordinates are
+// already scaled to the chart's view box.
+type LineChart struct {
+	Width      int
+	Height     int
+	Padding    int
+	Points     []LineChartPoint
+	MinLabel   string
+	MaxLabel   string
+	StartLabel string
+	EndLabel   string
+}
+
+type LineChartPoint struct {
+	X     float64
+	Y     float64
+	Label string
+}
+
+func (chart LineChart) Empty() bool {
+	return len(chart.Points) == 0
+}
+
+// Polyline formats the points for use in an SVG polyline's points attribute.
+func (chart LineChart) Polyline() string {
+	points := make([]string, len(chart.Points))
+	for index, point := range chart.Points {
+		points[index] = fmt.Sprintf("%.2f,%.2f", point.X, point.Y)
+	}
+
+	return strings.Join(points, " ")
+}
+
+func (chart LineChart) Left() int {
+	return chart.Padding
+}
+
+func (chart LineChart) Right() int {
+	return chart.Width - chart.Padding
+}
+
+func (chart LineChart) Top() int {
+	return chart.Padding
+}
+
+func (chart LineChart) Bottom() int {
+	return chart.Height - chart.Padding
+}
+
+// newExperienceChart plots experience against time for the given date range.
+func newExperienceChart(
+	snapshots []services.PlayerSkillSnapshot,
+	dateRange DateRange,
+) LineChart {
+	chart := LineChart{
+		Width:      chartWidth,
+		Height:     chartHeight,
+		Padding:    chartPadding,
+		Points:     make([]LineChartPoint, len(snapshots)),
+		MinLabel:   "",
+		MaxLabel:   "",
+		StartLabel: dateRange.From.Format(time.DateOnly),
+		EndLabel:   dateRange.To.Format(time.DateOnly),
+	}
+
+	if len(snapshots) == 0 {
+		return chart
+	}
+
+	minExperience := snapshots[0].Experience
+	maxExperience := snapshots[0].Experience
+
+	for _, snapshot := range snapshots {
+		minExperience = min(minExperience, snapshot.Experience)
+		maxExperience = max(maxExperience, snapshot.Experience)
+	}
+
+	chart.MinLabel = FormatFloat(minExperience)
+	chart.MaxLabel = FormatFloat(maxExperience)
+
+	plotWidth := float64(chart.Right() - chart.Left())
+	plotHeight := float64(chart.Bottom() - chart.Top())
+	totalDuration := dateRange.To.Sub(dateRange.From)
+	experienceRange := maxExperience - minExperience
+
+	for index, snapshot := range snapshots {
+		x := 0.5
+		if totalDuration > 0 {
+			x = float64(snapshot.Date.Sub(dateRange.From)) / float64(totalDuration)
+		}
+
+		y := 0.5
+		if experienceRange > 0 {
+			y = (snapshot.Experience - minExperience) / experienceRange
+		}
+
+		chart.Points[index] = LineChartPoint{
+			X: float64(chart.Left()) + x*plotWidth,
+			Y: float64(chart.Bottom()) - y*plotHeight,
+			Label: fmt.Sprintf(
+				"%s: %s XP (level %s)",
+				snapshot.Date.Format(time.DateOnly),
+				FormatFloat(snapshot.Experience),
+				FormatInt(snapshot.Level),
+			),
+		}
+	}
+
+	return chart
+}
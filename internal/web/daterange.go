@@ -0,0 +1,143 @@
+package web
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/cadyyan/void-tool/internal/services"
+)
+
+const defaultHistoryDays = 90
+
+var errInvalidDateRange = errors.New("the start of the date range is after the end")
+
+type DateRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// newDefaultDateRange ends today and starts the given number of days earlier.
+func newDefaultDateRange(days int) DateRange {
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+
+	return DateRange{
+		From: to.AddDate(0, 0, -days),
+		To:   to,
+	}
+}
+
+// newDateRangeFromRequest reads the "from" and "to" query parameters (formatted as YYYY-MM-DD).
+// When they're missing the range ends today and starts the given number of days earlier.
+func newDateRangeFromRequest(r *http.Request, defaultDays int) (DateRange, error) {
+	query := r.URL.Query()
+
+	defaultRange := newDefaultDateRange(defaultDays)
+
+	to := defaultRange.To
+	if value := query.Get("to"); value != "" {
+		parsed, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			return DateRange{}, fmt.Errorf("invalid end date: %w", err)
+		}
+
+		to = parsed
+	}
+
+	from := to.AddDate(0, 0, -defaultDays)
+	if value := query.Get("from"); value != "" {
+		parsed, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			return DateRange{}, fmt.Errorf("invalid start date: %w", err)
+		}
+
+		from = parsed
+	}
+
+	if from.After(to) {
+		return DateRange{}, errInvalidDateRange
+	}
+
+	return DateRange{
+		From: from,
+		To:   to,
+	}, nil
+}
+
+// defaultHourlyHistoryDays is shorter than defaultHistoryDays since hourly history is for looking
+// at individual sessions.
+const defaultHourlyHistoryDays = 2
+
+var errInvalidGranularity = errors.New("unknown granularity")
+
+// newHistoryGranularityFromRequest reads the "granularity" query parameter, defaulting to daily
+// history.
+func newHistoryGranularityFromRequest(r *http.Request) (services.HistoryGranularity, error) {
+	switch granularity := services.HistoryGranularity(r.URL.Query().Get("granularity")); granularity {
+	case "", services.HistoryGranularityDay:
+		return services.HistoryGranularityDay, nil
+	case services.HistoryGranularityHour:
+		return services.HistoryGranularityHour, nil
+	default:
+		return "", fmt.Errorf("%w: %s", errInvalidGranularity, granularity)
+	}
+}
+
+// EndOfDay is the last moment of the range's final day so times on that day are included.
+func (dateRange DateRange) EndOfDay() time.Time {
+	return dateRange.To.AddDate(0, 0, 1).Add(-time.Nanosecond)
+}
+
+// Gains can be tracked over these periods, each ending today. A custom period uses the "from"
+// and "to" query parameters instead.
+const (
+	gainsPeriodDay    = "day"
+	gainsPeriodWeek   = "week"
+	gainsPeriodMonth  = "month"
+	gainsPeriodCustom = "custom"
+)
+
+var gainsPeriods = []string{
+	gainsPeriodDay,
+	gainsPeriodWeek,
+	gainsPeriodMonth,
+	gainsPeriodCustom,
+}
+
+var errInvalidGainsPeriod = errors.New("unknown gains period")
+
+// newGainsPeriodFromRequest reads the "period" query parameter, defaulting to a week unless a
+// custom date range was given.
+func newGainsPeriodFromRequest(r *http.Request) (string, DateRange, error) {
+	query := r.URL.Query()
+
+	period := query.Get("period")
+	if period == "" {
+		period = gainsPeriodWeek
+
+		if query.Has("from") || query.Has("to") {
+			period = gainsPeriodCustom
+		}
+	}
+
+	today := newDefaultDateRange(0).To
+
+	switch period {
+	case gainsPeriodDay:
+		return period, DateRange{From: today.AddDate(0, 0, -1), To: today}, nil
+	case gainsPeriodWeek:
+		return period, DateRange{From: today.AddDate(0, 0, -7), To: today}, nil
+	case gainsPeriodMonth:
+		return period, DateRange{From: today.AddDate(0, -1, 0), To: today}, nil
+	case gainsPeriodCustom:
+		dateRange, err := newDateRangeFromRequest(r, 7)
+		if err != nil {
+			return "", DateRange{}, err
+		}
+
+		return period, dateRange, nil
+	default:
+		return "", DateRange{}, errInvalidGainsPeriod
+	}
+}
@@ -0,0 +1,234 @@
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/cadyyan/void-tool/internal/logging"
+	"github.com/cadyyan/void-tool/internal/services"
+)
+
+// httpError is an error that's shown to visitors as is, with the status it's responded with.
+// Any other error is an internal error and its details are only logged.
+type httpError struct {
+	Status  int
+	Code    string
+	Message string
+}
+
+func (err httpError) Error() string {
+	return err.Message
+}
+
+// Error codes let API clients handle errors without parsing the message, which can change.
+const (
+	errorCodeBadRequest               = "bad_request"
+	errorCodeNotFound                 = "not_found"
+	errorCodeMethodNotAllowed         = "method_not_allowed"
+	errorCodeUnknownWorld             = "unknown_world"
+	errorCodeUnknownSkill             = "unknown_skill"
+	errorCodeUnknownHighscoreCategory = "unknown_highscore_category"
+	errorCodePlayerNotFound           = "player_not_found"
+	errorCodeInvalidCursor            = "invalid_cursor"
+	errorCodeInternal                 = "internal_error"
+)
+
+// errorCodes are every error code, they're listed in the API's documentation.
+var errorCodes = []string{
+	errorCodeBadRequest,
+	errorCodeNotFound,
+	errorCodeMethodNotAllowed,
+	errorCodeUnknownWorld,
+	errorCodeUnknownSkill,
+	errorCodeUnknownHighscoreCategory,
+	errorCodePlayerNotFound,
+	errorCodeInvalidCursor,
+	errorCodeInternal,
+}
+
+var errPanic = errors.New("handler panicked")
+
+var (
+	errPageNotFound = httpError{
+		Status:  http.StatusNotFound,
+		Code:    errorCodeNotFound,
+		Message: "page not found",
+	}
+	errMethodNotAllowed = httpError{
+		Status:  http.StatusMethodNotAllowed,
+		Code:    errorCodeMethodNotAllowed,
+		Message: "method not allowed",
+	}
+	errUnknownWorld = httpError{
+		Status:  http.StatusNotFound,
+		Code:    errorCodeUnknownWorld,
+		Message: "unknown world",
+	}
+	errUnknownSkill = httpError{
+		Status:  http.StatusNotFound,
+		Code:    errorCodeUnknownSkill,
+		Message: "unknown skill",
+	}
+	errUnknownHighscoreCategory = httpError{
+		Status:  http.StatusNotFound,
+		Code:    errorCodeUnknownHighscoreCategory,
+		Message: "unknown highscore category",
+	}
+)
+
+func newBadRequestError(err error) httpError {
+	return httpError{
+		Status:  http.StatusBadRequest,
+		Code:    errorCodeBadRequest,
+		Message: err.Error(),
+	}
+}
+
+// newHTTPError works out what to tell a visitor about an error. Things that don't exist in
+// storage are not found, anything else that isn't already an httpError is an internal error.
+func newHTTPError(err error) httpError {
+	var httpErr httpError
+	if errors.As(err, &httpErr) {
+		return httpErr
+	}
+
+	switch {
+	case errors.Is(err, services.ErrPlayerNotFound):
+		return httpError{
+			Status:  http.StatusNotFound,
+			Code:    errorCodePlayerNotFound,
+			Message: "player not found",
+		}
+	case errors.Is(err, services.ErrNotFound):
+		return httpError{
+			Status:  http.StatusNotFound,
+			Code:    errorCodeNotFound,
+			Message: "not found",
+		}
+	default:
+		return httpError{
+			Status:  http.StatusInternalServerError,
+			Code:    errorCodeInternal,
+			Message: "something went wrong",
+		}
+	}
+}
+
+// problem is an RFC 9457 problem details body, what the API responds with when something goes
+// wrong.
+type problem struct {
+	Type   string `json:"type"`
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	Detail string `json:"detail"`
+	Code   string `json:"code"`
+}
+
+func writeProblem(w http.ResponseWriter, err httpError) {
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(err.Status)
+
+	_ = json.NewEncoder(w).Encode(problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(err.Status),
+		Status: err.Status,
+		Detail: err.Message,
+		Code:   err.Code,
+	})
+}
+
+var errorPageTemplate = template.Must(
+	template.New("error.html").ParseFS(templateFS, "templates/error.html"),
+)
+
+// writeError responds to a request that failed. API requests, and anything else that asked
+// for JSON, get problem details and everything else gets an error page. Internal errors have to
+// be logged before they're written since their details aren't shown.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	httpErr := newHTTPError(err)
+
+	if wantsJSON(r) {
+		writeProblem(w, httpErr)
+
+		return
+	}
+
+	var page bytes.Buffer
+
+	templateErr := errorPageTemplate.Execute(&page, map[string]any{
+		"Status": httpErr.Status,
+		"Title":  http.StatusText(httpErr.Status),
+		"Error":  httpErr,
+	})
+	if templateErr != nil {
+		http.Error(w, httpErr.Message, httpErr.Status)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(httpErr.Status)
+	_, _ = page.WriteTo(w)
+}
+
+func wantsJSON(r *http.Request) bool {
+	if strings.HasPrefix(r.URL.Path, "/api/") {
+		return true
+	}
+
+	accept := r.Header.Get("Accept")
+
+	return strings.Contains(accept, "json") && !strings.Contains(accept, "text/html")
+}
+
+// renderPage writes a page once it's been rendered in full so a template that fails part way
+// through is shown as an error page rather than half a page.
+func renderPage(
+	w http.ResponseWriter,
+	r *http.Request,
+	logger *slog.Logger,
+	tmpl *template.Template,
+	data any,
+) {
+	var page bytes.Buffer
+
+	if err := tmpl.Execute(&page, data); err != nil {
+		logger.ErrorContext(r.Context(), "Unable to render page", logging.Err(err))
+		writeError(w, r, err)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = page.WriteTo(w)
+}
+
+// recoverer turns panics into internal errors so they're shown like any other error.
+func recoverer(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				recovered := recover()
+				if recovered == nil {
+					return
+				}
+
+				// The server uses this to abort responses on purpose
+				if recovered == http.ErrAbortHandler {
+					panic(recovered)
+				}
+
+				logger.ErrorContext(r.Context(), "Recovered from panic", slog.Any("panic", recovered))
+				writeError(w, r, errPanic)
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package web
+
+import (
+	"errors"
+	"html/template"
+	"io/fs"
+	"log/slog"
+	"net/http"
+
+	"github.com/cadyyan/void-tool/internal/logging"
+	"github.com/cadyyan/void-tool/internal/services"
+)
+
+const recentIngestionRunsLimit = 50
+
+func HandlerAdminIngestion(
+	logger *slog.Logger,
+	templateFS fs.FS,
+	storageService services.StorageService,
+) http.HandlerFunc {
+	tmpl := template.Must(
+		template.New("admin_ingestion.html").
+			Funcs(DefaultMacros).
+			ParseFS(templateFS, "templates/admin_ingestion.html"),
+	)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		lastSuccessfulRun, err := storageService.GetLastSuccessfulIngestionRun(ctx)
+		hasSuccessfulRun := err == nil
+
+		if err != nil && !errors.Is(err, services.ErrIngestionRunNotFound) {
+			logger.ErrorContext(ctx, "Unable to get last successful ingestion run", logging.Err(err))
+			writeError(w, r, err)
+
+			return
+		}
+
+		runs, err := storageService.GetRecentIngestionRuns(ctx, recentIngestionRunsLimit)
+		if err != nil {
+			logger.ErrorContext(ctx, "Unable to get recent ingestion runs", logging.Err(err))
+			writeError(w, r, err)
+
+			return
+		}
+
+		quarantined, err := storageService.GetQuarantinedSaveFiles(ctx)
+		if err != nil {
+			logger.ErrorContext(ctx, "Unable to get quarantined save files", logging.Err(err))
+			writeError(w, r, err)
+
+			return
+		}
+
+		templateData := map[string]any{
+			"HasSuccessfulRun":  hasSuccessfulRun,
+			"LastSuccessfulRun": lastSuccessfulRun,
+			"Runs":              runs,
+			"Quarantined":       quarantined,
+		}
+		renderPage(w, r, logger, tmpl, templateData)
+	}
+}
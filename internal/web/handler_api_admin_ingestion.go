@@ -0,0 +1,134 @@
+package web
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/cadyyan/void-tool/internal/logging"
+	"github.com/cadyyan/void-tool/internal/services"
+)
+
+type ingestionStatusResponse struct {
+	LastSuccessfulRun *ingestionRunEntry  `json:"lastSuccessfulRun"`
+	Runs              []ingestionRunEntry `json:"runs"`
+	Quarantined       []quarantinedEntry  `json:"quarantined"`
+}
+
+type quarantinedEntry struct {
+	World       string    `json:"world"`
+	File        string    `json:"file"`
+	Hash        string    `json:"hash,omitempty"`
+	Error       string    `json:"error"`
+	FirstSeenOn time.Time `json:"firstSeenOn"`
+	LastSeenOn  time.Time `json:"lastSeenOn"`
+}
+
+type ingestionRunEntry struct {
+	ID             string                  `json:"id"`
+	World          string                  `json:"world"`
+	StartedOn      time.Time               `json:"startedOn"`
+	FinishedOn     *time.Time              `json:"finishedOn"`
+	Succeeded      bool                    `json:"succeeded"`
+	FilesScanned   int                     `json:"filesScanned"`
+	PlayersUpdated int                     `json:"playersUpdated"`
+	Error          string                  `json:"error,omitempty"`
+	Failures       []ingestionFailureEntry `json:"failures"`
+}
+
+type ingestionFailureEntry struct {
+	File  string `json:"file"`
+	Error string `json:"error"`
+}
+
+func newIngestionRunEntry(run services.IngestionRun) ingestionRunEntry {
+	var finishedOn *time.Time
+	if run.Finished() {
+		finishedOn = &run.FinishedOn
+	}
+
+	failures := make([]ingestionFailureEntry, len(run.Failures))
+	for index, failure := range run.Failures {
+		failures[index] = ingestionFailureEntry{
+			File:  failure.File,
+			Error: failure.Error,
+		}
+	}
+
+	return ingestionRunEntry{
+		ID:             run.ID,
+		World:          run.World,
+		StartedOn:      run.StartedOn,
+		FinishedOn:     finishedOn,
+		Succeeded:      run.Succeeded(),
+		FilesScanned:   run.FilesScanned,
+		PlayersUpdated: run.PlayersUpdated,
+		Error:          run.Error,
+		Failures:       failures,
+	}
+}
+
+func HandlerAPIAdminIngestion(
+	logger *slog.Logger,
+	storageService services.StorageService,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		var lastSuccessfulRun *ingestionRunEntry
+
+		run, err := storageService.GetLastSuccessfulIngestionRun(ctx)
+		if err == nil {
+			entry := newIngestionRunEntry(run)
+			lastSuccessfulRun = &entry
+		} else if !errors.Is(err, services.ErrIngestionRunNotFound) {
+			logger.ErrorContext(ctx, "Unable to get last successful ingestion run", logging.Err(err))
+			writeJSONError(w, http.StatusInternalServerError, "unable to get last successful ingestion run")
+
+			return
+		}
+
+		runs, err := storageService.GetRecentIngestionRuns(ctx, recentIngestionRunsLimit)
+		if err != nil {
+			logger.ErrorContext(ctx, "Unable to get recent ingestion runs", logging.Err(err))
+			writeJSONError(w, http.StatusInternalServerError, "unable to get recent ingestion runs")
+
+			return
+		}
+
+		entries := make([]ingestionRunEntry, len(runs))
+		for index, run := range runs {
+			entries[index] = newIngestionRunEntry(run)
+		}
+
+		quarantined, err := storageService.GetQuarantinedSaveFiles(ctx)
+		if err != nil {
+			logger.ErrorContext(ctx, "Unable to get quarantined save files", logging.Err(err))
+			writeJSONError(w, http.StatusInternalServerError, "unable to get quarantined save files")
+
+			return
+		}
+
+		quarantinedEntries := make([]quarantinedEntry, len(quarantined))
+		for index, saveFile := range quarantined {
+			quarantinedEntries[index] = quarantinedEntry{
+				World:       saveFile.World,
+				File:        saveFile.Path,
+				Hash:        saveFile.Hash,
+				Error:       saveFile.Error,
+				FirstSeenOn: saveFile.FirstSeenOn,
+				LastSeenOn:  saveFile.LastSeenOn,
+			}
+		}
+
+		err = writeJSON(w, http.StatusOK, ingestionStatusResponse{
+			LastSuccessfulRun: lastSuccessfulRun,
+			Runs:              entries,
+			Quarantined:       quarantinedEntries,
+		})
+		if err != nil {
+			logger.ErrorContext(ctx, "Unable to write ingestion status", logging.Err(err))
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package web
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/cadyyan/void-tool/internal/logging"
+	"github.com/cadyyan/void-tool/internal/services"
+	"github.com/go-chi/chi/v5"
+)
+
+type gainsResponse struct {
+	Skill   string       `json:"skill"`
+	Period  string       `json:"period"`
+	From    string       `json:"from"`
+	To      string       `json:"to"`
+	Page    int          `json:"page"`
+	Entries []gainsEntry `json:"entries"`
+}
+
+type gainsEntry struct {
+	Rank     int       `json:"rank,omitempty"`
+	Username string    `json:"username,omitempty"`
+	Skill    string    `json:"skill,omitempty"`
+	Gain     gainsDiff `json:"gain"`
+}
+
+type gainsDiff struct {
+	StartLevel       int     `json:"startLevel"`
+	StartExperience  float64 `json:"startExperience"`
+	EndLevel         int     `json:"endLevel"`
+	EndExperience    float64 `json:"endExperience"`
+	LevelsGained     int     `json:"levelsGained"`
+	ExperienceGained float64 `json:"experienceGained"`
+}
+
+func newGainsDiff(gain services.SkillGain) gainsDiff {
+	return gainsDiff{
+		StartLevel:       gain.StartLevel,
+		StartExperience:  gain.StartExperience,
+		EndLevel:         gain.EndLevel,
+		EndExperience:    gain.EndExperience,
+		LevelsGained:     gain.LevelsGained(),
+		ExperienceGained: gain.ExperienceGained(),
+	}
+}
+
+func HandlerAPIGains(
+	logger *slog.Logger,
+	storageService services.StorageService,
+	worlds Worlds,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		world, ok := worlds.lookup(r)
+		if !ok {
+			writeJSONError(w, http.StatusNotFound, "unknown world")
+
+			return
+		}
+
+		skill, ok := lookupSkill(chi.URLParam(r, "skill"))
+		if !ok {
+			writeJSONError(w, http.StatusNotFound, "unknown skill")
+
+			return
+		}
+
+		period, dateRange, err := newGainsPeriodFromRequest(r)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+
+			return
+		}
+
+		pagination := newPaginationFromRequest(r, defaultPageSize)
+
+		gainers, err := storageService.GetTopGainers(ctx, services.GetTopGainersParams{
+			World:  world.Name,
+			Skill:  skill,
+			From:   dateRange.From,
+			To:     dateRange.To,
+			Limit:  pagination.PageSize,
+			Offset: pagination.Offset(),
+		})
+		if err != nil {
+			logger.ErrorContext(ctx, "Unable to get top gainers", logging.Err(err))
+			writeJSONError(w, http.StatusInternalServerError, "unable to get top gainers")
+
+			return
+		}
+
+		entries := make([]gainsEntry, len(gainers))
+		for index, gainer := range gainers {
+			entries[index] = gainsEntry{
+				Rank:     gainer.Rank,
+				Username: gainer.Username,
+				Skill:    "",
+				Gain:     newGainsDiff(gainer.Gain),
+			}
+		}
+
+		err = writeJSON(w, http.StatusOK, gainsResponse{
+			Skill:   skill,
+			Period:  period,
+			From:    dateRange.From.Format(time.DateOnly),
+			To:      dateRange.To.Format(time.DateOnly),
+			Page:    pagination.Page,
+			Entries: entries,
+		})
+		if err != nil {
+			logger.ErrorContext(ctx, "Unable to write top gainers", logging.Err(err))
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package web
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/cadyyan/void-tool/internal/logging"
+	"github.com/cadyyan/void-tool/internal/services"
+	"github.com/go-chi/chi/v5"
+)
+
+type highscoresResponse struct {
+	Skill        string           `json:"skill"`
+	Page         int              `json:"page"`
+	PageSize     int              `json:"pageSize"`
+	TotalPages   int              `json:"totalPages"`
+	TotalEntries int              `json:"totalEntries"`
+	Entries      []highscoreEntry `json:"entries"`
+}
+
+type highscoreEntry struct {
+	Rank       int     `json:"rank"`
+	Username   string  `json:"username"`
+	Level      int     `json:"level"`
+	Experience float64 `json:"experience"`
+}
+
+func HandlerAPIHighscores(
+	logger *slog.Logger,
+	storageService services.StorageService,
+	worlds Worlds,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		world, ok := worlds.lookup(r)
+		if !ok {
+			writeJSONError(w, http.StatusNotFound, "unknown world")
+
+			return
+		}
+
+		skill, ok := lookupHighscoreCategory(chi.URLParam(r, "skill"))
+		if !ok {
+			writeJSONError(w, http.StatusNotFound, "unknown highscore category")
+
+			return
+		}
+
+		pagination := newPaginationFromRequest(r, defaultPageSize)
+
+		highscores, err := getHighscoresPage(ctx, storageService, world.Name, skill, &pagination)
+		if err != nil {
+			logger.ErrorContext(ctx, "Unable to get highscores", logging.Err(err))
+			writeJSONError(w, http.StatusInternalServerError, "unable to get highscores")
+
+			return
+		}
+
+		entries := make([]highscoreEntry, len(highscores))
+		for index, highscore := range highscores {
+			entries[index] = highscoreEntry{
+				Rank:       highscore.Rank,
+				Username:   highscore.Username,
+				Level:      highscore.Level,
+				Experience: highscore.Experience,
+			}
+		}
+
+		err = writeJSON(w, http.StatusOK, highscoresResponse{
+			Skill:        skill,
+			Page:         pagination.Page,
+			PageSize:     pagination.PageSize,
+			TotalPages:   pagination.TotalPages(),
+			TotalEntries: pagination.TotalItems,
+			Entries:      entries,
+		})
+		if err != nil {
+			logger.ErrorContext(ctx, "Unable to write highscores", logging.Err(err))
+		}
+	}
+}
+
+// getHighscoresPage fetches a single page of highscores and fills in the total number of ranked
+// players on the given pagination.
+func getHighscoresPage(
+	ctx context.Context,
+	storageService services.StorageService,
+	world string,
+	skill string,
+	pagination *Pagination,
+) ([]services.HighscoreSkillRecord, error) {
+	total, err := storageService.CountHighscoresForSkill(
+		ctx,
+		services.CountHighscoresForSkillParams{
+			World: world,
+			Skill: skill,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to count highscores: %w", err)
+	}
+
+	pagination.TotalItems = total
+
+	highscores, err := storageService.GetHighscoresForSkill(
+		ctx,
+		services.GetHighscoresForSkillParams{
+			World:  world,
+			Skill:  skill,
+			Limit:  pagination.PageSize,
+			Offset: pagination.Offset(),
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get highscores: %w", err)
+	}
+
+	return highscores, nil
+}
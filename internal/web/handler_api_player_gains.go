@@ -0,0 +1,98 @@
+package web
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/cadyyan/void-tool/internal/logging"
+	"github.com/cadyyan/void-tool/internal/services"
+	"github.com/go-chi/chi/v5"
+)
+
+type playerGainsResponse struct {
+	Username string       `json:"username"`
+	Period   string       `json:"period"`
+	From     string       `json:"from"`
+	To       string       `json:"to"`
+	Skills   []gainsEntry `json:"skills"`
+}
+
+func HandlerAPIPlayerGains(
+	logger *slog.Logger,
+	storageService services.StorageService,
+	worlds Worlds,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		world, ok := worlds.lookup(r)
+		if !ok {
+			writeJSONError(w, http.StatusNotFound, "unknown world")
+
+			return
+		}
+
+		period, dateRange, err := newGainsPeriodFromRequest(r)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+
+			return
+		}
+
+		player, err := storageService.GetPlayerByUsername(ctx, services.GetPlayerByUsernameParams{
+			World:    world.Name,
+			Username: chi.URLParam(r, "username"),
+		})
+		if errors.Is(err, services.ErrPlayerNotFound) {
+			writeJSONError(w, http.StatusNotFound, "unable to get user by given username")
+
+			return
+		} else if err != nil {
+			logger.ErrorContext(ctx, "Unable to get user", logging.Err(err))
+			writeJSONError(w, http.StatusInternalServerError, "unable to get user")
+
+			return
+		}
+
+		gains, err := storageService.GetPlayerGains(ctx, services.GetPlayerGainsParams{
+			World:    world.Name,
+			Username: player.Username,
+			From:     dateRange.From,
+			To:       dateRange.To,
+		})
+		if err != nil {
+			logger.ErrorContext(ctx, "Unable to get user gains", logging.Err(err))
+			writeJSONError(w, http.StatusInternalServerError, "unable to get user gains")
+
+			return
+		}
+
+		skills := make([]gainsEntry, 0, len(gains))
+		for _, skill := range highscoreSkills {
+			gain, ok := gains[skill]
+			if !ok {
+				continue
+			}
+
+			skills = append(skills, gainsEntry{
+				Rank:     0,
+				Username: "",
+				Skill:    skill,
+				Gain:     newGainsDiff(gain),
+			})
+		}
+
+		err = writeJSON(w, http.StatusOK, playerGainsResponse{
+			Username: player.Username,
+			Period:   period,
+			From:     dateRange.From.Format(time.DateOnly),
+			To:       dateRange.To.Format(time.DateOnly),
+			Skills:   skills,
+		})
+		if err != nil {
+			logger.ErrorContext(ctx, "Unable to write user gains", logging.Err(err))
+		}
+	}
+}
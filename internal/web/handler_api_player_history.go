@@ -0,0 +1,124 @@
+package web
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/cadyyan/void-tool/internal/logging"
+	"github.com/cadyyan/void-tool/internal/services"
+	"github.com/go-chi/chi/v5"
+)
+
+type playerHistoryResponse struct {
+	Username    string                  `json:"username"`
+	Skill       string                  `json:"skill"`
+	Granularity string                  `json:"granularity"`
+	From        string                  `json:"from"`
+	To          string                  `json:"to"`
+	Snapshots   []playerHistorySnapshot `json:"snapshots"`
+}
+
+type playerHistorySnapshot struct {
+	Date       string  `json:"date"`
+	Level      int     `json:"level"`
+	Experience float64 `json:"experience"`
+}
+
+func HandlerAPIPlayerHistory(
+	logger *slog.Logger,
+	storageService services.StorageService,
+	worlds Worlds,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		world, ok := worlds.lookup(r)
+		if !ok {
+			writeJSONError(w, http.StatusNotFound, "unknown world")
+
+			return
+		}
+
+		skill, ok := lookupSkill(chi.URLParam(r, "skill"))
+		if !ok {
+			writeJSONError(w, http.StatusNotFound, "unknown skill")
+
+			return
+		}
+
+		granularity, err := newHistoryGranularityFromRequest(r)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+
+			return
+		}
+
+		defaultDays, snapshotFormat := defaultHistoryDays, time.DateOnly
+		if granularity == services.HistoryGranularityHour {
+			defaultDays, snapshotFormat = defaultHourlyHistoryDays, time.RFC3339
+		}
+
+		dateRange, err := newDateRangeFromRequest(r, defaultDays)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, err.Error())
+
+			return
+		}
+
+		player, err := storageService.GetPlayerByUsername(ctx, services.GetPlayerByUsernameParams{
+			World:    world.Name,
+			Username: chi.URLParam(r, "username"),
+		})
+		if errors.Is(err, services.ErrPlayerNotFound) {
+			writeJSONError(w, http.StatusNotFound, "unable to get user by given username")
+
+			return
+		} else if err != nil {
+			logger.ErrorContext(ctx, "Unable to get user", logging.Err(err))
+			writeJSONError(w, http.StatusInternalServerError, "unable to get user")
+
+			return
+		}
+
+		history, err := storageService.GetPlayerSkillHistory(
+			ctx,
+			services.GetPlayerSkillHistoryParams{
+				World:       world.Name,
+				Username:    player.Username,
+				Skill:       skill,
+				From:        dateRange.From,
+				To:          dateRange.EndOfDay(),
+				Granularity: granularity,
+			},
+		)
+		if err != nil {
+			logger.ErrorContext(ctx, "Unable to get user skill history", logging.Err(err))
+			writeJSONError(w, http.StatusInternalServerError, "unable to get user skill history")
+
+			return
+		}
+
+		snapshots := make([]playerHistorySnapshot, len(history))
+		for index, snapshot := range history {
+			snapshots[index] = playerHistorySnapshot{
+				Date:       snapshot.Date.Format(snapshotFormat),
+				Level:      snapshot.Level,
+				Experience: snapshot.Experience,
+			}
+		}
+
+		err = writeJSON(w, http.StatusOK, playerHistoryResponse{
+			Username:    player.Username,
+			Skill:       skill,
+			Granularity: string(granularity),
+			From:        dateRange.From.Format(time.DateOnly),
+			To:          dateRange.To.Format(time.DateOnly),
+			Snapshots:   snapshots,
+		})
+		if err != nil {
+			logger.ErrorContext(ctx, "Unable to write user skill history", logging.Err(err))
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package web
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/cadyyan/void-tool/internal/logging"
+	"github.com/cadyyan/void-tool/internal/services"
+)
+
+const maxSearchLimit = 50
+
+// minSearchQueryLength is how long a query has to be before it's searched for. Shorter queries
+// match too many players to be useful suggestions.
+const minSearchQueryLength = 2
+
+type playerSearchResponse struct {
+	Query   string               `json:"query"`
+	Results []playerSearchResult `json:"results"`
+}
+
+type playerSearchResult struct {
+	Username string `json:"username"`
+	URL      string `json:"url"`
+}
+
+// HandlerAPIPlayerSearch finds players by name for the search box's autocomplete. The q query
+// parameter doesn't have to be a whole name, see services.SearchPlayersParams.
+func HandlerAPIPlayerSearch(
+	logger *slog.Logger,
+	storageService services.StorageService,
+	worlds Worlds,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		world, ok := worlds.lookup(r)
+		if !ok {
+			writeJSONError(w, http.StatusNotFound, "unknown world")
+
+			return
+		}
+
+		query := r.URL.Query().Get("q")
+
+		if utf8.RuneCountInString(services.NormalizeUsername(query)) < minSearchQueryLength {
+			err := writeJSON(w, http.StatusOK, playerSearchResponse{
+				Query:   query,
+				Results: []playerSearchResult{},
+			})
+			if err != nil {
+				logger.ErrorContext(ctx, "Unable to write player search results", logging.Err(err))
+			}
+
+			return
+		}
+
+		limit := services.DefaultSearchLimit
+		if requested, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && requested > 0 {
+			limit = min(requested, maxSearchLimit)
+		}
+
+		players, err := storageService.SearchPlayers(ctx, services.SearchPlayersParams{
+			World: world.Name,
+			Query: query,
+			Limit: limit,
+		})
+		if err != nil {
+			logger.ErrorContext(ctx, "Unable to search players", logging.Err(err))
+			writeJSONError(w, http.StatusInternalServerError, "unable to search players")
+
+			return
+		}
+
+		results := make([]playerSearchResult, len(players))
+		for index, player := range players {
+			results[index] = playerSearchResult{
+				Username: player.Username,
+				URL:      worlds.playerPath(world.Name, player.Username),
+			}
+		}
+
+		err = writeJSON(w, http.StatusOK, playerSearchResponse{
+			Query:   query,
+			Results: results,
+		})
+		if err != nil {
+			logger.ErrorContext(ctx, "Unable to write player search results", logging.Err(err))
+		}
+	}
+}
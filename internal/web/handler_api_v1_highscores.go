@@ -0,0 +1,173 @@
+package web
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/cadyyan/void-tool/internal/logging"
+	"github.com/cadyyan/void-tool/internal/services"
+	"github.com/go-chi/chi/v5"
+)
+
+type apiHighscore struct {
+	Rank       int     `json:"rank"`
+	Username   string  `json:"username"`
+	Level      int     `json:"level"`
+	Experience float64 `json:"experience"`
+}
+
+// apiGainsPage is a page of the players that gained the most in a skill over a period.
+type apiGainsPage struct {
+	Skill      string      `json:"skill"`
+	Period     string      `json:"period"`
+	From       string      `json:"from" format:"date"`
+	To         string      `json:"to" format:"date"`
+	Data       []apiGainer `json:"data"`
+	NextCursor *string     `json:"nextCursor"`
+}
+
+type apiGainer struct {
+	Rank     int     `json:"rank"`
+	Username string  `json:"username"`
+	Gain     apiGain `json:"gain"`
+}
+
+func HandlerAPIV1Highscores(
+	logger *slog.Logger,
+	storageService services.StorageService,
+	worlds Worlds,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		world, ok := lookupAPIWorld(w, r, worlds)
+		if !ok {
+			return
+		}
+
+		skill, ok := lookupHighscoreCategory(chi.URLParam(r, "skill"))
+		if !ok {
+			writeAPIError(
+				w,
+				http.StatusNotFound,
+				errorCodeUnknownHighscoreCategory,
+				"unknown highscore category",
+			)
+
+			return
+		}
+
+		pageRequest, err := newAPIPageRequestFromRequest(r)
+		if err != nil {
+			writeAPIPageRequestError(w, err)
+
+			return
+		}
+
+		highscores, err := storageService.GetHighscoresForSkill(
+			ctx,
+			services.GetHighscoresForSkillParams{
+				World:  world.Name,
+				Skill:  skill,
+				Limit:  pageRequest.Fetch(),
+				Offset: pageRequest.Offset,
+			},
+		)
+		if err != nil {
+			logger.ErrorContext(ctx, "Unable to get highscores", logging.Err(err))
+			writeAPIError(w, http.StatusInternalServerError, errorCodeInternal, "unable to get highscores")
+
+			return
+		}
+
+		entries := make([]apiHighscore, len(highscores))
+		for index, highscore := range highscores {
+			entries[index] = apiHighscore{
+				Rank:       highscore.Rank,
+				Username:   highscore.Username,
+				Level:      highscore.Level,
+				Experience: highscore.Experience,
+			}
+		}
+
+		err = writeJSON(w, http.StatusOK, newAPIPage(pageRequest, entries))
+		if err != nil {
+			logger.ErrorContext(ctx, "Unable to write highscores", logging.Err(err))
+		}
+	}
+}
+
+func HandlerAPIV1Gains(
+	logger *slog.Logger,
+	storageService services.StorageService,
+	worlds Worlds,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		world, ok := lookupAPIWorld(w, r, worlds)
+		if !ok {
+			return
+		}
+
+		skill, ok := lookupSkill(chi.URLParam(r, "skill"))
+		if !ok {
+			writeAPIError(w, http.StatusNotFound, errorCodeUnknownSkill, "unknown skill")
+
+			return
+		}
+
+		period, dateRange, err := newGainsPeriodFromRequest(r)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, errorCodeBadRequest, err.Error())
+
+			return
+		}
+
+		pageRequest, err := newAPIPageRequestFromRequest(r)
+		if err != nil {
+			writeAPIPageRequestError(w, err)
+
+			return
+		}
+
+		gainers, err := storageService.GetTopGainers(ctx, services.GetTopGainersParams{
+			World:  world.Name,
+			Skill:  skill,
+			From:   dateRange.From,
+			To:     dateRange.To,
+			Limit:  pageRequest.Fetch(),
+			Offset: pageRequest.Offset,
+		})
+		if err != nil {
+			logger.ErrorContext(ctx, "Unable to get top gainers", logging.Err(err))
+			writeAPIError(w, http.StatusInternalServerError, errorCodeInternal, "unable to get top gainers")
+
+			return
+		}
+
+		entries := make([]apiGainer, len(gainers))
+		for index, gainer := range gainers {
+			entries[index] = apiGainer{
+				Rank:     gainer.Rank,
+				Username: gainer.Username,
+				Gain:     newAPIGain(gainer.Gain),
+			}
+		}
+
+		page := newAPIPage(pageRequest, entries)
+
+		err = writeJSON(w, http.StatusOK, apiGainsPage{
+			Skill:      skill,
+			Period:     period,
+			From:       dateRange.From.Format(time.DateOnly),
+			To:         dateRange.To.Format(time.DateOnly),
+			Data:       page.Data,
+			NextCursor: page.NextCursor,
+		})
+		if err != nil {
+			logger.ErrorContext(ctx, "Unable to write top gainers", logging.Err(err))
+		}
+	}
+}
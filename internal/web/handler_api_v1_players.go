@@ -0,0 +1,457 @@
+package web
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/cadyyan/void-tool/internal/logging"
+	"github.com/cadyyan/void-tool/internal/services"
+	"github.com/go-chi/chi/v5"
+)
+
+type apiWorld struct {
+	Name    string `json:"name"`
+	Default bool   `json:"default"`
+}
+
+type apiPlayerSummary struct {
+	Username        string  `json:"username"`
+	TotalLevel      int     `json:"totalLevel"`
+	TotalExperience float64 `json:"totalExperience"`
+	CombatLevel     int     `json:"combatLevel"`
+	LastChanged     *string `json:"lastChanged" format:"date"`
+}
+
+type apiPlayer struct {
+	Username      string            `json:"username"`
+	World         string            `json:"world"`
+	CreatedOn     time.Time         `json:"createdOn"`
+	PreviousNames []apiPreviousName `json:"previousNames"`
+}
+
+type apiPreviousName struct {
+	Username  string    `json:"username"`
+	RenamedOn time.Time `json:"renamedOn"`
+}
+
+type apiPlayerSkills struct {
+	Username        string     `json:"username"`
+	TotalLevel      int        `json:"totalLevel"`
+	TotalExperience float64    `json:"totalExperience"`
+	CombatLevel     int        `json:"combatLevel"`
+	Skills          []apiSkill `json:"skills"`
+}
+
+type apiSkill struct {
+	Name       string  `json:"name"`
+	Level      int     `json:"level"`
+	Experience float64 `json:"experience"`
+}
+
+type apiSkillHistory struct {
+	Username    string             `json:"username"`
+	Skill       string             `json:"skill"`
+	Granularity string             `json:"granularity"`
+	From        string             `json:"from" format:"date"`
+	To          string             `json:"to" format:"date"`
+	Snapshots   []apiSkillSnapshot `json:"snapshots"`
+}
+
+type apiSkillSnapshot struct {
+	RecordedOn time.Time `json:"recordedOn"`
+	Level      int       `json:"level"`
+	Experience float64   `json:"experience"`
+}
+
+type apiPlayerGains struct {
+	Username string         `json:"username"`
+	Period   string         `json:"period"`
+	From     string         `json:"from" format:"date"`
+	To       string         `json:"to" format:"date"`
+	Skills   []apiSkillGain `json:"skills"`
+}
+
+type apiSkillGain struct {
+	Skill string  `json:"skill"`
+	Gain  apiGain `json:"gain"`
+}
+
+type apiGain struct {
+	StartLevel       int     `json:"startLevel"`
+	StartExperience  float64 `json:"startExperience"`
+	EndLevel         int     `json:"endLevel"`
+	EndExperience    float64 `json:"endExperience"`
+	LevelsGained     int     `json:"levelsGained"`
+	ExperienceGained float64 `json:"experienceGained"`
+}
+
+func newAPIGain(gain services.SkillGain) apiGain {
+	return apiGain{
+		StartLevel:       gain.StartLevel,
+		StartExperience:  gain.StartExperience,
+		EndLevel:         gain.EndLevel,
+		EndExperience:    gain.EndExperience,
+		LevelsGained:     gain.LevelsGained(),
+		ExperienceGained: gain.ExperienceGained(),
+	}
+}
+
+func HandlerAPIV1Worlds(logger *slog.Logger, worlds Worlds) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		data := make([]apiWorld, len(worlds))
+		for index, world := range worlds {
+			data[index] = apiWorld{
+				Name:    world.Name,
+				Default: index == 0,
+			}
+		}
+
+		err := writeJSON(w, http.StatusOK, apiResponse[[]apiWorld]{Data: data})
+		if err != nil {
+			logger.ErrorContext(r.Context(), "Unable to write worlds", logging.Err(err))
+		}
+	}
+}
+
+func HandlerAPIV1Players(
+	logger *slog.Logger,
+	storageService services.StorageService,
+	worlds Worlds,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		world, ok := lookupAPIWorld(w, r, worlds)
+		if !ok {
+			return
+		}
+
+		pageRequest, err := newAPIPageRequestFromRequest(r)
+		if err != nil {
+			writeAPIPageRequestError(w, err)
+
+			return
+		}
+
+		sort := newPlayerIndexSortFromRequest(r)
+
+		index, err := storageService.GetPlayerIndex(ctx, services.GetPlayerIndexParams{
+			World:      world.Name,
+			Sort:       sort.Column,
+			Descending: sort.Descending,
+			Limit:      pageRequest.Fetch(),
+			Offset:     pageRequest.Offset,
+		})
+		if err != nil {
+			logger.ErrorContext(ctx, "Unable to get player index", logging.Err(err))
+			writeAPIError(w, http.StatusInternalServerError, errorCodeInternal, "unable to get players")
+
+			return
+		}
+
+		players := make([]apiPlayerSummary, len(index))
+		for position, record := range index {
+			var lastChanged *string
+			if !record.LastChanged.IsZero() {
+				lastChanged = ptr(record.LastChanged.Format(time.DateOnly))
+			}
+
+			players[position] = apiPlayerSummary{
+				Username:        record.Username,
+				TotalLevel:      record.TotalLevel,
+				TotalExperience: record.TotalExperience,
+				CombatLevel:     record.CombatLevel,
+				LastChanged:     lastChanged,
+			}
+		}
+
+		err = writeJSON(w, http.StatusOK, newAPIPage(pageRequest, players))
+		if err != nil {
+			logger.ErrorContext(ctx, "Unable to write players", logging.Err(err))
+		}
+	}
+}
+
+func HandlerAPIV1Player(
+	logger *slog.Logger,
+	storageService services.StorageService,
+	worlds Worlds,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		world, ok := lookupAPIWorld(w, r, worlds)
+		if !ok {
+			return
+		}
+
+		player, err := lookupAPIPlayer(ctx, storageService, world, chi.URLParam(r, "username"))
+		if err != nil {
+			writeAPIPlayerError(ctx, logger, w, err)
+
+			return
+		}
+
+		previousNames, err := storageService.GetPlayerPreviousNames(ctx, player.ID)
+		if err != nil {
+			logger.ErrorContext(ctx, "Unable to get previous player names", logging.Err(err))
+			writeAPIError(w, http.StatusInternalServerError, errorCodeInternal, "unable to get player")
+
+			return
+		}
+
+		names := make([]apiPreviousName, len(previousNames))
+		for index, name := range previousNames {
+			names[index] = apiPreviousName{
+				Username:  name.Username,
+				RenamedOn: name.RenamedOn,
+			}
+		}
+
+		err = writeJSON(w, http.StatusOK, apiResponse[apiPlayer]{
+			Data: apiPlayer{
+				Username:      player.Username,
+				World:         player.World,
+				CreatedOn:     player.CreatedOn,
+				PreviousNames: names,
+			},
+		})
+		if err != nil {
+			logger.ErrorContext(ctx, "Unable to write player", logging.Err(err))
+		}
+	}
+}
+
+func HandlerAPIV1PlayerSkills(
+	logger *slog.Logger,
+	storageService services.StorageService,
+	worlds Worlds,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		world, ok := lookupAPIWorld(w, r, worlds)
+		if !ok {
+			return
+		}
+
+		player, err := lookupAPIPlayer(ctx, storageService, world, chi.URLParam(r, "username"))
+		if err != nil {
+			writeAPIPlayerError(ctx, logger, w, err)
+
+			return
+		}
+
+		skills, err := storageService.GetPlayerSkills(ctx, services.GetPlayerSkillsParams{
+			World:    world.Name,
+			Username: player.Username,
+		})
+		if err != nil {
+			logger.ErrorContext(ctx, "Unable to get player skills", logging.Err(err))
+			writeAPIError(
+				w,
+				http.StatusInternalServerError,
+				errorCodeInternal,
+				"unable to get player skills",
+			)
+
+			return
+		}
+
+		data := apiPlayerSkills{
+			Username:        player.Username,
+			TotalLevel:      0,
+			TotalExperience: 0,
+			CombatLevel:     combatLevel(skills),
+			Skills:          make([]apiSkill, 0, len(skills)),
+		}
+
+		for _, name := range services.SkillOrder {
+			skill, ok := skills[name]
+			if !ok {
+				continue
+			}
+
+			data.TotalLevel += skill.Level
+			data.TotalExperience += skill.Experience
+			data.Skills = append(data.Skills, apiSkill{
+				Name:       name,
+				Level:      skill.Level,
+				Experience: skill.Experience,
+			})
+		}
+
+		err = writeJSON(w, http.StatusOK, apiResponse[apiPlayerSkills]{Data: data})
+		if err != nil {
+			logger.ErrorContext(ctx, "Unable to write player skills", logging.Err(err))
+		}
+	}
+}
+
+func HandlerAPIV1PlayerSkillHistory(
+	logger *slog.Logger,
+	storageService services.StorageService,
+	worlds Worlds,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		world, ok := lookupAPIWorld(w, r, worlds)
+		if !ok {
+			return
+		}
+
+		skill, ok := lookupSkill(chi.URLParam(r, "skill"))
+		if !ok {
+			writeAPIError(w, http.StatusNotFound, errorCodeUnknownSkill, "unknown skill")
+
+			return
+		}
+
+		granularity, err := newHistoryGranularityFromRequest(r)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, errorCodeBadRequest, err.Error())
+
+			return
+		}
+
+		defaultDays := defaultHistoryDays
+		if granularity == services.HistoryGranularityHour {
+			defaultDays = defaultHourlyHistoryDays
+		}
+
+		dateRange, err := newDateRangeFromRequest(r, defaultDays)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, errorCodeBadRequest, err.Error())
+
+			return
+		}
+
+		player, err := lookupAPIPlayer(ctx, storageService, world, chi.URLParam(r, "username"))
+		if err != nil {
+			writeAPIPlayerError(ctx, logger, w, err)
+
+			return
+		}
+
+		history, err := storageService.GetPlayerSkillHistory(
+			ctx,
+			services.GetPlayerSkillHistoryParams{
+				World:       world.Name,
+				Username:    player.Username,
+				Skill:       skill,
+				From:        dateRange.From,
+				To:          dateRange.EndOfDay(),
+				Granularity: granularity,
+			},
+		)
+		if err != nil {
+			logger.ErrorContext(ctx, "Unable to get player skill history", logging.Err(err))
+			writeAPIError(
+				w,
+				http.StatusInternalServerError,
+				errorCodeInternal,
+				"unable to get player skill history",
+			)
+
+			return
+		}
+
+		snapshots := make([]apiSkillSnapshot, len(history))
+		for index, snapshot := range history {
+			snapshots[index] = apiSkillSnapshot{
+				RecordedOn: snapshot.Date,
+				Level:      snapshot.Level,
+				Experience: snapshot.Experience,
+			}
+		}
+
+		err = writeJSON(w, http.StatusOK, apiResponse[apiSkillHistory]{
+			Data: apiSkillHistory{
+				Username:    player.Username,
+				Skill:       skill,
+				Granularity: string(granularity),
+				From:        dateRange.From.Format(time.DateOnly),
+				To:          dateRange.To.Format(time.DateOnly),
+				Snapshots:   snapshots,
+			},
+		})
+		if err != nil {
+			logger.ErrorContext(ctx, "Unable to write player skill history", logging.Err(err))
+		}
+	}
+}
+
+func HandlerAPIV1PlayerGains(
+	logger *slog.Logger,
+	storageService services.StorageService,
+	worlds Worlds,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		world, ok := lookupAPIWorld(w, r, worlds)
+		if !ok {
+			return
+		}
+
+		period, dateRange, err := newGainsPeriodFromRequest(r)
+		if err != nil {
+			writeAPIError(w, http.StatusBadRequest, errorCodeBadRequest, err.Error())
+
+			return
+		}
+
+		player, err := lookupAPIPlayer(ctx, storageService, world, chi.URLParam(r, "username"))
+		if err != nil {
+			writeAPIPlayerError(ctx, logger, w, err)
+
+			return
+		}
+
+		gains, err := storageService.GetPlayerGains(ctx, services.GetPlayerGainsParams{
+			World:    world.Name,
+			Username: player.Username,
+			From:     dateRange.From,
+			To:       dateRange.To,
+		})
+		if err != nil {
+			logger.ErrorContext(ctx, "Unable to get player gains", logging.Err(err))
+			writeAPIError(
+				w,
+				http.StatusInternalServerError,
+				errorCodeInternal,
+				"unable to get player gains",
+			)
+
+			return
+		}
+
+		skills := make([]apiSkillGain, 0, len(gains))
+		for _, skill := range highscoreSkills {
+			gain, ok := gains[skill]
+			if !ok {
+				continue
+			}
+
+			skills = append(skills, apiSkillGain{
+				Skill: skill,
+				Gain:  newAPIGain(gain),
+			})
+		}
+
+		err = writeJSON(w, http.StatusOK, apiResponse[apiPlayerGains]{
+			Data: apiPlayerGains{
+				Username: player.Username,
+				Period:   period,
+				From:     dateRange.From.Format(time.DateOnly),
+				To:       dateRange.To.Format(time.DateOnly),
+				Skills:   skills,
+			},
+		})
+		if err != nil {
+			logger.ErrorContext(ctx, "Unable to write player gains", logging.Err(err))
+		}
+	}
+}
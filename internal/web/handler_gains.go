@@ -0,0 +1,80 @@
+package web
+
+import (
+	"html/template"
+	"io/fs"
+	"log/slog"
+	"net/http"
+
+	"github.com/cadyyan/void-tool/internal/logging"
+	"github.com/cadyyan/void-tool/internal/services"
+	"github.com/go-chi/chi/v5"
+)
+
+func HandlerGains(
+	logger *slog.Logger,
+	templateFS fs.FS,
+	storageService services.StorageService,
+	worlds Worlds,
+) http.HandlerFunc {
+	tmpl := template.Must(
+		template.New("gains.html").
+			Funcs(DefaultMacros).
+			ParseFS(templateFS, "templates/gains.html", "templates/world_switcher.html"),
+	)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		world, ok := worlds.lookup(r)
+		if !ok {
+			writeError(w, r, errUnknownWorld)
+
+			return
+		}
+
+		skill, ok := lookupSkill(chi.URLParam(r, "skill"))
+		if !ok {
+			writeError(w, r, errUnknownSkill)
+
+			return
+		}
+
+		period, dateRange, err := newGainsPeriodFromRequest(r)
+		if err != nil {
+			writeError(w, r, newBadRequestError(err))
+
+			return
+		}
+
+		pagination := newPaginationFromRequest(r, defaultPageSize)
+
+		gainers, err := storageService.GetTopGainers(ctx, services.GetTopGainersParams{
+			World:  world.Name,
+			Skill:  skill,
+			From:   dateRange.From,
+			To:     dateRange.To,
+			Limit:  pagination.PageSize,
+			Offset: pagination.Offset(),
+		})
+		if err != nil {
+			logger.ErrorContext(ctx, "Unable to get top gainers", logging.Err(err))
+			writeError(w, r, err)
+
+			return
+		}
+
+		templateData := map[string]any{
+			"Skill":      skill,
+			"Skills":     highscoreSkills,
+			"Period":     period,
+			"Periods":    gainsPeriods,
+			"DateRange":  dateRange,
+			"Gainers":    gainers,
+			"Pagination": pagination,
+			"HasMore":    len(gainers) == pagination.PageSize,
+			"World":      worlds.page(r, world),
+		}
+		renderPage(w, r, logger, tmpl, templateData)
+	}
+}
@@ -0,0 +1,62 @@
+package web
+
+import (
+	"html/template"
+	"io/fs"
+	"log/slog"
+	"net/http"
+
+	"github.com/cadyyan/void-tool/internal/logging"
+	"github.com/cadyyan/void-tool/internal/services"
+	"github.com/go-chi/chi/v5"
+)
+
+func HandlerHighscores(
+	logger *slog.Logger,
+	templateFS fs.FS,
+	storageService services.StorageService,
+	worlds Worlds,
+) http.HandlerFunc {
+	tmpl := template.Must(
+		template.New("highscores.html").
+			Funcs(DefaultMacros).
+			ParseFS(templateFS, "templates/highscores.html", "templates/world_switcher.html"),
+	)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		world, ok := worlds.lookup(r)
+		if !ok {
+			writeError(w, r, errUnknownWorld)
+
+			return
+		}
+
+		skill, ok := lookupHighscoreCategory(chi.URLParam(r, "skill"))
+		if !ok {
+			writeError(w, r, errUnknownHighscoreCategory)
+
+			return
+		}
+
+		pagination := newPaginationFromRequest(r, defaultPageSize)
+
+		highscores, err := getHighscoresPage(ctx, storageService, world.Name, skill, &pagination)
+		if err != nil {
+			logger.ErrorContext(ctx, "Unable to get highscores", logging.Err(err))
+			writeError(w, r, err)
+
+			return
+		}
+
+		templateData := map[string]any{
+			"Skill":      skill,
+			"Skills":     highscoreCategories,
+			"Highscores": highscores,
+			"Pagination": pagination,
+			"World":      worlds.page(r, world),
+		}
+		renderPage(w, r, logger, tmpl, templateData)
+	}
+}
@@ -1,88 +1,213 @@
 package web
 
 import (
+	"context"
+	"fmt"
 	"html/template"
 	"io/fs"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
 
 	"github.com/cadyyan/void-tool/internal/logging"
 	"github.com/cadyyan/void-tool/internal/services"
 )
 
+// playerIndexSort is how the player index on the home page is sorted. Columns are sorted
+// highest first the first time they're picked, apart from usernames which are sorted
+// alphabetically.
+type playerIndexSort struct {
+	Column     services.PlayerIndexSort
+	Descending bool
+}
+
+type playerIndexColumn struct {
+	Label      string
+	URL        string
+	Active     bool
+	Descending bool
+}
+
+var playerIndexColumnLabels = map[services.PlayerIndexSort]string{
+	services.PlayerIndexSortUsername:        "Player",
+	services.PlayerIndexSortTotalLevel:      "Total level",
+	services.PlayerIndexSortTotalExperience: "Total experience",
+	services.PlayerIndexSortCombatLevel:     "Combat",
+	services.PlayerIndexSortLastChanged:     "Last changed",
+}
+
+// newPlayerIndexSortFromRequest reads the sort and order query parameters. Unknown columns fall
+// back to sorting by username.
+func newPlayerIndexSortFromRequest(r *http.Request) playerIndexSort {
+	query := r.URL.Query()
+
+	column := services.PlayerIndexSort(query.Get("sort"))
+	if !slices.Contains(services.PlayerIndexSorts, column) {
+		column = services.PlayerIndexSortUsername
+	}
+
+	descending := column != services.PlayerIndexSortUsername
+
+	switch query.Get("order") {
+	case "asc":
+		descending = false
+	case "desc":
+		descending = true
+	}
+
+	return playerIndexSort{
+		Column:     column,
+		Descending: descending,
+	}
+}
+
+// Order is the value of the order query parameter.
+func (sort playerIndexSort) Order() string {
+	if sort.Descending {
+		return "desc"
+	}
+
+	return "asc"
+}
+
+// PageURL links to another page of the index sorted the same way.
+func (sort playerIndexSort) PageURL(page int) string {
+	return "?" + url.Values{
+		"sort":  {string(sort.Column)},
+		"order": {sort.Order()},
+		"page":  {strconv.Itoa(page)},
+	}.Encode()
+}
+
+// Columns are the headers of the index. Each one links to the index sorted by it, or sorted the
+// other way around if it's already sorted by it.
+func (sort playerIndexSort) Columns() []playerIndexColumn {
+	columns := make([]playerIndexColumn, len(services.PlayerIndexSorts))
+
+	for index, column := range services.PlayerIndexSorts {
+		next := playerIndexSort{
+			Column:     column,
+			Descending: column != services.PlayerIndexSortUsername,
+		}
+
+		active := column == sort.Column
+		if active {
+			next.Descending = !sort.Descending
+		}
+
+		columns[index] = playerIndexColumn{
+			Label:      playerIndexColumnLabels[column],
+			URL:        next.PageURL(1),
+			Active:     active,
+			Descending: sort.Descending,
+		}
+	}
+
+	return columns
+}
+
 func HandlerHome(
 	logger *slog.Logger,
 	templateFS fs.FS,
 	storageService services.StorageService,
+	worlds Worlds,
 ) http.HandlerFunc {
-	tmpl := template.Must(template.ParseFS(templateFS, "templates/home.html"))
+	tmpl := template.Must(
+		template.New("home.html").
+			Funcs(DefaultMacros).
+			ParseFS(templateFS, "templates/home.html", "templates/world_switcher.html"),
+	)
 
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
-		logger.DebugContext(ctx, "Getting all players")
-
-		players, err := storageService.GetAllPlayers(ctx)
-		if err != nil {
-			logger.ErrorContext(ctx, "Unable to get a list of all players", logging.Err(err))
-			w.WriteHeader(http.StatusInternalServerError)
+		world, ok := worlds.lookup(r)
+		if !ok {
+			writeError(w, r, errUnknownWorld)
 
 			return
 		}
-		logger.DebugContext(ctx, "Found all users")
 
-		logger.DebugContext(ctx, "Getting skills for each player")
-		playerSkills := make(map[string]map[string]services.PlayerSkillRecord)
-		for _, player := range players {
-			skills, err := storageService.GetPlayerSkills(ctx, player.Username)
+		query := r.URL.Query().Get("q")
+		sort := newPlayerIndexSortFromRequest(r)
+		pagination := newPaginationFromRequest(r, defaultPageSize)
+
+		var (
+			players []services.Player
+			index   []services.PlayerIndexRecord
+			err     error
+		)
+
+		if query != "" {
+			players, err = storageService.SearchPlayers(ctx, services.SearchPlayersParams{
+				World: world.Name,
+				Query: query,
+				Limit: maxSearchLimit,
+			})
 			if err != nil {
-				logger.ErrorContext(ctx, "Unable to get player stats", logging.Err(err))
-				w.WriteHeader(http.StatusInternalServerError)
+				logger.ErrorContext(ctx, "Unable to search players", logging.Err(err))
+				writeError(w, r, err)
 
 				return
 			}
 
-			playerSkills[player.Username] = skills
-		}
-		logger.DebugContext(ctx, "Get all player skills")
+			// Searching for someone's whole name goes straight to their page
+			if len(players) > 0 &&
+				services.NormalizeUsername(players[0].Username) == services.NormalizeUsername(query) {
+				http.Redirect(w, r, worlds.playerPath(world.Name, players[0].Username), http.StatusFound)
+
+				return
+			}
+		} else {
+			index, err = getPlayerIndexPage(ctx, storageService, world.Name, sort, &pagination)
+			if err != nil {
+				logger.ErrorContext(ctx, "Unable to get player index", logging.Err(err))
+				writeError(w, r, err)
 
-		w.WriteHeader(http.StatusOK)
+				return
+			}
+		}
 
 		templateData := map[string]any{
-			"Players":      players,
-			"PlayerSkills": playerSkills,
-			"SkillOrder":   skillOrder,
-		}
-		if err := tmpl.Execute(w, templateData); err != nil {
-			panic(err) // TODO: better handling
+			"Query":           query,
+			"Players":         players,
+			"Index":           index,
+			"Sort":            sort,
+			"Pagination":      pagination,
+			"World":           worlds.page(r, world),
+			"SearchLimit":     services.DefaultSearchLimit,
+			"SearchMinLength": minSearchQueryLength,
 		}
+		renderPage(w, r, logger, tmpl, templateData)
 	}
 }
 
-var skillOrder = []string{
-	"Attack",
-	"Defence",
-	"Strength",
-	"Constitution",
-	"Ranged",
-	"Prayer",
-	"Magic",
-	"Cooking",
-	"Woodcutting",
-	"Fletching",
-	"Fishing",
-	"Firemaking",
-	"Crafting",
-	"Smithing",
-	"Mining",
-	"Herblore",
-	"Agility",
-	"Thieving",
-	"Slayer",
-	"Farming",
-	"Runecrafting",
-	"Hunter",
-	"Construction",
-	"Summoning",
-	"Dungeoneering",
+func getPlayerIndexPage(
+	ctx context.Context,
+	storageService services.StorageService,
+	world string,
+	sort playerIndexSort,
+	pagination *Pagination,
+) ([]services.PlayerIndexRecord, error) {
+	total, err := storageService.CountPlayers(ctx, world)
+	if err != nil {
+		return nil, fmt.Errorf("unable to count players: %w", err)
+	}
+
+	pagination.TotalItems = total
+
+	index, err := storageService.GetPlayerIndex(ctx, services.GetPlayerIndexParams{
+		World:      world,
+		Sort:       sort.Column,
+		Descending: sort.Descending,
+		Limit:      pagination.PageSize,
+		Offset:     pagination.Offset(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to get player index: %w", err)
+	}
+
+	return index, nil
 }
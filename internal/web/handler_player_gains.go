@@ -0,0 +1,92 @@
+package web
+
+import (
+	"errors"
+	"html/template"
+	"io/fs"
+	"log/slog"
+	"net/http"
+
+	"github.com/cadyyan/void-tool/internal/logging"
+	"github.com/cadyyan/void-tool/internal/services"
+	"github.com/go-chi/chi/v5"
+)
+
+func HandlerPlayerGains(
+	logger *slog.Logger,
+	templateFS fs.FS,
+	storageService services.StorageService,
+	worlds Worlds,
+) http.HandlerFunc {
+	tmpl := template.Must(
+		template.New("player_gains.html").
+			Funcs(DefaultMacros).
+			ParseFS(templateFS, "templates/player_gains.html", "templates/world_switcher.html"),
+	)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		world, ok := worlds.lookup(r)
+		if !ok {
+			writeError(w, r, errUnknownWorld)
+
+			return
+		}
+
+		period, dateRange, err := newGainsPeriodFromRequest(r)
+		if err != nil {
+			writeError(w, r, newBadRequestError(err))
+
+			return
+		}
+
+		username := chi.URLParam(r, "username")
+
+		player, redirected, err := lookupPlayer(
+			w,
+			r,
+			storageService,
+			worlds,
+			world,
+			username,
+			"/gains",
+		)
+		if redirected {
+			return
+		} else if err != nil {
+			if !errors.Is(err, services.ErrNotFound) {
+				logger.ErrorContext(ctx, "Unable to get user", logging.Err(err))
+			}
+
+			writeError(w, r, err)
+
+			return
+		}
+
+		gains, err := storageService.GetPlayerGains(ctx, services.GetPlayerGainsParams{
+			World:    world.Name,
+			Username: player.Username,
+			From:     dateRange.From,
+			To:       dateRange.To,
+		})
+		if err != nil {
+			logger.ErrorContext(ctx, "Unable to get user gains", logging.Err(err))
+			writeError(w, r, err)
+
+			return
+		}
+
+		templateData := map[string]any{
+			"Player":     player,
+			"Gains":      gains,
+			"SkillOrder": services.SkillOrder,
+			"Overall":    gains[services.SkillOverall],
+			"Period":     period,
+			"Periods":    gainsPeriods,
+			"DateRange":  dateRange,
+			"World":      worlds.page(r, world),
+		}
+		renderPage(w, r, logger, tmpl, templateData)
+	}
+}
@@ -1,6 +1,7 @@
 package web
 
 import (
+	"errors"
 	"html/template"
 	"io/fs"
 	"log/slog"
@@ -15,48 +16,97 @@ func HandlerPlayerPage(
 	logger *slog.Logger,
 	templateFS fs.FS,
 	storageService services.StorageService,
+	worlds Worlds,
 ) http.HandlerFunc {
 	tmpl := template.Must(
 		template.New("player_page.html").
 			Funcs(DefaultMacros).
-			ParseFS(templateFS, "templates/player_page.html"),
+			ParseFS(templateFS, "templates/player_page.html", "templates/world_switcher.html"),
 	)
 
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
+		world, ok := worlds.lookup(r)
+		if !ok {
+			writeError(w, r, errUnknownWorld)
+
+			return
+		}
+
 		username := chi.URLParam(r, "username")
 		if username == "" {
-			// TODO: proper 404 page
-			w.WriteHeader(http.StatusNotFound)
-			w.Write([]byte("Username not provided"))
+			writeError(w, r, errPageNotFound)
 
 			return
 		}
 
-		player, err := storageService.GetPlayerByUsername(ctx, username)
-		if err != nil {
-			logger.ErrorContext(ctx, "Unable to get user", logging.Err(err))
+		player, redirected, err := lookupPlayer(w, r, storageService, worlds, world, username, "")
+		if redirected {
+			return
+		} else if err != nil {
+			if !errors.Is(err, services.ErrNotFound) {
+				logger.ErrorContext(ctx, "Unable to get user", logging.Err(err))
+			}
 
-			// TODO: proper error handling
-			w.WriteHeader(http.StatusNotFound)
-			w.Write([]byte("Unable to get user by given username"))
+			writeError(w, r, err)
 
 			return
 		}
 
-		skills, err := storageService.GetPlayerSkills(ctx, player.Username)
+		skills, err := storageService.GetPlayerSkills(ctx, services.GetPlayerSkillsParams{
+			World:    world.Name,
+			Username: player.Username,
+		})
 		if err != nil {
 			logger.ErrorContext(ctx, "Unable to get user skills", logging.Err(err))
+			writeError(w, r, err)
 
-			// TODO: proper error handling
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte("Unable to get user skills"))
+			return
+		}
+
+		chartSkill, ok := lookupSkill(r.URL.Query().Get("chart"))
+		if !ok {
+			chartSkill = services.SkillOverall
+		}
+
+		dateRange, err := newDateRangeFromRequest(r, defaultHistoryDays)
+		if err != nil {
+			dateRange = newDefaultDateRange(defaultHistoryDays)
+		}
+
+		history, err := storageService.GetPlayerSkillHistory(
+			ctx,
+			services.GetPlayerSkillHistoryParams{
+				World:       world.Name,
+				Username:    player.Username,
+				Skill:       chartSkill,
+				From:        dateRange.From,
+				To:          dateRange.To,
+				Granularity: services.HistoryGranularityDay,
+			},
+		)
+		if err != nil {
+			logger.ErrorContext(ctx, "Unable to get user skill history", logging.Err(err))
+			writeError(w, r, err)
 
 			return
 		}
 
-		// TODO: combat level
+		// The save file is only used to show what the player is holding so the page still works
+		// without it
+		save, err := world.Players.GetPlayer(ctx, player.Username)
+		saveAvailable := err == nil
+
+		if err != nil {
+			logger.WarnContext(ctx, "Unable to get user save", logging.Err(err))
+		}
+
+		// Previous names are only extra information so the page still works without them
+		previousNames, err := storageService.GetPlayerPreviousNames(ctx, player.ID)
+		if err != nil {
+			logger.WarnContext(ctx, "Unable to get previous user names", logging.Err(err))
+		}
 
 		totalExperience := 0.0
 		totalLevel := 0
@@ -65,17 +115,21 @@ func HandlerPlayerPage(
 			totalLevel += skill.Level
 		}
 
-		w.WriteHeader(http.StatusOK)
-
 		templateData := map[string]any{
 			"Player":          player,
+			"PreviousNames":   previousNames,
 			"Skills":          skills,
-			"SkillOrder":      skillOrder,
+			"SkillOrder":      services.SkillOrder,
 			"TotalExperience": totalExperience,
 			"TotalLevel":      totalLevel,
+			"CombatLevel":     combatLevel(skills),
+			"SaveAvailable":   saveAvailable,
+			"Save":            save,
+			"ChartSkill":      chartSkill,
+			"ChartSkills":     highscoreSkills,
+			"Chart":           newExperienceChart(history, dateRange),
+			"World":           worlds.page(r, world),
 		}
-		if err := tmpl.Execute(w, templateData); err != nil {
-			panic(err) // TODO: better handling
-		}
+		renderPage(w, r, logger, tmpl, templateData)
 	}
 }
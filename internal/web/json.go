@@ -0,0 +1,26 @@
+package web
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type jsonError struct {
+	Error string `json:"error"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		return fmt.Errorf("unable to encode JSON response: %w", err)
+	}
+
+	return nil
+}
+
+func writeJSONError(w http.ResponseWriter, status int, message string) {
+	_ = writeJSON(w, status, jsonError{Error: message})
+}
@@ -2,6 +2,8 @@ package web
 
 import (
 	"html/template"
+	"strings"
+	"time"
 
 	"golang.org/x/text/message"
 )
@@ -18,7 +20,53 @@ func FormatFloat(value float64) string {
 	return printer.Sprintf("%0.2f", value)
 }
 
+func FormatDate(value time.Time) string {
+	return value.Format(time.DateOnly)
+}
+
+func FormatTime(value time.Time) string {
+	return value.Format(time.RFC3339)
+}
+
+// FormatItemName turns an item ID (e.g. "rune_full_helm") into something readable.
+func FormatItemName(id string) string {
+	name := strings.ReplaceAll(id, "_", " ")
+	if name == "" {
+		return name
+	}
+
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+var equipmentSlotNames = map[int]string{
+	0:  "Head",
+	1:  "Cape",
+	2:  "Neck",
+	3:  "Weapon",
+	4:  "Body",
+	5:  "Shield",
+	7:  "Legs",
+	9:  "Hands",
+	10: "Feet",
+	12: "Ring",
+	13: "Ammo",
+}
+
+// FormatEquipmentSlot names a worn equipment slot.
+func FormatEquipmentSlot(slot int) string {
+	if name, ok := equipmentSlotNames[slot]; ok {
+		return name
+	}
+
+	return FormatInt(slot)
+}
+
 var DefaultMacros = template.FuncMap{
 	"FmtInt":   FormatInt,
 	"FmtFloat": FormatFloat,
+	"FmtDate":  FormatDate,
+	"FmtTime":  FormatTime,
+	"FmtItem":  FormatItemName,
+	"FmtSlot":  FormatEquipmentSlot,
+	"Lower":    strings.ToLower,
 }
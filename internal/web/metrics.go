@@ -0,0 +1,53 @@
+package web
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cadyyan/void-tool/internal/metrics"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// unmatchedRoute labels requests that didn't match any route so bots probing for random paths
+// can't create new series.
+const unmatchedRoute = "unmatched"
+
+// observeRequests counts and times requests by the route that served them. The route is only
+// known once the request has been routed so it's read after the request has been served.
+func observeRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		next.ServeHTTP(recorder, r)
+
+		route := requestRoute(r)
+		status := responseStatus(recorder)
+
+		metrics.HTTPRequests.WithLabelValues(r.Method, route, strconv.Itoa(status)).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(r.Method, route).Observe(time.Since(start).Seconds())
+	})
+}
+
+// requestRoute is the pattern of the route that served a request, like /player/{username}.
+func requestRoute(r *http.Request) string {
+	if routeContext := chi.RouteContext(r.Context()); routeContext != nil {
+		if pattern := routeContext.RoutePattern(); pattern != "" {
+			return pattern
+		}
+	}
+
+	return unmatchedRoute
+}
+
+// responseStatus is the status a request was served with. Handlers that only write a body are
+// served with 200.
+func responseStatus(recorder middleware.WrapResponseWriter) int {
+	if status := recorder.Status(); status != 0 {
+		return status
+	}
+
+	return http.StatusOK
+}
@@ -0,0 +1,167 @@
+package web
+
+import (
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// openAPIDocument is the part of OpenAPI 3.1 the API uses to describe itself. It's built from
+// the API's operations and response types so it can't drift from what the API actually does.
+type openAPIDocument struct {
+	OpenAPI    string                                 `json:"openapi"`
+	Info       openAPIInfo                            `json:"info"`
+	Paths      map[string]map[string]openAPIOperation `json:"paths"`
+	Components openAPIComponents                      `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type openAPIComponents struct {
+	Schemas map[string]*openAPISchema `json:"schemas"`
+}
+
+type openAPIOperation struct {
+	OperationID string                     `json:"operationId"`
+	Summary     string                     `json:"summary"`
+	Tags        []string                   `json:"tags,omitempty"`
+	Parameters  []openAPIParameter         `json:"parameters,omitempty"`
+	Responses   map[string]openAPIResponse `json:"responses"`
+}
+
+type openAPIParameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required,omitempty"`
+	Schema      *openAPISchema `json:"schema"`
+}
+
+type openAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]openAPIMediaType `json:"content,omitempty"`
+}
+
+type openAPIMediaType struct {
+	Schema *openAPISchema `json:"schema"`
+}
+
+type openAPISchema struct {
+	Ref         string                    `json:"$ref,omitempty"`
+	Type        any                       `json:"type,omitempty"`
+	Format      string                    `json:"format,omitempty"`
+	Description string                    `json:"description,omitempty"`
+	Enum        []string                  `json:"enum,omitempty"`
+	Default     any                       `json:"default,omitempty"`
+	Minimum     *int                      `json:"minimum,omitempty"`
+	Maximum     *int                      `json:"maximum,omitempty"`
+	Items       *openAPISchema            `json:"items,omitempty"`
+	Properties  map[string]*openAPISchema `json:"properties,omitempty"`
+	Required    []string                  `json:"required,omitempty"`
+	AnyOf       []*openAPISchema          `json:"anyOf,omitempty"`
+}
+
+// openAPISchemas collects the schemas of named types as they're described so each one is only
+// written out once under #/components/schemas.
+type openAPISchemas map[string]*openAPISchema
+
+// schemaOf describes how a Go value is encoded as JSON. Struct fields can have a format tag,
+// like format:"date", for strings in a particular format.
+func (schemas openAPISchemas) schemaOf(valueType reflect.Type) *openAPISchema {
+	if valueType == reflect.TypeFor[time.Time]() {
+		return &openAPISchema{Type: "string", Format: "date-time"}
+	}
+
+	switch valueType.Kind() { //nolint:exhaustive // Only the kinds used in responses are described
+	case reflect.Pointer:
+		schema := schemas.schemaOf(valueType.Elem())
+		if schema.Ref != "" {
+			return &openAPISchema{AnyOf: []*openAPISchema{schema, {Type: "null"}}}
+		}
+
+		schema.Type = []any{schema.Type, "null"}
+
+		return schema
+	case reflect.Bool:
+		return &openAPISchema{Type: "boolean"}
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return &openAPISchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &openAPISchema{Type: "number"}
+	case reflect.String:
+		return &openAPISchema{Type: "string"}
+	case reflect.Slice:
+		return &openAPISchema{Type: "array", Items: schemas.schemaOf(valueType.Elem())}
+	case reflect.Struct:
+		return schemas.structSchema(valueType)
+	default:
+		return &openAPISchema{}
+	}
+}
+
+func (schemas openAPISchemas) structSchema(structType reflect.Type) *openAPISchema {
+	// Generic types, like pages of results, are written inline since their names can't be
+	// used as schema names
+	name := openAPISchemaName(structType)
+	if name != "" {
+		if _, ok := schemas[name]; ok {
+			return &openAPISchema{Ref: "#/components/schemas/" + name}
+		}
+
+		// Reserve the name first in case the type refers to itself
+		schemas[name] = nil
+	}
+
+	schema := &openAPISchema{Type: "object", Properties: make(map[string]*openAPISchema)}
+
+	for index := range structType.NumField() {
+		field := structType.Field(index)
+
+		jsonName, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if jsonName == "-" || !field.IsExported() {
+			continue
+		}
+
+		if jsonName == "" {
+			jsonName = field.Name
+		}
+
+		property := schemas.schemaOf(field.Type)
+		if format := field.Tag.Get("format"); format != "" {
+			property.Format = format
+		}
+
+		schema.Properties[jsonName] = property
+
+		if !strings.Contains(options, "omitempty") {
+			schema.Required = append(schema.Required, jsonName)
+		}
+	}
+
+	if name == "" {
+		return schema
+	}
+
+	schemas[name] = schema
+
+	return &openAPISchema{Ref: "#/components/schemas/" + name}
+}
+
+// openAPISchemaName names the schema of an API type, e.g. apiPlayer is described as Player.
+func openAPISchemaName(structType reflect.Type) string {
+	name := structType.Name()
+	if name == "" || strings.Contains(name, "[") {
+		return ""
+	}
+
+	name = strings.TrimPrefix(name, "api")
+	runes := []rune(name)
+	runes[0] = unicode.ToUpper(runes[0])
+
+	return string(runes)
+}
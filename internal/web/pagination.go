@@ -0,0 +1,66 @@
+package web
+
+import (
+	"net/http"
+	"strconv"
+)
+
+const (
+	defaultPageSize = 25
+	maxPageSize     = 100
+)
+
+type Pagination struct {
+	Page       int
+	PageSize   int
+	TotalItems int
+}
+
+// newPaginationFromRequest reads the page number and page size from the query string. Missing
+// or invalid values fall back to the first page and the given default page size.
+func newPaginationFromRequest(r *http.Request, pageSize int) Pagination {
+	query := r.URL.Query()
+
+	page, err := strconv.Atoi(query.Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	if requested, err := strconv.Atoi(query.Get("pageSize")); err == nil && requested > 0 {
+		pageSize = min(requested, maxPageSize)
+	}
+
+	return Pagination{
+		Page:       page,
+		PageSize:   pageSize,
+		TotalItems: 0,
+	}
+}
+
+func (pagination Pagination) Offset() int {
+	return (pagination.Page - 1) * pagination.PageSize
+}
+
+func (pagination Pagination) TotalPages() int {
+	if pagination.TotalItems == 0 {
+		return 1
+	}
+
+	return (pagination.TotalItems + pagination.PageSize - 1) / pagination.PageSize
+}
+
+func (pagination Pagination) HasPrevious() bool {
+	return pagination.Page > 1
+}
+
+func (pagination Pagination) HasNext() bool {
+	return pagination.Page < pagination.TotalPages()
+}
+
+func (pagination Pagination) PreviousPage() int {
+	return pagination.Page - 1
+}
+
+func (pagination Pagination) NextPage() int {
+	return pagination.Page + 1
+}
@@ -0,0 +1,81 @@
+package web
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/cadyyan/void-tool/internal/services"
+)
+
+// lookupPlayer finds the player a page is for. Names are matched the way players type them so
+// visitors using another spelling of a name, or a name the player used to have, are redirected
+// to the same page under the player's current name. It reports whether the visitor was
+// redirected.
+func lookupPlayer(
+	w http.ResponseWriter,
+	r *http.Request,
+	storageService services.StorageService,
+	worlds Worlds,
+	world services.VoidWorld,
+	username string,
+	page string,
+) (services.Player, bool, error) {
+	ctx := r.Context()
+
+	player, err := storageService.GetPlayerByUsername(ctx, services.GetPlayerByUsernameParams{
+		World:    world.Name,
+		Username: username,
+	})
+	if errors.Is(err, services.ErrPlayerNotFound) {
+		renamed, renamedErr := storageService.GetPlayerByPreviousUsername(
+			ctx,
+			services.GetPlayerByUsernameParams{
+				World:    world.Name,
+				Username: username,
+			},
+		)
+		if errors.Is(renamedErr, services.ErrPlayerNotFound) {
+			return services.Player{}, false, err
+		} else if renamedErr != nil {
+			return services.Player{}, false, renamedErr
+		}
+
+		// Old names can be taken by someone else later so the redirect isn't permanent
+		redirectToPlayer(w, r, worlds, world, renamed, page, http.StatusFound)
+
+		return renamed, true, nil
+	} else if err != nil {
+		return services.Player{}, false, err
+	}
+
+	if player.Username != username {
+		redirectToPlayer(w, r, worlds, world, player, page, http.StatusMovedPermanently)
+
+		return player, true, nil
+	}
+
+	return player, false, nil
+}
+
+func redirectToPlayer(
+	w http.ResponseWriter,
+	r *http.Request,
+	worlds Worlds,
+	world services.VoidWorld,
+	player services.Player,
+	page string,
+	status int,
+) {
+	location := worlds.playerPath(world.Name, player.Username) + page
+	if r.URL.RawQuery != "" {
+		location += "?" + r.URL.RawQuery
+	}
+
+	http.Redirect(w, r, location, status)
+}
+
+// playerPath is the path of a player's page.
+func (worlds Worlds) playerPath(world string, username string) string {
+	return worlds.path(world) + "/player/" + url.PathEscape(username)
+}
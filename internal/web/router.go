@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/cadyyan/void-tool/internal/configuration"
+	"github.com/cadyyan/void-tool/internal/metrics"
 	"github.com/cadyyan/void-tool/internal/services"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -22,13 +23,18 @@ func NewRouter(
 	logger *slog.Logger,
 	config configuration.Configuration,
 	storageService services.StorageService,
+	worlds Worlds,
 ) *chi.Mux {
 	router := chi.NewRouter()
 
 	router.Use(
 		// The logger needs to be the first middleware
 		httplog.RequestLogger(config.Logging.BuildAccessLogger()),
-		middleware.Recoverer,
+		// Panics are recovered first so they're counted and traced as the errors they're
+		// served as
+		traceRequests,
+		observeRequests,
+		recoverer(logger),
 		middleware.RealIP,
 		middleware.RedirectSlashes,
 		middleware.CleanPath,
@@ -38,18 +44,84 @@ func NewRouter(
 
 	// TODO: rate limits
 
+	router.Handle("/metrics", metrics.Handler())
+
 	router.Get("/api/healthcheck", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write([]byte("OK"))
 	})
 
-	router.Get("/", HandlerHome(logger, templateFS, storageService))
-	router.Get("/player/{username}", HandlerPlayerPage(logger, templateFS, storageService))
+	router.Get("/api/highscores/{skill}", HandlerAPIHighscores(logger, storageService, worlds))
+	router.Get("/api/players/search", HandlerAPIPlayerSearch(logger, storageService, worlds))
+	router.Get(
+		"/api/players/{username}/skills/{skill}/history",
+		HandlerAPIPlayerHistory(logger, storageService, worlds),
+	)
+	router.Get(
+		"/api/players/{username}/gains",
+		HandlerAPIPlayerGains(logger, storageService, worlds),
+	)
+	router.Get("/api/gains/{skill}", HandlerAPIGains(logger, storageService, worlds))
+	router.Route("/api/v1", func(router chi.Router) {
+		apiV1Routes(router, logger, storageService, worlds)
+	})
+
+	// The default world is served at the root and every world, the default one included, under
+	// /worlds/{world}
+	router.Group(func(router chi.Router) {
+		worldRoutes(router, logger, storageService, worlds)
+	})
+	router.Route("/worlds/{world}", func(router chi.Router) {
+		worldRoutes(router, logger, storageService, worlds)
+	})
+
+	if config.Admin.Enabled() {
+		router.Group(func(router chi.Router) {
+			router.Use(middleware.BasicAuth("void-tool admin", map[string]string{
+				config.Admin.Username: config.Admin.Password,
+			}))
+
+			router.Get("/admin/ingestion", HandlerAdminIngestion(logger, templateFS, storageService))
+			router.Get("/api/admin/ingestion", HandlerAPIAdminIngestion(logger, storageService))
+		})
+	}
 
 	router.Handle(
 		"/assets/*",
 		http.FileServer(http.FS(assetsFS)),
 	)
 
+	router.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, r, errPageNotFound)
+	})
+	router.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, r, errMethodNotAllowed)
+	})
+
 	return router
 }
+
+// worldRoutes are the pages that show a single world.
+func worldRoutes(
+	router chi.Router,
+	logger *slog.Logger,
+	storageService services.StorageService,
+	worlds Worlds,
+) {
+	router.Get("/", HandlerHome(logger, templateFS, storageService, worlds))
+	router.Get(
+		"/player/{username}",
+		HandlerPlayerPage(logger, templateFS, storageService, worlds),
+	)
+	router.Get("/highscores", worlds.redirect("/highscores/overall"))
+	router.Get(
+		"/highscores/{skill}",
+		HandlerHighscores(logger, templateFS, storageService, worlds),
+	)
+	router.Get("/gains", worlds.redirect("/gains/overall"))
+	router.Get("/gains/{skill}", HandlerGains(logger, templateFS, storageService, worlds))
+	router.Get(
+		"/player/{username}/gains",
+		HandlerPlayerGains(logger, templateFS, storageService, worlds),
+	)
+}
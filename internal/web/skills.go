@@ -0,0 +1,48 @@
+package web
+
+import (
+	"strings"
+
+	"github.com/cadyyan/void-tool/internal/combat"
+	"github.com/cadyyan/void-tool/internal/services"
+)
+
+// highscoreSkills are all of the skills that have history, including the overall totals.
+var highscoreSkills = append([]string{services.SkillOverall}, services.SkillOrder...)
+
+// highscoreCategories are all of the categories a player can be ranked in.
+var highscoreCategories = append(
+	[]string{services.SkillOverall, services.SkillCombat},
+	services.SkillOrder...,
+)
+
+// lookupSkill finds the canonical name of a skill from a (case-insensitive) name given in a URL.
+func lookupSkill(name string) (string, bool) {
+	return lookupName(highscoreSkills, name)
+}
+
+// lookupHighscoreCategory finds the canonical name of a highscore category from a
+// (case-insensitive) name given in a URL.
+func lookupHighscoreCategory(name string) (string, bool) {
+	return lookupName(highscoreCategories, name)
+}
+
+func lookupName(names []string, name string) (string, bool) {
+	for _, candidate := range names {
+		if strings.EqualFold(candidate, name) {
+			return candidate, true
+		}
+	}
+
+	return "", false
+}
+
+// combatLevel calculates the combat level for a player's latest skills.
+func combatLevel(skills map[string]services.PlayerSkillRecord) int {
+	levels := make(map[string]int, len(skills))
+	for name, skill := range skills {
+		levels[name] = skill.Level
+	}
+
+	return combat.Level(levels)
+}
@@ -0,0 +1,47 @@
+package web
+
+import (
+	"net/http"
+
+	"github.com/cadyyan/void-tool/internal/tracing"
+	"github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// traceRequests starts a span for every request, continuing the caller's trace when it sent
+// one. Spans are named after the route that served them once it's known.
+func traceRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+		ctx, span := tracing.Start(
+			ctx,
+			r.Method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(r.Method),
+				semconv.URLPath(r.URL.Path),
+			),
+		)
+		defer span.End()
+
+		recorder := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+
+		route := requestRoute(r)
+		status := responseStatus(recorder)
+
+		span.SetName(r.Method + " " + route)
+		span.SetAttributes(semconv.HTTPRoute(route), semconv.HTTPResponseStatusCode(status))
+
+		// Client errors are the client's problem so only server errors fail the span
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	})
+}
@@ -0,0 +1,126 @@
+package web
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/cadyyan/void-tool/internal/services"
+	"github.com/go-chi/chi/v5"
+)
+
+// Worlds are the game worlds the site serves. The first one is the default world and its pages
+// are at the root of the site, the rest are under /worlds/{world}. The API picks a world with
+// the world query parameter instead.
+type Worlds []services.VoidWorld
+
+// worldPage is the world a page is showing. Path is prepended to every link on the page so
+// visitors stay in the same world.
+type worldPage struct {
+	Name  string
+	Path  string
+	Home  string
+	Links []worldLink
+}
+
+type worldLink struct {
+	Name   string
+	Path   string
+	Active bool
+}
+
+// lookup finds the world a request is for. It's false when the request names a world that
+// doesn't exist.
+func (worlds Worlds) lookup(r *http.Request) (services.VoidWorld, bool) {
+	name := chi.URLParam(r, "world")
+	if name == "" {
+		name = r.URL.Query().Get("world")
+	}
+
+	if name == "" {
+		return worlds[0], true
+	}
+
+	for _, world := range worlds {
+		if world.Name == name {
+			return world, true
+		}
+	}
+
+	return services.VoidWorld{}, false
+}
+
+// path is the prefix of every page in a world. It's empty for the default world so links can
+// always be written as path + "/page".
+func (worlds Worlds) path(world string) string {
+	if world == worlds[0].Name {
+		return ""
+	}
+
+	return "/worlds/" + world
+}
+
+// homePath is the path of a world's home page.
+func (worlds Worlds) homePath(world string) string {
+	if path := worlds.path(world); path != "" {
+		return path
+	}
+
+	return "/"
+}
+
+// links points at the page being viewed in every world so visitors can switch between them.
+// There aren't any links when there's only one world.
+func (worlds Worlds) links(r *http.Request, current services.VoidWorld) []worldLink {
+	if len(worlds) == 1 {
+		return nil
+	}
+
+	page := r.URL.Path
+	if rest, ok := strings.CutPrefix(page, "/worlds/"+current.Name); ok {
+		page = rest
+	}
+
+	if page == "/" {
+		page = ""
+	}
+
+	links := make([]worldLink, len(worlds))
+	for index, world := range worlds {
+		path := worlds.path(world.Name) + page
+		if path == "" {
+			path = "/"
+		}
+
+		links[index] = worldLink{
+			Name:   world.Name,
+			Path:   path,
+			Active: world.Name == current.Name,
+		}
+	}
+
+	return links
+}
+
+// page is what every page needs to know about the world it's showing.
+func (worlds Worlds) page(r *http.Request, world services.VoidWorld) worldPage {
+	return worldPage{
+		Name:  world.Name,
+		Path:  worlds.path(world.Name),
+		Home:  worlds.homePath(world.Name),
+		Links: worlds.links(r, world),
+	}
+}
+
+// redirect sends visitors to a page in the world they're browsing.
+func (worlds Worlds) redirect(page string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		world, ok := worlds.lookup(r)
+		if !ok {
+			writeError(w, r, errUnknownWorld)
+
+			return
+		}
+
+		http.Redirect(w, r, worlds.path(world.Name)+page, http.StatusFound)
+	}
+}